@@ -1,14 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"gin-template/internal/app/config"
 	"gin-template/internal/app/database"
+	"gin-template/internal/app/i18n"
 	"gin-template/internal/app/middleware"
+	"gin-template/internal/app/module"
+	"gin-template/internal/app/query"
 	"gin-template/internal/app/routes"
+	"gin-template/internal/app/tenant"
+	"gin-template/internal/audit"
+	"gin-template/internal/demo"
 	"gin-template/internal/utils"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
+	_ "time/tzdata" // 内嵌时区数据库，运行环境缺少时区数据时 time_zone 配置仍可使用 IANA 时区名称
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -24,14 +37,56 @@ func main() {
 		log.Fatalf("服务器启动失败: %v", err)
 	}
 
-	// 初始化数据库
-	db, err := database.NewDatabase(cfg.Database)
+	// 初始化多语言消息目录
+	if err := i18n.Init(cfg.I18n); err != nil {
+		log.Fatalf("初始化多语言消息目录失败: %v", err)
+	}
+
+	// 列表过滤中只有日期的时间取值按默认数据库连接的存储时区解析
+	loc, err := time.LoadLocation(cfg.Databases[config.DefaultDatabase].TimeZone)
 	if err != nil {
+		log.Fatalf("无效的时区配置: %v", err)
+	}
+	query.SetLocation(loc)
+
+	// 初始化数据库：按配置打开所有命名连接，每个连接按 connect 配置重试，延迟连接模式下在后台重连
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel() // 程序退出时停止后台重连
+	dbManager := database.NewManager(cfg.Databases)
+	if err := dbManager.Open(ctx); err != nil {
 		log.Fatalf("初始化数据库失败: %v", err)
 	}
 
-	sqlDB, _ := db.DB() // 获取底层的 SQL 数据库连接
-	defer sqlDB.Close() // 确保程序退出时关闭数据库连接，释放资源
+	defer dbManager.Close() // 确保程序退出时关闭所有数据库连接（包括只读副本），释放资源
+
+	// 启用多租户隔离：注册 GORM 租户插件，database 模式下按租户路由连接
+	if cfg.Tenant.Enabled {
+		if err := tenant.Install(dbManager, cfg.Tenant); err != nil {
+			log.Fatalf("初始化多租户隔离失败: %v", err)
+		}
+	}
+
+	// 初始化业务模块：按依赖顺序初始化启用的模块（包括审计日志、历史版本等数据变更记录插件）
+	// 新增模块时在此注册，modules.enabled 配置可停用模块
+	modules := module.NewRegistry(
+		audit.NewModule(),
+		demo.NewModule(),
+	)
+	if err := modules.Init(ctx, &module.Deps{Config: cfg, DBManager: dbManager}); err != nil {
+		log.Fatalf("初始化业务模块失败: %v", err)
+	}
+	if cfg.Modules.AutoMigrate {
+		// 迁移默认连接及 database 隔离模式下各租户的连接，跨租户访问以免迁移语句被租户隔离拦截
+		for _, name := range cfg.MigrationDatabases() {
+			db, err := dbManager.DB(name)
+			if err != nil {
+				log.Fatalf("自动迁移表结构失败: %v", err)
+			}
+			if err := modules.Migrate(tenant.Elevate(ctx), db); err != nil {
+				log.Fatalf("自动迁移数据库 '%s' 的表结构失败: %v", name, err)
+			}
+		}
+	}
 
 	// 设置Gin模式
 	// 生成环境设置为发布模式，发布模式的主要特性：
@@ -51,13 +106,31 @@ func main() {
 	router.Use(middleware.Logger())
 	router.Use(middleware.Recovery())
 	router.Use(middleware.RequestIdInject())
+	router.Use(middleware.Locale(cfg.I18n, i18n.Default()))
 
-	// 初始化依赖及注册路由
-	routes.SetupRoutes(cfg, router, db)
+	// 注册基础路由及各业务模块的路由
+	routes.SetupRoutes(cfg, router, dbManager, modules)
 
 	// 启动服务器
-	logrus.Infof("服务器运行在端口 %d", PORT)
-	if err := http.ListenAndServe(":"+strconv.Itoa(PORT), router); err != nil {
-		logrus.Fatalf("服务器启动失败: %v", err)
+	server := &http.Server{Addr: ":" + strconv.Itoa(PORT), Handler: router}
+	go func() {
+		logrus.Infof("服务器运行在端口 %d", PORT)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.Fatalf("服务器启动失败: %v", err)
+		}
+	}()
+
+	// 收到退出信号后优雅关闭：停止接受新请求并等待处理中的请求结束，再按相反顺序关闭各业务模块
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	logrus.Info("正在关闭服务器")
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.App.ShutdownTimeout)
+	defer shutdownCancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logrus.WithError(err).Error("关闭服务器失败")
+	}
+	if err := modules.Shutdown(shutdownCtx); err != nil {
+		logrus.WithError(err).Error("关闭业务模块失败")
 	}
 }
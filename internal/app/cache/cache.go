@@ -0,0 +1,88 @@
+// Package cache 进程内短时缓存：按键缓存计算结果，超过有效期后失效
+//
+// 缓存只保存在当前进程的内存中，多实例部署时各实例分别缓存，数据变更后在有效期内可能读到旧结果。
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache 缓存接口
+type Cache interface {
+	// Get 获取未过期的缓存值
+	Get(key string) (any, bool)
+	// Set 写入缓存值，有效期为创建缓存时指定的时间
+	Set(key string, value any)
+}
+
+// MemoryCache 基于内存的缓存实现
+type MemoryCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]entry
+}
+
+// entry 缓存项
+type entry struct {
+	value    any
+	expireAt time.Time
+}
+
+// NewMemory 创建内存缓存，ttl <= 0 时不缓存（Set 不保存），maxEntries <= 0 时为 1000
+func NewMemory(ttl time.Duration, maxEntries int) Cache {
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	return &MemoryCache{ttl: ttl, maxEntries: maxEntries, entries: make(map[string]entry)}
+}
+
+// Get 获取缓存值，已过期的缓存项同时删除
+func (c *MemoryCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(item.expireAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return item.value, true
+}
+
+// Set 写入缓存值，缓存项已满时先清理过期项，仍然已满则淘汰最早过期的一项
+func (c *MemoryCache) Set(key string, value any) {
+	if c.ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = entry{value: value, expireAt: now.Add(c.ttl)}
+}
+
+// evict 清理过期项，没有过期项时淘汰最早过期的一项，调用方持有锁
+func (c *MemoryCache) evict(now time.Time) {
+	var (
+		oldestKey string
+		oldest    time.Time
+	)
+	for key, item := range c.entries {
+		if !now.Before(item.expireAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || item.expireAt.Before(oldest) {
+			oldestKey, oldest = key, item.expireAt
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		delete(c.entries, oldestKey)
+	}
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	type step struct {
+		set   string        // 写入的键，为空时不写入
+		sleep time.Duration // 写入后等待的时间
+	}
+	tests := []struct {
+		name       string
+		ttl        time.Duration
+		maxEntries int
+		steps      []step
+		wantHits   []string // 最终命中的键
+		wantMisses []string // 最终未命中的键
+	}{
+		{
+			name:     "有效期内命中",
+			ttl:      time.Hour,
+			steps:    []step{{set: "a"}, {set: "b"}},
+			wantHits: []string{"a", "b"},
+		},
+		{
+			name:       "超过有效期后失效",
+			ttl:        20 * time.Millisecond,
+			steps:      []step{{set: "a", sleep: 40 * time.Millisecond}},
+			wantMisses: []string{"a"},
+		},
+		{
+			name:       "ttl <= 0 时不缓存",
+			steps:      []step{{set: "a"}},
+			wantMisses: []string{"a"},
+		},
+		{
+			name:       "已满时淘汰最早过期的一项",
+			ttl:        time.Hour,
+			maxEntries: 2,
+			steps:      []step{{set: "a", sleep: time.Millisecond}, {set: "b", sleep: time.Millisecond}, {set: "c"}},
+			wantHits:   []string{"b", "c"},
+			wantMisses: []string{"a"},
+		},
+		{
+			name:       "更新已有的键不淘汰其他项",
+			ttl:        time.Hour,
+			maxEntries: 2,
+			steps:      []step{{set: "a", sleep: time.Millisecond}, {set: "b"}, {set: "a"}},
+			wantHits:   []string{"a", "b"},
+		},
+		{
+			name:       "未命中的键",
+			ttl:        time.Hour,
+			wantMisses: []string{"missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewMemory(tt.ttl, tt.maxEntries)
+			for _, s := range tt.steps {
+				if s.set != "" {
+					c.Set(s.set, "value-"+s.set)
+				}
+				time.Sleep(s.sleep)
+			}
+			for _, key := range tt.wantHits {
+				if got, ok := c.Get(key); !ok || got != "value-"+key {
+					t.Errorf("Get(%q) = %v, %v, want 命中", key, got, ok)
+				}
+			}
+			for _, key := range tt.wantMisses {
+				if got, ok := c.Get(key); ok {
+					t.Errorf("Get(%q) = %v, want 未命中", key, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryCacheEvictsExpiredFirst(t *testing.T) {
+	c := NewMemory(30*time.Millisecond, 2).(*MemoryCache)
+	c.Set("a", 1)
+	time.Sleep(40 * time.Millisecond)
+	c.Set("b", 2) // a 已过期但仍在缓存中
+	c.Set("c", 3) // 已满时先清理过期的 a，b 保留
+
+	if _, ok := c.entries["a"]; ok {
+		t.Error("过期项 a 未被清理")
+	}
+	for _, key := range []string{"b", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("Get(%q) 未命中", key)
+		}
+	}
+}
@@ -2,27 +2,69 @@
 package config
 
 import (
+	"slices"
 	"time"
 )
 
 // Config 主配置结构
 type Config struct {
-	App      AppConfig      `yaml:"app"` // yaml 标签:用于告诉解析器在解析 YAML 文件时，如何将 YAML 文件中的键名映射到 Go 结构体的字段名。
-	Database DatabaseConfig `yaml:"database"`
+	App        AppConfig                 `yaml:"app"`       // yaml 标签:用于告诉解析器在解析 YAML 文件时，如何将 YAML 文件中的键名映射到 Go 结构体的字段名。
+	Databases  map[string]DatabaseConfig `yaml:"databases"` // 命名数据库连接，必须包含 default
+	I18n       I18nConfig                `yaml:"i18n"`
+	Tenant     TenantConfig              `yaml:"tenant"`
+	Pagination PaginationConfig          `yaml:"pagination"`
+	Batch      BatchConfig               `yaml:"batch"`
+	Audit      AuditConfig               `yaml:"audit"`
+	Versions   VersionConfig             `yaml:"versions"`
+	Import     ImportConfig              `yaml:"import"`
+	Stats      StatsConfig               `yaml:"stats"`
+	Modules    ModulesConfig             `yaml:"modules"`
 }
 
+// DefaultDatabase 默认数据库连接名称
+const DefaultDatabase = "default"
+
 // AppConfig 应用配置
 type AppConfig struct {
-	Name           string   `yaml:"name"`
-	Env            string   `yaml:"env"`
-	Port           int      `yaml:"port"`
-	Debug          bool     `yaml:"debug"`
-	AllowedOrigins []string `yaml:"cors.allowed_origins"`
+	Name            string        `yaml:"name"`
+	Env             string        `yaml:"env"`
+	Port            int           `yaml:"port"`
+	Debug           bool          `yaml:"debug"`
+	AllowedOrigins  []string      `yaml:"cors.allowed_origins"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"` // 收到退出信号后等待处理中的请求和后台任务结束的最长时间
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
-	Driver                string        `yaml:"driver"`
+	Driver                string            `yaml:"driver"`
+	Host                  string            `yaml:"host"`
+	Port                  int               `yaml:"port"`
+	Username              string            `yaml:"username"`
+	Password              string            `yaml:"password"`
+	DBName                string            `yaml:"dbname"`
+	MaxOpenConnections    int               `yaml:"max_open_connections"`
+	MaxIdleConnections    int               `yaml:"max_idle_connections"`
+	ConnectionMaxLifetime time.Duration     `yaml:"connection_max_lifetime"`
+	TimeZone              string            `yaml:"time_zone"` // 时间的存储时区（IANA 名称，如 UTC、Asia/Shanghai），默认 UTC
+	Transaction           TransactionConfig `yaml:"transaction"`
+	Replicas              []ReplicaConfig   `yaml:"replicas"`       // 只读副本（从库）列表，为空时读写均使用主库
+	ReplicaPolicy         string            `yaml:"replica_policy"` // 副本负载均衡策略：random, round_robin, least_conn
+	ReplicaHealthCheck    time.Duration     `yaml:"replica_health_check_interval"`
+	Connect               ConnectConfig     `yaml:"connect"`
+}
+
+// ConnectConfig 启动时数据库连接重试配置
+type ConnectConfig struct {
+	MaxRetries      int           `yaml:"max_retries"`      // 最大重试次数，<= 0 表示不限次数（仍受 max_wait 限制）
+	InitialInterval time.Duration `yaml:"initial_interval"` // 首次重试前的等待时间，之后按指数增长
+	MaxInterval     time.Duration `yaml:"max_interval"`     // 单次重试等待时间上限
+	MaxWait         time.Duration `yaml:"max_wait"`         // 启动阶段等待数据库就绪的总时长上限
+	Jitter          float64       `yaml:"jitter"`           // 等待时间随机抖动比例（0-1），避免多个实例同时重连
+	Lazy            bool          `yaml:"lazy"`             // 延迟连接：服务先启动并在就绪探针中报告未就绪，后台持续重连
+}
+
+// ReplicaConfig 只读副本配置，连接信息未配置的项沿用主库配置
+type ReplicaConfig struct {
 	Host                  string        `yaml:"host"`
 	Port                  int           `yaml:"port"`
 	Username              string        `yaml:"username"`
@@ -32,3 +74,91 @@ type DatabaseConfig struct {
 	MaxIdleConnections    int           `yaml:"max_idle_connections"`
 	ConnectionMaxLifetime time.Duration `yaml:"connection_max_lifetime"`
 }
+
+// TransactionConfig 事务配置
+type TransactionConfig struct {
+	IsolationLevel string        `yaml:"isolation_level"` // 默认隔离级别：read_uncommitted, read_committed, repeatable_read, serializable，为空使用数据库默认级别
+	MaxRetries     int           `yaml:"max_retries"`     // 死锁或序列化失败时的最大重试次数，0 表示不重试
+	RetryInterval  time.Duration `yaml:"retry_interval"`  // 首次重试前的等待时间，之后每次翻倍
+}
+
+// I18nConfig 多语言配置
+type I18nConfig struct {
+	DefaultLocale string `yaml:"default_locale"` // 默认语言，请求未指定或指定的语言不支持时使用
+	QueryParam    string `yaml:"query_param"`    // 指定语言的查询参数名，优先级高于 Accept-Language 请求头
+	Dir           string `yaml:"dir"`            // 外部消息目录路径（可选），其中的消息会覆盖内置消息
+}
+
+// PaginationConfig 分页配置
+type PaginationConfig struct {
+	CursorSecret string `yaml:"cursor_secret"` // 游标签名密钥，为空时使用启动时生成的随机密钥（重启或多实例部署时游标失效）
+}
+
+// BatchConfig 批量操作配置
+type BatchConfig struct {
+	MaxSize   int `yaml:"max_size"`   // 单次批量更新 / 删除的最大记录数
+	ChunkSize int `yaml:"chunk_size"` // 批量创建时每批插入的记录数，每批在独立事务中执行
+}
+
+// AuditConfig 审计日志配置
+type AuditConfig struct {
+	Enabled bool `yaml:"enabled"` // 是否记录审计日志，启用前需创建 audit_log 表
+}
+
+// VersionConfig 数据历史版本配置
+type VersionConfig struct {
+	Enabled  bool          `yaml:"enabled"`   // 是否保存历史版本，启用前需创建 demo_versions 表
+	KeepLast int           `yaml:"keep_last"` // 每条数据最多保留的版本数，<= 0 不限制
+	MaxAge   time.Duration `yaml:"max_age"`   // 版本最长保留时间，超过的旧版本被清理，0 不限制；最新版本始终保留
+}
+
+// ImportConfig 数据导入配置
+type ImportConfig struct {
+	MaxFileSize    int64         `yaml:"max_file_size"`   // 上传请求体大小上限（字节）
+	CommitSize     int           `yaml:"commit_size"`     // chunk 事务模式下每个事务包含的行数
+	AsyncThreshold int64         `yaml:"async_threshold"` // 文件超过该大小（字节）时转为异步任务执行
+	MaxErrors      int           `yaml:"max_errors"`      // 导入结果中最多返回的错误行数
+	Workers        int           `yaml:"workers"`         // 同时执行的异步导入任务数
+	QueueSize      int           `yaml:"queue_size"`      // 排队等待的异步导入任务数上限
+	JobRetention   time.Duration `yaml:"job_retention"`   // 已结束的导入任务保留时间，超过后无法再查询
+}
+
+// StatsConfig 统计查询配置
+type StatsConfig struct {
+	MaxGroups int           `yaml:"max_groups"` // 单次统计最多返回的分组数，超出时截断
+	CacheTTL  time.Duration `yaml:"cache_ttl"`  // 统计结果缓存时间，< 0 不缓存
+	CacheSize int           `yaml:"cache_size"` // 最多缓存的统计结果数
+}
+
+// ModulesConfig 业务模块配置
+type ModulesConfig struct {
+	Enabled     map[string]bool `yaml:"enabled"`      // 模块名称 -> 是否启用，未列出的模块默认启用
+	AutoMigrate bool            `yaml:"auto_migrate"` // 启动时按各模块的数据模型自动迁移表结构
+}
+
+// MigrationDatabases 自动迁移表结构的数据库连接名称（已排序、去重）：默认连接，database 隔离模式下还包括各租户映射的连接
+// schema 隔离模式下配置中没有租户列表，各租户 schema 无法自动迁移，由配置验证拒绝
+func (c *Config) MigrationDatabases() []string {
+	names := []string{DefaultDatabase}
+	if c.Tenant.Enabled && c.Tenant.Mode == "database" {
+		for _, name := range c.Tenant.Databases {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// TenantConfig 多租户配置
+type TenantConfig struct {
+	Enabled      bool              `yaml:"enabled"`       // 是否启用多租户隔离
+	Mode         string            `yaml:"mode"`          // 隔离模式：column（共享表，按 tenant_id 列过滤）, schema（每个租户独立 schema）, database（每个租户独立数据库连接）
+	Sources      []string          `yaml:"sources"`       // 租户标识来源，按顺序尝试：header, subdomain, claim
+	Header       string            `yaml:"header"`        // 携带租户标识的请求头
+	BaseDomain   string            `yaml:"base_domain"`   // 子域名解析的基础域名，如 example.com 时 acme.example.com 解析为租户 acme
+	ClaimsKey    string            `yaml:"claims_key"`    // 上游认证中间件存放已校验 JWT 声明（map[string]any）的上下文键
+	Claim        string            `yaml:"claim"`         // JWT 声明中的租户字段名
+	Required     bool              `yaml:"required"`      // 请求未携带租户标识时是否拒绝
+	SchemaFormat string            `yaml:"schema_format"` // schema 模式下租户 schema 名称格式，%s 替换为租户标识
+	Databases    map[string]string `yaml:"databases"`     // database 模式下租户标识 -> databases 中的连接名称
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3" // 第三方YAML解析库，用于将YAML数据解析为Go结构体
 )
@@ -27,6 +28,9 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("解析配置文件失败: %w", err)
 	}
 
+	// 填充默认值
+	setDefaults(&config)
+
 	// 验证配置
 	if err := validateConfig(&config); err != nil {
 		return nil, fmt.Errorf("配置验证失败: %w", err)
@@ -53,6 +57,122 @@ func replaceEnvVariables(data []byte) []byte {
 	}))
 }
 
+// setDefaults 为未配置的可选项填充默认值
+func setDefaults(config *Config) {
+	// 应用默认值
+	if config.App.ShutdownTimeout <= 0 {
+		config.App.ShutdownTimeout = 30 * time.Second
+	}
+
+	// 多语言默认值
+	if config.I18n.DefaultLocale == "" {
+		config.I18n.DefaultLocale = "zh-CN"
+	}
+	if config.I18n.QueryParam == "" {
+		config.I18n.QueryParam = "lang"
+	}
+
+	// 多租户默认值
+	if config.Tenant.Mode == "" {
+		config.Tenant.Mode = "column"
+	}
+	if len(config.Tenant.Sources) == 0 {
+		config.Tenant.Sources = []string{"header"}
+	}
+	if config.Tenant.Header == "" {
+		config.Tenant.Header = "X-Tenant-ID"
+	}
+	if config.Tenant.ClaimsKey == "" {
+		config.Tenant.ClaimsKey = "claims"
+	}
+	if config.Tenant.Claim == "" {
+		config.Tenant.Claim = "tenant_id"
+	}
+	if config.Tenant.SchemaFormat == "" {
+		config.Tenant.SchemaFormat = "tenant_%s"
+	}
+
+	// 批量操作默认值
+	if config.Batch.MaxSize <= 0 {
+		config.Batch.MaxSize = 100
+	}
+	if config.Batch.ChunkSize <= 0 {
+		config.Batch.ChunkSize = 100
+	}
+
+	// 数据导入默认值
+	if config.Import.MaxFileSize <= 0 {
+		config.Import.MaxFileSize = 100 << 20
+	}
+	if config.Import.CommitSize <= 0 {
+		config.Import.CommitSize = 1000
+	}
+	if config.Import.AsyncThreshold <= 0 {
+		config.Import.AsyncThreshold = 1 << 20
+	}
+	if config.Import.MaxErrors <= 0 {
+		config.Import.MaxErrors = 100
+	}
+	if config.Import.Workers <= 0 {
+		config.Import.Workers = 2
+	}
+	if config.Import.QueueSize <= 0 {
+		config.Import.QueueSize = 10
+	}
+	if config.Import.JobRetention <= 0 {
+		config.Import.JobRetention = time.Hour
+	}
+
+	// 统计查询默认值
+	if config.Stats.MaxGroups <= 0 {
+		config.Stats.MaxGroups = 1000
+	}
+	if config.Stats.CacheTTL == 0 {
+		config.Stats.CacheTTL = 30 * time.Second
+	}
+	if config.Stats.CacheSize <= 0 {
+		config.Stats.CacheSize = 1000
+	}
+
+	// 数据库默认值（map 中的值不可寻址，逐个取出修改后写回）
+	for name, dbConfig := range config.Databases {
+		setDatabaseDefaults(&dbConfig)
+		config.Databases[name] = dbConfig
+	}
+}
+
+// setDatabaseDefaults 为单个数据库连接填充默认值
+func setDatabaseDefaults(dbConfig *DatabaseConfig) {
+	// 时间默认按 UTC 存储，不依赖服务器所在时区
+	if dbConfig.TimeZone == "" {
+		dbConfig.TimeZone = "UTC"
+	}
+	// 只读副本默认值
+	if dbConfig.ReplicaPolicy == "" {
+		dbConfig.ReplicaPolicy = "round_robin"
+	}
+	if dbConfig.ReplicaHealthCheck <= 0 {
+		dbConfig.ReplicaHealthCheck = 10 * time.Second
+	}
+	// 副本未配置端口时沿用主库端口
+	for i := range dbConfig.Replicas {
+		if dbConfig.Replicas[i].Port == 0 {
+			dbConfig.Replicas[i].Port = dbConfig.Port
+		}
+	}
+
+	// 数据库连接重试默认值
+	if dbConfig.Connect.InitialInterval <= 0 {
+		dbConfig.Connect.InitialInterval = time.Second
+	}
+	if dbConfig.Connect.MaxInterval <= 0 {
+		dbConfig.Connect.MaxInterval = 30 * time.Second
+	}
+	if dbConfig.Connect.MaxWait <= 0 {
+		dbConfig.Connect.MaxWait = 2 * time.Minute
+	}
+}
+
 // validateConfig 验证配置的有效性
 func validateConfig(config *Config) error {
 	// 验证应用配置
@@ -61,8 +181,80 @@ func validateConfig(config *Config) error {
 	}
 
 	// 验证数据库配置
-	if err := validateDatabaseConfig(&config.Database, config.App.Env); err != nil {
-		return fmt.Errorf("数据库配置验证失败: %w", err)
+	if _, ok := config.Databases[DefaultDatabase]; !ok {
+		return fmt.Errorf("数据库配置验证失败: databases 中必须包含名为 '%s' 的连接", DefaultDatabase)
+	}
+	for name, dbConfig := range config.Databases {
+		if err := validateDatabaseConfig(&dbConfig, config.App.Env); err != nil {
+			return fmt.Errorf("数据库 '%s' 配置验证失败: %w", name, err)
+		}
+	}
+
+	// 验证多租户配置
+	if config.Tenant.Enabled {
+		if err := validateTenantConfig(&config.Tenant, config.Databases); err != nil {
+			return fmt.Errorf("多租户配置验证失败: %w", err)
+		}
+	}
+
+	// 验证自动迁移配置
+	if config.Modules.AutoMigrate {
+		if err := validateAutoMigrate(config); err != nil {
+			return fmt.Errorf("模块配置验证失败: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateAutoMigrate 验证自动迁移表结构的配置：迁移在启动时同步执行，需要迁移的连接必须能在启动时访问
+func validateAutoMigrate(config *Config) error {
+	if config.Tenant.Enabled && config.Tenant.Mode == "schema" {
+		return fmt.Errorf("schema 隔离模式下无法自动迁移各租户 schema 的表结构(auto_migrate)，请关闭后手动迁移")
+	}
+	for _, name := range config.MigrationDatabases() {
+		if config.Databases[name].Connect.Lazy {
+			return fmt.Errorf("数据库 '%s' 启用延迟连接(connect.lazy)时不能自动迁移表结构(auto_migrate)，启动时数据库可能尚未就绪", name)
+		}
+	}
+	return nil
+}
+
+// validateTenantConfig 验证多租户配置
+func validateTenantConfig(tenantConfig *TenantConfig, databases map[string]DatabaseConfig) error {
+	validModes := map[string]bool{"column": true, "schema": true, "database": true}
+	if !validModes[tenantConfig.Mode] {
+		return fmt.Errorf("无效的租户隔离模式: '%s'，有效值为 'column', 'schema', 'database'", tenantConfig.Mode)
+	}
+
+	validSources := map[string]bool{"header": true, "subdomain": true, "claim": true}
+	for _, source := range tenantConfig.Sources {
+		if !validSources[source] {
+			return fmt.Errorf("无效的租户标识来源: '%s'，有效值为 'header', 'subdomain', 'claim'", source)
+		}
+		if source == "subdomain" && tenantConfig.BaseDomain == "" {
+			return fmt.Errorf("使用子域名解析租户时，基础域名(base_domain)不能为空")
+		}
+	}
+
+	switch tenantConfig.Mode {
+	case "schema":
+		if strings.Count(tenantConfig.SchemaFormat, "%s") != 1 {
+			return fmt.Errorf("租户 schema 名称格式(schema_format)必须包含且仅包含一个 %%s")
+		}
+	case "database":
+		if len(tenantConfig.Databases) == 0 {
+			return fmt.Errorf("database 隔离模式下，租户数据库映射(databases)不能为空")
+		}
+		for tenantID, name := range tenantConfig.Databases {
+			if _, ok := databases[name]; !ok {
+				return fmt.Errorf("租户 '%s' 映射的数据库连接 '%s' 未在 databases 中配置", tenantID, name)
+			}
+		}
+		// 租户路由在连接池层完成，无法再叠加 default 连接的读写分离
+		if len(databases[DefaultDatabase].Replicas) > 0 {
+			return fmt.Errorf("database 隔离模式下，default 连接不支持配置只读副本")
+		}
 	}
 
 	return nil
@@ -113,5 +305,39 @@ func validateDatabaseConfig(dbConfig *DatabaseConfig, appEnv string) error {
 		return fmt.Errorf("最大空闲连接数(max_idle_connections)不能大于最大连接数(max_open_connections)")
 	}
 
+	// 检查事务配置
+	validIsolationLevels := map[string]bool{"": true, "read_uncommitted": true, "read_committed": true, "repeatable_read": true, "serializable": true}
+	if !validIsolationLevels[dbConfig.Transaction.IsolationLevel] {
+		return fmt.Errorf("无效的事务隔离级别: '%s'，有效值为 'read_uncommitted', 'read_committed', 'repeatable_read', 'serializable'", dbConfig.Transaction.IsolationLevel)
+	}
+	if dbConfig.Transaction.MaxRetries < 0 {
+		return fmt.Errorf("事务重试次数(transaction.max_retries)不能小于0")
+	}
+
+	// 检查连接重试配置
+	if dbConfig.Connect.Jitter < 0 || dbConfig.Connect.Jitter > 1 {
+		return fmt.Errorf("无效的连接重试抖动比例(connect.jitter): %v，取值范围应为 0-1", dbConfig.Connect.Jitter)
+	}
+	if dbConfig.Connect.MaxInterval < dbConfig.Connect.InitialInterval {
+		return fmt.Errorf("连接重试最大间隔(connect.max_interval)不能小于初始间隔(connect.initial_interval)")
+	}
+
+	// 检查只读副本配置
+	validPolicies := map[string]bool{"random": true, "round_robin": true, "least_conn": true}
+	if !validPolicies[dbConfig.ReplicaPolicy] {
+		return fmt.Errorf("无效的副本负载均衡策略: '%s'，有效值为 'random', 'round_robin', 'least_conn'", dbConfig.ReplicaPolicy)
+	}
+	for i, replica := range dbConfig.Replicas {
+		if replica.Host == "" {
+			return fmt.Errorf("第%d个只读副本的主机(host)不能为空", i+1)
+		}
+		if replica.Port < 1 || replica.Port > 65535 {
+			return fmt.Errorf("第%d个只读副本的端口无效: %d，端口范围应为 1-65535", i+1, replica.Port)
+		}
+		if replica.MaxOpenConnections > 0 && replica.MaxIdleConnections > replica.MaxOpenConnections {
+			return fmt.Errorf("第%d个只读副本的最大空闲连接数不能大于最大连接数", i+1)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testConfig 返回通过验证的最小配置，databases 为 default 以外的连接名称
+func testConfig(databases ...string) *Config {
+	cfg := &Config{
+		App:       AppConfig{Env: "development", Port: 8080},
+		Databases: map[string]DatabaseConfig{},
+		Modules:   ModulesConfig{AutoMigrate: true},
+	}
+	for _, name := range append([]string{DefaultDatabase}, databases...) {
+		cfg.Databases[name] = DatabaseConfig{Driver: "mysql", Host: "127.0.0.1", Port: 3306, Username: "root", DBName: name}
+	}
+	setDefaults(cfg)
+	return cfg
+}
+
+func TestMigrationDatabases(t *testing.T) {
+	tests := []struct {
+		name   string
+		tenant TenantConfig
+		want   []string
+	}{
+		{"未启用多租户", TenantConfig{Mode: "database", Databases: map[string]string{"t1": "db1"}}, []string{"default"}},
+		{"column 模式", TenantConfig{Enabled: true, Mode: "column"}, []string{"default"}},
+		{
+			"database 模式包括各租户的连接并去重",
+			TenantConfig{Enabled: true, Mode: "database", Databases: map[string]string{"t1": "db2", "t2": "db1", "t3": "db1", "t4": "default"}},
+			[]string{"db1", "db2", "default"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Tenant: tt.tenant}
+			if got := cfg.MigrationDatabases(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MigrationDatabases() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAutoMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:  "默认连接",
+			setup: func(cfg *Config) {},
+		},
+		{
+			name: "未迁移的连接可以延迟连接",
+			setup: func(cfg *Config) {
+				setLazy(cfg, "db1")
+			},
+		},
+		{
+			name: "默认连接延迟连接",
+			setup: func(cfg *Config) {
+				setLazy(cfg, DefaultDatabase)
+			},
+			wantErr: "数据库 'default' 启用延迟连接",
+		},
+		{
+			name: "database 模式下租户连接延迟连接",
+			setup: func(cfg *Config) {
+				cfg.Tenant = TenantConfig{Enabled: true, Mode: "database", Databases: map[string]string{"t1": "db1"}}
+				setLazy(cfg, "db1")
+			},
+			wantErr: "数据库 'db1' 启用延迟连接",
+		},
+		{
+			name: "schema 模式",
+			setup: func(cfg *Config) {
+				cfg.Tenant = TenantConfig{Enabled: true, Mode: "schema", SchemaFormat: "tenant_%s"}
+			},
+			wantErr: "schema 隔离模式下无法自动迁移",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig("db1")
+			tt.setup(cfg)
+			err := validateConfig(cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateConfig() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// setLazy 为连接启用延迟连接
+func setLazy(cfg *Config, name string) {
+	db := cfg.Databases[name]
+	db.Connect.Lazy = true
+	cfg.Databases[name] = db
+}
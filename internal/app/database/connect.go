@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-template/internal/app/config"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// 就绪探针 Ping 数据库的超时时间
+const readinessPingTimeout = 2 * time.Second
+
+// ErrNotReady 数据库尚未就绪（延迟连接模式下后台仍在重连）
+var ErrNotReady = errors.New("数据库尚未就绪")
+
+// Readiness 数据库就绪状态，供就绪探针使用
+type Readiness struct {
+	db      *gorm.DB
+	ready   atomic.Bool
+	mu      sync.RWMutex
+	lastErr error // 最近一次连接失败的原因
+}
+
+// Ready 判断数据库是否就绪：已建立过连接且当前可以 Ping 通
+func (r *Readiness) Ready(ctx context.Context) error {
+	if !r.ready.Load() {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		if r.lastErr != nil {
+			return fmt.Errorf("%w: %v", ErrNotReady, r.lastErr)
+		}
+		return ErrNotReady
+	}
+
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, readinessPingTimeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+// markFailed 记录连接失败原因
+func (r *Readiness) markFailed(err error) {
+	r.mu.Lock()
+	r.lastErr = err
+	r.mu.Unlock()
+}
+
+// markReady 标记数据库已就绪
+func (r *Readiness) markReady() {
+	r.mu.Lock()
+	r.lastErr = nil
+	r.mu.Unlock()
+	r.ready.Store(true)
+}
+
+// Open 按连接重试配置创建数据库连接
+//   - 默认模式：连接失败时按指数退避（带随机抖动）重试，超过最大重试次数或最大等待时间后返回错误
+//   - 延迟连接模式（connect.lazy）：立即返回未连接的数据库实例，后台持续重连，连接成功前就绪探针报告未就绪
+//
+// ctx 取消时停止重试（包括延迟连接模式的后台重连）
+func Open(ctx context.Context, cfg config.DatabaseConfig) (*gorm.DB, *Readiness, error) {
+	if cfg.Connect.Lazy {
+		return openLazy(ctx, cfg)
+	}
+
+	db, err := connectWithRetry(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	readiness := &Readiness{db: db}
+	readiness.markReady()
+	return db, readiness, nil
+}
+
+// connectWithRetry 启动阶段重试连接数据库
+func connectWithRetry(ctx context.Context, cfg config.DatabaseConfig) (*gorm.DB, error) {
+	backoff := newBackoff(cfg.Connect)
+	deadline := time.Now().Add(cfg.Connect.MaxWait)
+
+	for attempt := 1; ; attempt++ {
+		db, err := NewDatabase(cfg)
+		if err == nil {
+			if attempt > 1 {
+				logrus.WithField("attempt", attempt).Info("数据库连接成功")
+			}
+			return db, nil
+		}
+
+		wait := backoff.next()
+		if cfg.Connect.MaxRetries > 0 && attempt > cfg.Connect.MaxRetries {
+			return nil, fmt.Errorf("数据库连接失败，已重试 %d 次: %w", cfg.Connect.MaxRetries, err)
+		}
+		if time.Now().Add(wait).After(deadline) {
+			return nil, fmt.Errorf("数据库连接失败，等待超过 %s: %w", cfg.Connect.MaxWait, err)
+		}
+
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"attempt": attempt,
+			"wait":    wait.String(),
+		}).Warn("数据库连接失败，等待后重试")
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, fmt.Errorf("数据库连接已取消: %w", err)
+		}
+	}
+}
+
+// openLazy 延迟连接模式：创建不访问数据库的实例，后台按退避策略 Ping 直到连接成功
+// 不受最大重试次数和最大等待时间限制，服务运行期间一直重连
+func openLazy(ctx context.Context, cfg config.DatabaseConfig) (*gorm.DB, *Readiness, error) {
+	db, err := newDatabase(cfg, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取底层sql.DB失败: %w", err)
+	}
+
+	readiness := &Readiness{db: db}
+
+	go func() {
+		backoff := newBackoff(cfg.Connect)
+		for attempt := 1; ; attempt++ {
+			pingCtx, cancel := context.WithTimeout(ctx, readinessPingTimeout)
+			err := sqlDB.PingContext(pingCtx)
+			cancel()
+			if err == nil {
+				readiness.markReady()
+				logrus.WithField("attempt", attempt).Info("数据库连接成功，服务已就绪")
+				return
+			}
+			readiness.markFailed(err)
+
+			wait := backoff.next()
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"attempt": attempt,
+				"wait":    wait.String(),
+			}).Warn("数据库连接失败，后台等待后重连")
+
+			if sleepContext(ctx, wait) != nil {
+				return
+			}
+		}
+	}()
+
+	return db, readiness, nil
+}
+
+// backoff 指数退避：每次等待时间翻倍，不超过上限，并按比例加入随机抖动
+type backoff struct {
+	current time.Duration
+	max     time.Duration
+	jitter  float64
+}
+
+// newBackoff 根据连接重试配置创建退避计算器
+func newBackoff(cfg config.ConnectConfig) *backoff {
+	return &backoff{current: cfg.InitialInterval, max: cfg.MaxInterval, jitter: cfg.Jitter}
+}
+
+// next 返回本次的等待时间并推进到下一次
+func (b *backoff) next() time.Duration {
+	wait := b.current
+	b.current = min(b.current*2, b.max)
+
+	// 在 [wait*(1-jitter), wait*(1+jitter)] 范围内随机取值
+	if b.jitter > 0 {
+		delta := float64(wait) * b.jitter
+		wait = time.Duration(float64(wait) - delta + rand.Float64()*2*delta)
+	}
+	return wait
+}
+
+// sleepContext 等待指定时长，上下文取消时提前返回错误
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
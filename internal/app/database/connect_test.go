@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"gin-template/internal/app/config"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Run("翻倍且不超过上限", func(t *testing.T) {
+		b := newBackoff(config.ConnectConfig{InitialInterval: time.Second, MaxInterval: 5 * time.Second})
+		want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+		for i, w := range want {
+			if got := b.next(); got != w {
+				t.Errorf("第 %d 次 next() = %s, want %s", i+1, got, w)
+			}
+		}
+	})
+
+	t.Run("抖动不超出比例范围", func(t *testing.T) {
+		b := newBackoff(config.ConnectConfig{InitialInterval: time.Second, MaxInterval: time.Second, Jitter: 0.2})
+		for range 100 {
+			if got := b.next(); got < 800*time.Millisecond || got > 1200*time.Millisecond {
+				t.Fatalf("next() = %s, 超出 [800ms, 1.2s]", got)
+			}
+		}
+	})
+}
+
+func TestSleepContext(t *testing.T) {
+	if err := sleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("sleepContext() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sleepContext(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("sleepContext() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestConnectWithRetry(t *testing.T) {
+	// 不支持的驱动使每次连接都立即失败
+	base := config.DatabaseConfig{Driver: "unknown", TimeZone: "UTC"}
+	tests := []struct {
+		name    string
+		connect config.ConnectConfig
+		cancel  bool
+		wantErr string
+	}{
+		{"超过最大重试次数", config.ConnectConfig{MaxRetries: 2, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, MaxWait: time.Minute}, false, "已重试 2 次"},
+		{"超过最大等待时间", config.ConnectConfig{InitialInterval: time.Hour, MaxInterval: time.Hour, MaxWait: time.Minute}, false, "等待超过 1m0s"},
+		{"上下文取消", config.ConnectConfig{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, MaxWait: time.Minute}, true, "数据库连接已取消"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			cfg.Connect = tt.connect
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+			db, err := connectWithRetry(ctx, cfg)
+			if db != nil || err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("connectWithRetry() = %v, %v, want error containing %q", db, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpenLazy(t *testing.T) {
+	// 没有监听的端口：延迟连接模式立即返回实例，后台重连期间未就绪
+	cfg := config.DatabaseConfig{
+		Driver:   "mysql",
+		Host:     "127.0.0.1",
+		Port:     1,
+		DBName:   "test",
+		TimeZone: "UTC",
+		Connect:  config.ConnectConfig{Lazy: true, InitialInterval: time.Millisecond, MaxInterval: 10 * time.Millisecond},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db, readiness, err := Open(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer Close(db)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		err := readiness.Ready(context.Background())
+		if !errors.Is(err, ErrNotReady) {
+			t.Fatalf("Ready() error = %v, want ErrNotReady", err)
+		}
+		// 后台 Ping 失败后记录失败原因
+		if err.Error() != ErrNotReady.Error() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("未记录连接失败原因")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReadinessReady(t *testing.T) {
+	db := openTestDB(t, nil)
+	readiness := &Readiness{db: db}
+	if err := readiness.Ready(context.Background()); !errors.Is(err, ErrNotReady) {
+		t.Errorf("就绪前 Ready() error = %v, want ErrNotReady", err)
+	}
+	readiness.markFailed(errors.New("refused"))
+	if err := readiness.Ready(context.Background()); !errors.Is(err, ErrNotReady) || !strings.Contains(err.Error(), "refused") {
+		t.Errorf("连接失败后 Ready() error = %v", err)
+	}
+	readiness.markReady()
+	if err := readiness.Ready(context.Background()); err != nil {
+		t.Errorf("就绪后 Ready() error = %v", err)
+	}
+}
@@ -1,40 +1,55 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 	"gin-template/internal/app/config"
+	"net/url"
 	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
-// 全局DB实例（初始化后复用，避免重复创建连接）
-var db *gorm.DB
-
-// NewDatabase 创建数据库连接
+// NewDatabase 创建数据库连接，数据库不可用时立即返回错误（启动重试见 Open）
 func NewDatabase(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	return newDatabase(cfg, false)
+}
+
+// newDatabase 创建数据库连接
+// lazy 为 true 时不在创建时连接数据库（跳过 Ping 和版本查询），首次使用或后台重连时才真正建立连接
+func newDatabase(cfg config.DatabaseConfig, lazy bool) (*gorm.DB, error) {
+	// 时间的存储时区
+	loc, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("无效的时区配置 '%s': %w", cfg.TimeZone, err)
+	}
+
 	// 构建数据源名称 (DSN)
-	var dsn string
-	switch cfg.Driver {
-	case "mysql":
-		dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-			cfg.Username,
-			cfg.Password,
-			cfg.Host,
-			cfg.Port,
-			cfg.DBName,
-		)
-	// 可在此扩展其他数据库驱动
-	default:
-		return nil, fmt.Errorf("不支持的数据库驱动: %s", cfg.Driver)
+	dialector, err := openDialector(cfg.Driver, cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.DBName, loc, lazy)
+	if err != nil {
+		return nil, err
 	}
 
-	var err error
-	// 打开数据库连接，通过 GORM 的Open方法创建数据库连接，并将结果保存到全局变量db中。
-	db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	// 打开数据库连接，通过 GORM 的Open方法创建数据库连接
+	db, err := gorm.Open(dialector, gormConfig(loc, lazy))
 	if err != nil {
-		return nil, fmt.Errorf("数据库连接失败: %v", err)
+		// 连接失败时释放已创建的连接池，避免启动重试期间泄漏
+		if db != nil {
+			if sqlDB, dbErr := db.DB(); dbErr == nil {
+				_ = sqlDB.Close()
+			}
+		}
+		return nil, fmt.Errorf("数据库连接失败: %w", err)
+	}
+
+	// 注册软删除插件，默认过滤已软删除的数据
+	if err := db.Use(SoftDeletePlugin{}); err != nil {
+		return nil, fmt.Errorf("注册软删除插件失败: %w", err)
+	}
+	// 注册操作人插件，创建、更新时填充 created_by / updated_by
+	if err := db.Use(TrackingPlugin{}); err != nil {
+		return nil, fmt.Errorf("注册操作人插件失败: %w", err)
 	}
 
 	// 获取底层的*sql.DB对象，用于配置数据库连接池参数
@@ -44,26 +59,84 @@ func NewDatabase(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	}
 
 	// 设置连接池
-	sqlDB.SetMaxOpenConns(cfg.MaxOpenConnections)
-	sqlDB.SetMaxIdleConns(cfg.MaxIdleConnections)
-	sqlDB.SetConnMaxLifetime(cfg.ConnectionMaxLifetime * time.Second)
+	configurePool(sqlDB, cfg.MaxOpenConnections, cfg.MaxIdleConnections, cfg.ConnectionMaxLifetime)
+
+	// 配置了只读副本时注册读写分离插件，查询路由到副本，写操作和事务使用主库
+	if len(cfg.Replicas) > 0 {
+		resolver, err := newReadWriteResolver(cfg, loc, lazy)
+		if err != nil {
+			_ = sqlDB.Close()
+			return nil, err
+		}
+		if err := db.Use(resolver); err != nil {
+			resolver.Close()
+			_ = sqlDB.Close()
+			return nil, fmt.Errorf("注册读写分离插件失败: %w", err)
+		}
+	}
 
-	// 自动迁移模型
-	// GORM 的 AutoMigrate 会根据定义的模型自动创建或更新数据库表结构（生产环境通常会禁用，改为手动管理表结构）
-	// if err := migrateModels(db); err != nil {
-	//  return nil, err
-	// }
+	// 自动迁移由各业务模块提供数据模型（Module.Migrations），启用 modules.auto_migrate 时在模块初始化后执行
 
 	return db, nil
 }
 
-// migrateModels 自动迁移数据库模型（GORM 的 AutoMigrate 方法会根据数据模型自动创建或更新表结构）
-// func migrateModels(db *gorm.DB) error {
-// 	// 添加需要迁移的模型
-// 	return db.AutoMigrate(&model.Example{})
-// }
+// gormConfig 创建 GORM 配置：自动填充的时间（create_time / update_time 等）使用 loc 时区
+func gormConfig(loc *time.Location, lazy bool) *gorm.Config {
+	return &gorm.Config{
+		DisableAutomaticPing: lazy,
+		NowFunc: func() time.Time {
+			return time.Now().In(loc)
+		},
+	}
+}
+
+// openDialector 根据数据库驱动构建 GORM 方言，lazy 为 true 时初始化方言不访问数据库
+// loc 为时间的存储时区：写入时转换到该时区，读取的时间按该时区解析
+func openDialector(driver, host string, port int, username, password, dbName string, loc *time.Location, lazy bool) (gorm.Dialector, error) {
+	switch driver {
+	case "mysql":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=%s",
+			username,
+			password,
+			host,
+			port,
+			dbName,
+			url.QueryEscape(loc.String()),
+		)
+		// 默认初始化时查询数据库版本以适配方言特性，延迟连接模式下跳过
+		return mysql.New(mysql.Config{DSN: dsn, SkipInitializeWithVersion: lazy}), nil
+	// 可在此扩展其他数据库驱动
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", driver)
+	}
+}
 
-// GetDB 获取数据库连接实例
-func GetDB() *gorm.DB {
-	return db
+// configurePool 设置连接池参数，取值语义与 database/sql 一致（如最大连接数 <= 0 表示不限制）
+func configurePool(sqlDB *sql.DB, maxOpen, maxIdle int, maxLifetime time.Duration) {
+	sqlDB.SetMaxOpenConns(maxOpen)
+	sqlDB.SetMaxIdleConns(maxIdle)
+	// 配置文件中的时长已解析为 time.Duration（如 300s），直接使用
+	sqlDB.SetConnMaxLifetime(maxLifetime)
+}
+
+// resolverOf 返回数据库实例注册的读写分离插件，未配置只读副本时返回 nil
+func resolverOf(db *gorm.DB) *ReadWriteResolver {
+	if plugin, ok := db.Config.Plugins[readWriteResolverName]; ok {
+		if resolver, ok := plugin.(*ReadWriteResolver); ok {
+			return resolver
+		}
+	}
+	return nil
+}
+
+// Close 关闭数据库连接，包括读写分离插件持有的副本连接
+func Close(db *gorm.DB) error {
+	if resolver := resolverOf(db); resolver != nil {
+		resolver.Close()
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
@@ -0,0 +1,187 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gin-template/internal/app/config"
+	"sort"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// Manager 数据库连接管理器，按名称管理多个数据库连接（如 default、analytics）的打开、健康检查和关闭
+// 各模块通过注入的 Manager 获取所需连接，不依赖包级全局变量，便于同时使用多个数据库或并行测试
+type Manager interface {
+	// Open 按配置打开所有连接，任一连接失败时关闭已打开的连接并返回错误
+	Open(ctx context.Context) error
+	// DB 返回指定名称的连接
+	DB(name string) (*gorm.DB, error)
+	// Default 返回默认连接（config.DefaultDatabase）
+	Default() *gorm.DB
+	// Config 返回指定名称连接的配置
+	Config(name string) (config.DatabaseConfig, bool)
+	// Names 返回所有连接名称（按名称排序）
+	Names() []string
+	// Health 检查所有连接，返回连接名称 -> 检查结果（nil 表示健康）
+	Health(ctx context.Context) map[string]error
+	// Stats 返回所有连接的连接池统计信息
+	Stats() map[string]ConnStats
+	// Close 关闭所有连接
+	Close() error
+}
+
+// ConnStats 单个数据库连接的统计信息
+type ConnStats struct {
+	Ready    bool                    `json:"ready"`
+	Primary  sql.DBStats             `json:"primary"`
+	Replicas map[string]ReplicaStats `json:"replicas,omitempty"`
+}
+
+// connection 已打开的命名连接
+type connection struct {
+	db        *gorm.DB
+	readiness *Readiness
+	cancel    context.CancelFunc // 停止该连接的后台重连
+}
+
+// DBManager 数据库连接管理器实现
+type DBManager struct {
+	mu      sync.RWMutex
+	configs map[string]config.DatabaseConfig
+	conns   map[string]*connection
+}
+
+// NewManager 创建数据库连接管理器实例，连接在调用 Open 后才建立
+func NewManager(configs map[string]config.DatabaseConfig) Manager {
+	return &DBManager{
+		configs: configs,
+		conns:   make(map[string]*connection, len(configs)),
+	}
+}
+
+// Open 按配置打开所有连接，每个连接各自按 connect 配置重试或延迟连接
+func (m *DBManager) Open(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, name := range m.sortedNames() {
+		if _, opened := m.conns[name]; opened {
+			continue
+		}
+		connCtx, cancel := context.WithCancel(ctx)
+		db, readiness, err := Open(connCtx, m.configs[name])
+		if err != nil {
+			cancel()
+			_ = m.closeLocked()
+			return fmt.Errorf("打开数据库连接 '%s' 失败: %w", name, err)
+		}
+		m.conns[name] = &connection{db: db, readiness: readiness, cancel: cancel}
+	}
+	return nil
+}
+
+// DB 返回指定名称的连接，连接未配置或未打开时返回错误
+func (m *DBManager) DB(name string) (*gorm.DB, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	conn, ok := m.conns[name]
+	if !ok {
+		return nil, fmt.Errorf("数据库连接 '%s' 未配置或未打开", name)
+	}
+	return conn.db, nil
+}
+
+// Default 返回默认连接，未打开时 panic（属于启动顺序错误）
+func (m *DBManager) Default() *gorm.DB {
+	db, err := m.DB(config.DefaultDatabase)
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+// Config 返回指定名称连接的配置
+func (m *DBManager) Config(name string) (config.DatabaseConfig, bool) {
+	cfg, ok := m.configs[name]
+	return cfg, ok
+}
+
+// Names 返回所有连接名称（按名称排序）
+func (m *DBManager) Names() []string {
+	return m.sortedNames()
+}
+
+// Health 检查所有连接：未打开、尚未就绪或 Ping 失败的连接返回对应错误
+func (m *DBManager) Health(ctx context.Context) map[string]error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := make(map[string]error, len(m.configs))
+	for name := range m.configs {
+		conn, ok := m.conns[name]
+		if !ok {
+			results[name] = ErrNotReady
+			continue
+		}
+		results[name] = conn.readiness.Ready(ctx)
+	}
+	return results
+}
+
+// Stats 返回所有已打开连接的连接池统计信息，包括只读副本
+func (m *DBManager) Stats() map[string]ConnStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := make(map[string]ConnStats, len(m.conns))
+	for name, conn := range m.conns {
+		item := ConnStats{Ready: conn.readiness.ready.Load()}
+		if sqlDB, err := conn.db.DB(); err == nil {
+			item.Primary = sqlDB.Stats()
+		}
+		if resolver := resolverOf(conn.db); resolver != nil {
+			item.Replicas = resolver.Stats()
+		}
+		stats[name] = item
+	}
+	return stats
+}
+
+// Close 关闭所有连接，返回所有关闭失败的错误
+func (m *DBManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closeLocked()
+}
+
+// closeLocked 关闭所有已打开的连接，调用方需持有写锁
+func (m *DBManager) closeLocked() error {
+	var errs []error
+	for name, conn := range m.conns {
+		conn.cancel()
+		if err := Close(conn.db); err != nil {
+			errs = append(errs, fmt.Errorf("关闭数据库连接 '%s' 失败: %w", name, err))
+		}
+		delete(m.conns, name)
+	}
+	return errors.Join(errs...)
+}
+
+// sortedNames 返回排序后的连接名称，默认连接排在首位，保证打开顺序稳定
+func (m *DBManager) sortedNames() []string {
+	names := make([]string, 0, len(m.configs))
+	for name := range m.configs {
+		if name != config.DefaultDatabase {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := m.configs[config.DefaultDatabase]; ok {
+		names = append([]string{config.DefaultDatabase}, names...)
+	}
+	return names
+}
@@ -0,0 +1,260 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gin-template/internal/app/config"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// 读写分离插件名称
+const readWriteResolverName = "read_write_resolver"
+
+// 强制使用主库在 GORM Statement 中的设置键
+const usePrimaryKey = "read_write:primary"
+
+// primaryContextKey 强制使用主库在上下文中的键
+type primaryContextKey struct{}
+
+// WithPrimary 返回强制使用主库的上下文，用于写后立即读取（read-your-writes）等不能容忍复制延迟的场景
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// UsePrimary 查询作用域：本次查询强制使用主库
+// 用法：db.Scopes(database.UsePrimary).Find(&list)
+func UsePrimary(db *gorm.DB) *gorm.DB {
+	return db.Set(usePrimaryKey, true)
+}
+
+// replica 只读副本连接
+type replica struct {
+	name    string  // 副本标识（host:port），用于日志
+	pool    *sql.DB // 副本连接池
+	healthy atomic.Bool
+}
+
+// ReadWriteResolver 读写分离插件
+// 查询（Query/Row）路由到健康的只读副本；写操作、事务内的查询、加锁查询以及显式要求主库的查询使用主库。
+// 后台定期检查副本健康状态，检查失败的副本暂时移出负载均衡，全部副本不可用时回退到主库。
+type ReadWriteResolver struct {
+	replicas []*replica
+	policy   string        // 负载均衡策略：random, round_robin, least_conn
+	interval time.Duration // 健康检查间隔
+	counter  atomic.Uint64 // 轮询计数器
+	stop     chan struct{}
+	once     sync.Once
+}
+
+// newReadWriteResolver 根据配置打开所有只读副本连接
+// lazy 为 true 时不立即连接副本，副本初始标记为不健康，由健康检查确认可用后再加入负载均衡；副本与主库使用相同的存储时区 loc
+func newReadWriteResolver(cfg config.DatabaseConfig, loc *time.Location, lazy bool) (*ReadWriteResolver, error) {
+	resolver := &ReadWriteResolver{
+		policy:   cfg.ReplicaPolicy,
+		interval: cfg.ReplicaHealthCheck,
+		stop:     make(chan struct{}),
+	}
+
+	for _, replicaCfg := range cfg.Replicas {
+		replicaCfg = inheritPrimary(replicaCfg, cfg)
+		name := fmt.Sprintf("%s:%d", replicaCfg.Host, replicaCfg.Port)
+
+		dialector, err := openDialector(cfg.Driver, replicaCfg.Host, replicaCfg.Port, replicaCfg.Username, replicaCfg.Password, replicaCfg.DBName, loc, lazy)
+		if err != nil {
+			resolver.Close()
+			return nil, err
+		}
+		replicaDB, err := gorm.Open(dialector, gormConfig(loc, lazy))
+		if err != nil {
+			resolver.Close()
+			return nil, fmt.Errorf("只读副本 %s 连接失败: %w", name, err)
+		}
+		pool, err := replicaDB.DB()
+		if err != nil {
+			resolver.Close()
+			return nil, fmt.Errorf("获取只读副本 %s 的底层sql.DB失败: %w", name, err)
+		}
+
+		// 每个副本使用独立的连接池参数
+		configurePool(pool, replicaCfg.MaxOpenConnections, replicaCfg.MaxIdleConnections, replicaCfg.ConnectionMaxLifetime)
+
+		r := &replica{name: name, pool: pool}
+		r.healthy.Store(!lazy)
+		resolver.replicas = append(resolver.replicas, r)
+	}
+
+	return resolver, nil
+}
+
+// inheritPrimary 副本未配置的连接信息沿用主库配置
+func inheritPrimary(replicaCfg config.ReplicaConfig, primary config.DatabaseConfig) config.ReplicaConfig {
+	if replicaCfg.Port == 0 {
+		replicaCfg.Port = primary.Port
+	}
+	if replicaCfg.Username == "" {
+		replicaCfg.Username = primary.Username
+	}
+	if replicaCfg.Password == "" {
+		replicaCfg.Password = primary.Password
+	}
+	if replicaCfg.DBName == "" {
+		replicaCfg.DBName = primary.DBName
+	}
+	if replicaCfg.MaxOpenConnections == 0 {
+		replicaCfg.MaxOpenConnections = primary.MaxOpenConnections
+	}
+	if replicaCfg.MaxIdleConnections == 0 {
+		replicaCfg.MaxIdleConnections = primary.MaxIdleConnections
+	}
+	if replicaCfg.ConnectionMaxLifetime == 0 {
+		replicaCfg.ConnectionMaxLifetime = primary.ConnectionMaxLifetime
+	}
+	return replicaCfg
+}
+
+// Name 插件名称
+func (r *ReadWriteResolver) Name() string {
+	return readWriteResolverName
+}
+
+// Initialize 注册路由回调并启动副本健康检查
+func (r *ReadWriteResolver) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register("read_write:query", r.route); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("read_write:row", r.route); err != nil {
+		return err
+	}
+
+	go r.healthCheckLoop()
+	return nil
+}
+
+// route 为查询语句选择连接：满足条件时将 Statement 的连接池替换为只读副本
+func (r *ReadWriteResolver) route(db *gorm.DB) {
+	if db.Error != nil || r.usePrimary(db) {
+		return
+	}
+	if target := r.pick(); target != nil {
+		db.Statement.ConnPool = target.pool
+	}
+}
+
+// usePrimary 判断本次查询是否必须使用主库
+func (r *ReadWriteResolver) usePrimary(db *gorm.DB) bool {
+	// 事务内的查询：连接池已是事务对象，必须留在同一连接上
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return true
+	}
+	// 加锁查询（SELECT ... FOR UPDATE / FOR SHARE）
+	if _, locking := db.Statement.Clauses["FOR"]; locking {
+		return true
+	}
+	// 通过 UsePrimary 作用域显式指定
+	if primary, ok := db.Get(usePrimaryKey); ok && primary == true {
+		return true
+	}
+	// 通过 WithPrimary 上下文显式指定
+	if ctx := db.Statement.Context; ctx != nil {
+		if primary, _ := ctx.Value(primaryContextKey{}).(bool); primary {
+			return true
+		}
+	}
+	return false
+}
+
+// pick 按负载均衡策略从健康的副本中选择一个，没有健康副本时返回 nil（回退到主库）
+func (r *ReadWriteResolver) pick() *replica {
+	healthy := make([]*replica, 0, len(r.replicas))
+	for _, rep := range r.replicas {
+		if rep.healthy.Load() {
+			healthy = append(healthy, rep)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	switch r.policy {
+	case "random":
+		return healthy[rand.IntN(len(healthy))]
+	case "least_conn":
+		// 选择正在使用的连接数最少的副本
+		target := healthy[0]
+		minInUse := target.pool.Stats().InUse
+		for _, rep := range healthy[1:] {
+			if inUse := rep.pool.Stats().InUse; inUse < minInUse {
+				target, minInUse = rep, inUse
+			}
+		}
+		return target
+	default:
+		// 轮询
+		return healthy[(r.counter.Add(1)-1)%uint64(len(healthy))]
+	}
+}
+
+// healthCheckLoop 定期检查副本健康状态，直到插件关闭
+func (r *ReadWriteResolver) healthCheckLoop() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.checkReplicas()
+		}
+	}
+}
+
+// checkReplicas 逐个 Ping 副本，状态变化时记录日志
+func (r *ReadWriteResolver) checkReplicas() {
+	for _, rep := range r.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), r.interval/2)
+		err := rep.pool.PingContext(ctx)
+		cancel()
+
+		healthy := err == nil
+		if previous := rep.healthy.Swap(healthy); previous != healthy {
+			if healthy {
+				logrus.WithField("replica", rep.name).Info("只读副本恢复，重新加入负载均衡")
+			} else {
+				logrus.WithError(err).WithField("replica", rep.name).Warn("只读副本健康检查失败，暂时移出负载均衡")
+			}
+		}
+	}
+}
+
+// Stats 返回各副本的连接池统计信息，键为副本标识（host:port）
+func (r *ReadWriteResolver) Stats() map[string]ReplicaStats {
+	stats := make(map[string]ReplicaStats, len(r.replicas))
+	for _, rep := range r.replicas {
+		stats[rep.name] = ReplicaStats{Healthy: rep.healthy.Load(), Pool: rep.pool.Stats()}
+	}
+	return stats
+}
+
+// ReplicaStats 只读副本状态
+type ReplicaStats struct {
+	Healthy bool        `json:"healthy"`
+	Pool    sql.DBStats `json:"pool"`
+}
+
+// Close 停止健康检查并关闭所有副本连接
+func (r *ReadWriteResolver) Close() {
+	r.once.Do(func() {
+		close(r.stop)
+		for _, rep := range r.replicas {
+			_ = rep.pool.Close()
+		}
+	})
+}
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// openReplicaTest 打开主库和指定名称的副本，每个库中只有一条以库名命名的记录，便于判断查询路由到了哪个库
+func openReplicaTest(t *testing.T, policy string, replicaNames ...string) (*gorm.DB, *ReadWriteResolver) {
+	t.Helper()
+	resolver := &ReadWriteResolver{policy: policy, interval: time.Hour, stop: make(chan struct{})}
+	for _, name := range replicaNames {
+		replicaDB := openTestDB(t, nil, &plainRecord{})
+		if err := replicaDB.Create(&plainRecord{Name: name}).Error; err != nil {
+			t.Fatal(err)
+		}
+		pool, err := replicaDB.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := &replica{name: name, pool: pool}
+		r.healthy.Store(true)
+		resolver.replicas = append(resolver.replicas, r)
+	}
+	t.Cleanup(resolver.Close)
+
+	// 迁移时检查表是否存在的查询也会被路由，因此先准备主库数据再注册插件
+	db := openTestDB(t, nil, &plainRecord{})
+	if err := db.Create(&plainRecord{Name: "primary"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(resolver); err != nil {
+		t.Fatal(err)
+	}
+	return db, resolver
+}
+
+// readFrom 执行查询并返回命中的库名
+func readFrom(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+	var record plainRecord
+	if err := db.First(&record).Error; err != nil {
+		t.Fatal(err)
+	}
+	return record.Name
+}
+
+func TestReadWriteResolverRoute(t *testing.T) {
+	db, _ := openReplicaTest(t, "round_robin", "replica")
+
+	tests := []struct {
+		name  string
+		query func(db *gorm.DB) *gorm.DB
+		want  string
+	}{
+		{"普通查询使用副本", func(db *gorm.DB) *gorm.DB { return db }, "replica"},
+		{"UsePrimary 作用域使用主库", func(db *gorm.DB) *gorm.DB { return db.Scopes(UsePrimary) }, "primary"},
+		{"WithPrimary 上下文使用主库", func(db *gorm.DB) *gorm.DB { return db.WithContext(WithPrimary(context.Background())) }, "primary"},
+		{"加锁查询使用主库", func(db *gorm.DB) *gorm.DB { return db.Clauses(clause.Locking{Strength: "UPDATE"}) }, "primary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readFrom(t, tt.query(db)); got != tt.want {
+				t.Errorf("查询命中 %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("事务内查询使用主库", func(t *testing.T) {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if got := readFrom(t, tx); got != "primary" {
+				t.Errorf("查询命中 %s, want primary", got)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Row 查询使用副本", func(t *testing.T) {
+		var name string
+		if err := db.Model(&plainRecord{}).Select("name").Row().Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		if name != "replica" {
+			t.Errorf("查询命中 %s, want replica", name)
+		}
+	})
+
+	t.Run("写操作使用主库", func(t *testing.T) {
+		if err := db.Create(&plainRecord{Name: "written"}).Error; err != nil {
+			t.Fatal(err)
+		}
+		var count int64
+		if err := db.Scopes(UsePrimary).Model(&plainRecord{}).Where("name = ?", "written").Count(&count).Error; err != nil || count != 1 {
+			t.Errorf("主库 Count() = %d, error = %v", count, err)
+		}
+	})
+}
+
+func TestReadWriteResolverRoundRobin(t *testing.T) {
+	db, _ := openReplicaTest(t, "round_robin", "r1", "r2")
+	var got []string
+	for range 4 {
+		got = append(got, readFrom(t, db))
+	}
+	want := []string{"r1", "r2", "r1", "r2"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("轮询顺序 = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestReadWriteResolverHealth(t *testing.T) {
+	db, resolver := openReplicaTest(t, "random", "r1", "r2")
+
+	// 关闭 r1 后健康检查将其移出负载均衡
+	if err := resolver.replicas[0].pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	resolver.checkReplicas()
+	if stats := resolver.Stats(); stats["r1"].Healthy || !stats["r2"].Healthy {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+	for range 5 {
+		if got := readFrom(t, db); got != "r2" {
+			t.Fatalf("查询命中 %s, want r2", got)
+		}
+	}
+
+	// 全部副本不可用时回退到主库
+	resolver.replicas[1].healthy.Store(false)
+	if got := readFrom(t, db); got != "primary" {
+		t.Errorf("查询命中 %s, want primary", got)
+	}
+}
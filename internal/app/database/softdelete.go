@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"gin-template/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// 软删除标记字段及取值
+const (
+	softDeleteColumn = "is_deleted"
+	softDeletedValue = "Y" // 已删除
+	softActiveValue  = "N" // 未删除
+)
+
+// 软删除查询范围在 GORM Statement 中的设置键
+const (
+	softDeleteModeKey    = "soft_delete:mode"
+	softDeleteAppliedKey = "soft_delete:applied"
+)
+
+// softDeleteMode 软删除查询范围
+type softDeleteMode int
+
+const (
+	softDeleteExclude softDeleteMode = iota // 默认：排除已删除数据
+	softDeleteInclude                       // 包含已删除数据
+	softDeleteOnly                          // 仅查询已删除数据
+)
+
+// SoftDeletePlugin 软删除插件
+// 模型包含 is_deleted 字段时，查询、更新、删除操作默认追加 is_deleted = 'N' 条件，
+// 使已软删除的数据对业务不可见，也无法被再次更新或物理删除。
+// 需要访问已删除数据时，使用 WithDeleted / OnlyDeleted 作用域显式声明。
+type SoftDeletePlugin struct{}
+
+// Name 插件名称
+func (SoftDeletePlugin) Name() string {
+	return "soft_delete"
+}
+
+// Initialize 注册软删除回调，在 GORM 执行 SQL 之前追加过滤条件
+func (SoftDeletePlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register("soft_delete:query", softDeleteCallback); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("soft_delete:row", softDeleteCallback); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("soft_delete:update", softDeleteCallback); err != nil {
+		return err
+	}
+	return db.Callback().Delete().Before("gorm:delete").Register("soft_delete:delete", softDeleteCallback)
+}
+
+// softDeleteCallback 根据查询范围为包含 is_deleted 字段的模型追加过滤条件
+func softDeleteCallback(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.LookUpField(softDeleteColumn)
+	if field == nil {
+		return
+	}
+
+	// 同一个 Statement 可能被执行多次（如先 Count 再 Find），避免重复追加条件
+	if _, applied := db.InstanceGet(softDeleteAppliedKey); applied {
+		return
+	}
+
+	mode := softDeleteExclude
+	if value, ok := db.Get(softDeleteModeKey); ok {
+		mode, _ = value.(softDeleteMode)
+	}
+
+	var flag string
+	switch mode {
+	case softDeleteInclude:
+		return
+	case softDeleteOnly:
+		flag = softDeletedValue
+	default:
+		flag = softActiveValue
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: flag},
+	}})
+	db.InstanceSet(softDeleteAppliedKey, true)
+}
+
+// WithDeleted 查询作用域：包含已软删除的数据
+// 用法：db.Scopes(database.WithDeleted).Find(&list)
+func WithDeleted(db *gorm.DB) *gorm.DB {
+	return db.Set(softDeleteModeKey, softDeleteInclude)
+}
+
+// OnlyDeleted 查询作用域：仅查询已软删除的数据
+// 用法：db.Scopes(database.OnlyDeleted).Find(&list)
+func OnlyDeleted(db *gorm.DB) *gorm.DB {
+	return db.Set(softDeleteModeKey, softDeleteOnly)
+}
+
+// SoftDeleteField 返回模型的软删除标记字段，模型不支持软删除时返回 nil
+func SoftDeleteField(sch *schema.Schema) *schema.Field {
+	return sch.LookUpField(softDeleteColumn)
+}
+
+// IsSoftDeleted 判断软删除标记字段的取值是否表示已删除
+func IsSoftDeleted(value any) bool {
+	flag, _ := value.(string)
+	return flag == softDeletedValue
+}
+
+// SoftDeleteUpdates 返回软删除需要更新的字段：删除标记、删除时间和删除人
+func SoftDeleteUpdates(ctx context.Context) map[string]interface{} {
+	var deletedBy interface{}
+	if operator := utils.GetOperator(ctx); operator != "" {
+		deletedBy = operator
+	}
+	return map[string]interface{}{
+		softDeleteColumn: softDeletedValue,
+		"deleted_at":     time.Now(),
+		"deleted_by":     deletedBy,
+	}
+}
+
+// RestoreUpdates 返回恢复软删除数据需要更新的字段，需配合 OnlyDeleted 作用域使用
+func RestoreUpdates() map[string]interface{} {
+	return map[string]interface{}{
+		softDeleteColumn: softActiveValue,
+		"deleted_at":     nil,
+		"deleted_by":     nil,
+	}
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"gin-template/internal/utils"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB 打开测试用的 SQLite 数据库（临时文件），注册指定插件并迁移模型
+func openTestDB(t *testing.T, plugins []gorm.Plugin, models ...any) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, plugin := range plugins {
+		if err := db.Use(plugin); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// softRecord 支持软删除的测试模型
+type softRecord struct {
+	ID        int
+	Name      string
+	IsDeleted string `gorm:"default:'N'"`
+	DeletedAt *time.Time
+	DeletedBy *string
+}
+
+// plainRecord 不支持软删除的测试模型
+type plainRecord struct {
+	ID   int
+	Name string
+}
+
+// names 返回记录名称（按字母顺序），便于比较
+func names(records []softRecord) []string {
+	result := make([]string, 0, len(records))
+	for _, record := range records {
+		result = append(result, record.Name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestSoftDeletePlugin(t *testing.T) {
+	db := openTestDB(t, []gorm.Plugin{SoftDeletePlugin{}}, &softRecord{}, &plainRecord{})
+	records := []softRecord{{Name: "a"}, {Name: "b"}, {Name: "c", IsDeleted: softDeletedValue}}
+	if err := db.Create(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		scopes []func(*gorm.DB) *gorm.DB
+		want   []string
+	}{
+		{"默认排除已删除", nil, []string{"a", "b"}},
+		{"包含已删除", []func(*gorm.DB) *gorm.DB{WithDeleted}, []string{"a", "b", "c"}},
+		{"仅已删除", []func(*gorm.DB) *gorm.DB{OnlyDeleted}, []string{"c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []softRecord
+			if err := db.Scopes(tt.scopes...).Find(&got).Error; err != nil {
+				t.Fatal(err)
+			}
+			if want := tt.want; !slices.Equal(names(got), want) {
+				t.Errorf("Find() = %v, want %v", names(got), want)
+			}
+		})
+	}
+
+	t.Run("先 Count 再 Find 不重复追加条件", func(t *testing.T) {
+		var count int64
+		var got []softRecord
+		query := db.Model(&softRecord{})
+		if err := query.Count(&count).Find(&got).Error; err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 || len(got) != 2 {
+			t.Errorf("Count() = %d, Find() = %d 条", count, len(got))
+		}
+	})
+
+	t.Run("按主键查询不到已删除数据", func(t *testing.T) {
+		var got softRecord
+		if err := db.First(&got, records[2].ID).Error; err != gorm.ErrRecordNotFound {
+			t.Errorf("First() error = %v, want ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("更新和删除不命中已删除数据", func(t *testing.T) {
+		if result := db.Model(&softRecord{}).Where("1 = 1").Update("name", "x"); result.RowsAffected != 2 {
+			t.Errorf("Update() RowsAffected = %d, want 2", result.RowsAffected)
+		}
+		if result := db.Where("1 = 1").Delete(&softRecord{}); result.RowsAffected != 2 {
+			t.Errorf("Delete() RowsAffected = %d, want 2", result.RowsAffected)
+		}
+		var remaining []softRecord
+		db.Scopes(WithDeleted).Find(&remaining)
+		if !slices.Equal(names(remaining), []string{"c"}) {
+			t.Errorf("剩余数据 = %v, want [c]", names(remaining))
+		}
+	})
+
+	t.Run("不支持软删除的模型不追加条件", func(t *testing.T) {
+		if err := db.Create(&plainRecord{Name: "p"}).Error; err != nil {
+			t.Fatal(err)
+		}
+		var count int64
+		if err := db.Model(&plainRecord{}).Count(&count).Error; err != nil || count != 1 {
+			t.Errorf("Count() = %d, error = %v", count, err)
+		}
+	})
+}
+
+func TestSoftDeleteAndRestoreUpdates(t *testing.T) {
+	db := openTestDB(t, []gorm.Plugin{SoftDeletePlugin{}}, &softRecord{})
+	record := softRecord{Name: "a"}
+	if err := db.Create(&record).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := utils.WithOperator(context.Background(), "bob")
+	if err := db.WithContext(ctx).Model(&softRecord{}).Where("id = ?", record.ID).Updates(SoftDeleteUpdates(ctx)).Error; err != nil {
+		t.Fatal(err)
+	}
+	var deleted softRecord
+	if err := db.Scopes(OnlyDeleted).First(&deleted, record.ID).Error; err != nil {
+		t.Fatalf("软删除后 OnlyDeleted First() error = %v", err)
+	}
+	if !IsSoftDeleted(deleted.IsDeleted) || deleted.DeletedAt == nil || deleted.DeletedBy == nil || *deleted.DeletedBy != "bob" {
+		t.Errorf("软删除后 = %+v", deleted)
+	}
+
+	// 恢复需配合 OnlyDeleted 作用域，否则默认条件排除已删除数据
+	if result := db.Model(&softRecord{}).Where("id = ?", record.ID).Updates(RestoreUpdates()); result.RowsAffected != 0 {
+		t.Errorf("未使用 OnlyDeleted 恢复 RowsAffected = %d, want 0", result.RowsAffected)
+	}
+	if err := db.Model(&softRecord{}).Scopes(OnlyDeleted).Where("id = ?", record.ID).Updates(RestoreUpdates()).Error; err != nil {
+		t.Fatal(err)
+	}
+	var restored softRecord
+	if err := db.First(&restored, record.ID).Error; err != nil {
+		t.Fatalf("恢复后 First() error = %v", err)
+	}
+	if IsSoftDeleted(restored.IsDeleted) || restored.DeletedAt != nil || restored.DeletedBy != nil {
+		t.Errorf("恢复后 = %+v", restored)
+	}
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"gin-template/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// 操作人字段
+const (
+	createdByColumn = "created_by"
+	updatedByColumn = "updated_by"
+)
+
+// TrackingPlugin 操作人插件
+// 模型包含 created_by / updated_by 字段时（如嵌入 model.BaseModel），创建时填充创建人和更新人，
+// 更新时（包括按 map 更新、软删除和恢复）填充更新人；取值为上下文中的操作人，未认证时不填充。
+type TrackingPlugin struct{}
+
+// Name 插件名称
+func (TrackingPlugin) Name() string {
+	return "tracking"
+}
+
+// Initialize 注册操作人回调，在 GORM 执行 SQL 之前设置字段取值
+func (TrackingPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("tracking:create", trackCreate); err != nil {
+		return err
+	}
+	return db.Callback().Update().Before("gorm:update").Register("tracking:update", trackUpdate)
+}
+
+// trackCreate 创建时为所有记录填充创建人和更新人
+func trackCreate(db *gorm.DB) {
+	operator, ok := trackingOperator(db)
+	if !ok {
+		return
+	}
+	for _, column := range []string{createdByColumn, updatedByColumn} {
+		if db.Statement.Schema.LookUpField(column) != nil {
+			db.Statement.SetColumn(column, operator, true)
+		}
+	}
+}
+
+// trackUpdate 更新时填充更新人，与 update_time 一致，UpdateColumn / UpdateColumns 不填充
+func trackUpdate(db *gorm.DB) {
+	operator, ok := trackingOperator(db)
+	if !ok || db.Statement.SkipHooks || db.Statement.Schema.LookUpField(updatedByColumn) == nil {
+		return
+	}
+	db.Statement.SetColumn(updatedByColumn, operator, true)
+}
+
+// trackingOperator 返回上下文中的操作人，语句出错、模型未解析或未认证时返回 false
+func trackingOperator(db *gorm.DB) (string, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return "", false
+	}
+	operator := utils.GetOperator(db.Statement.Context)
+	return operator, operator != ""
+}
+
+// UpdatedByField 返回模型的更新人字段，模型不包含该字段时返回 nil
+func UpdatedByField(sch *schema.Schema) *schema.Field {
+	return sch.LookUpField(updatedByColumn)
+}
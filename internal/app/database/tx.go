@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gin-template/internal/app/config"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// txContextKey 事务在上下文中的键
+type txContextKey struct{}
+
+// TxOptions 事务选项
+type TxOptions struct {
+	Isolation     sql.IsolationLevel // 隔离级别，sql.LevelDefault 表示使用数据库默认级别
+	ReadOnly      bool               // 是否只读事务
+	MaxRetries    int                // 遇到死锁或序列化失败时的最大重试次数，0 表示不重试
+	RetryInterval time.Duration      // 首次重试前的等待时间，之后每次翻倍
+}
+
+// TxManager 事务管理器接口，定义事务边界。服务层通过它让多个 Repository 调用在同一事务中执行
+type TxManager interface {
+	// WithinTx 使用默认选项在事务中执行 fn
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// WithinTxOptions 使用指定选项在事务中执行 fn
+	WithinTxOptions(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error
+}
+
+// GormTxManager 基于 GORM 的事务管理器实现
+type GormTxManager struct {
+	db   *gorm.DB
+	opts TxOptions // 默认事务选项
+}
+
+// NewTxManager 创建事务管理器实例
+func NewTxManager(db *gorm.DB, cfg config.TransactionConfig) TxManager {
+	return &GormTxManager{
+		db: db,
+		opts: TxOptions{
+			Isolation:     ParseIsolationLevel(cfg.IsolationLevel),
+			MaxRetries:    cfg.MaxRetries,
+			RetryInterval: cfg.RetryInterval,
+		},
+	}
+}
+
+// WithinTx 使用默认选项在事务中执行 fn
+func (m *GormTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.WithinTxOptions(ctx, m.opts, fn)
+}
+
+// WithinTxOptions 使用指定选项在事务中执行 fn
+//   - 事务对象存入传给 fn 的上下文，Repository 通过 DBFromContext 自动加入该事务
+//   - fn 返回错误或发生 panic 时回滚，否则提交
+//   - 上下文中已存在事务时，通过保存点（SAVEPOINT）实现嵌套事务，内层失败只回滚到保存点，隔离级别和重试选项不生效
+//   - 最外层事务遇到死锁或序列化失败时按选项重试，因此 fn 必须可以安全地重复执行
+func (m *GormTxManager) WithinTxOptions(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error {
+	// 嵌套事务：GORM 在已开启的事务中调用 Transaction 时自动使用保存点
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.WithContext(ctx).Transaction(func(inner *gorm.DB) error {
+			return fn(ContextWithTx(ctx, inner))
+		})
+	}
+
+	txOpts := &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly}
+	interval := opts.RetryInterval
+	for attempt := 0; ; attempt++ {
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(ContextWithTx(ctx, tx))
+		}, txOpts)
+		if err == nil || attempt >= opts.MaxRetries || !IsRetryableTxError(err) {
+			return err
+		}
+
+		// 等待后重试，上下文取消时立即返回
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(interval):
+		}
+		interval *= 2
+	}
+}
+
+// ContextWithTx 将事务存入上下文
+func ContextWithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext 从上下文中获取事务
+func TxFromContext(ctx context.Context) (*gorm.DB, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	tx, ok := ctx.Value(txContextKey{}).(*gorm.DB)
+	return tx, ok && tx != nil
+}
+
+// DBFromContext 返回绑定上下文的数据库会话：上下文中存在事务时使用该事务，否则使用 db
+func DBFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// ParseIsolationLevel 解析配置中的事务隔离级别，空值或无法识别时使用数据库默认级别
+func ParseIsolationLevel(level string) sql.IsolationLevel {
+	switch level {
+	case "read_uncommitted":
+		return sql.LevelReadUncommitted
+	case "read_committed":
+		return sql.LevelReadCommitted
+	case "repeatable_read":
+		return sql.LevelRepeatableRead
+	case "serializable":
+		return sql.LevelSerializable
+	default:
+		return sql.LevelDefault
+	}
+}
+
+// IsRetryableTxError 判断是否为可重试的事务错误（死锁、序列化失败）
+func IsRetryableTxError(err error) bool {
+	// 适配MySQL：1213 死锁，SQLSTATE 40001 序列化失败
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1213 || string(mysqlErr.SQLState[:]) == "40001"
+	}
+
+	// 适配PostgreSQL等提供 SQLSTATE 的驱动：40001 序列化失败，40P01 死锁
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		state := stateErr.SQLState()
+		return state == "40001" || state == "40P01"
+	}
+
+	return false
+}
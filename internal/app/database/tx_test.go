@@ -0,0 +1,257 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gin-template/internal/app/config"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// txRecord 事务测试模型
+type txRecord struct {
+	ID   int
+	Name string
+}
+
+// countRecords 返回 txRecord 的行数
+func countRecords(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(&txRecord{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+// insert 使用上下文中的事务（没有事务时使用 db）插入一行
+func insert(ctx context.Context, db *gorm.DB, name string) error {
+	return DBFromContext(ctx, db).Create(&txRecord{Name: name}).Error
+}
+
+func TestWithinTxCommitAndRollback(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name      string
+		fn        func(ctx context.Context, db *gorm.DB) error
+		wantErr   bool
+		wantPanic bool
+		wantRows  int64
+	}{
+		{
+			name: "成功时提交",
+			fn: func(ctx context.Context, db *gorm.DB) error {
+				if err := insert(ctx, db, "a"); err != nil {
+					return err
+				}
+				return insert(ctx, db, "b")
+			},
+			wantRows: 2,
+		},
+		{
+			name: "返回错误时回滚",
+			fn: func(ctx context.Context, db *gorm.DB) error {
+				if err := insert(ctx, db, "a"); err != nil {
+					return err
+				}
+				return errFailed
+			},
+			wantErr: true,
+		},
+		{
+			name: "panic 时回滚",
+			fn: func(ctx context.Context, db *gorm.DB) error {
+				if err := insert(ctx, db, "a"); err != nil {
+					return err
+				}
+				panic("boom")
+			},
+			wantPanic: true,
+		},
+		{
+			name: "嵌套事务失败只回滚到保存点",
+			fn: func(ctx context.Context, db *gorm.DB) error {
+				if err := insert(ctx, db, "outer"); err != nil {
+					return err
+				}
+				manager := NewTxManager(db, config.TransactionConfig{})
+				innerErr := manager.WithinTx(ctx, func(ctx context.Context) error {
+					if err := insert(ctx, db, "inner"); err != nil {
+						return err
+					}
+					return errFailed
+				})
+				if !errors.Is(innerErr, errFailed) {
+					return fmt.Errorf("内层事务 error = %v", innerErr)
+				}
+				return nil
+			},
+			wantRows: 1,
+		},
+		{
+			name: "嵌套事务失败导致外层失败时全部回滚",
+			fn: func(ctx context.Context, db *gorm.DB) error {
+				if err := insert(ctx, db, "outer"); err != nil {
+					return err
+				}
+				return NewTxManager(db, config.TransactionConfig{}).WithinTx(ctx, func(ctx context.Context) error {
+					if err := insert(ctx, db, "inner"); err != nil {
+						return err
+					}
+					return errFailed
+				})
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, nil, &txRecord{})
+			manager := NewTxManager(db, config.TransactionConfig{})
+
+			var err error
+			panicked := func() (panicked bool) {
+				defer func() {
+					if r := recover(); r != nil {
+						panicked = true
+					}
+				}()
+				err = manager.WithinTx(context.Background(), func(ctx context.Context) error {
+					if _, ok := TxFromContext(ctx); !ok {
+						t.Error("fn 的上下文中没有事务")
+					}
+					return tt.fn(ctx, db)
+				})
+				return false
+			}()
+
+			if panicked != tt.wantPanic {
+				t.Errorf("panic = %v, want %v", panicked, tt.wantPanic)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithinTx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := countRecords(t, db); got != tt.wantRows {
+				t.Errorf("行数 = %d, want %d", got, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestWithinTxRetry(t *testing.T) {
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}
+	tests := []struct {
+		name         string
+		maxRetries   int
+		failures     int
+		failWith     error
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"死锁后重试成功", 2, 1, deadlock, 2, false},
+		{"超过重试次数返回错误", 1, 5, deadlock, 2, true},
+		{"未开启重试", 0, 1, deadlock, 1, true},
+		{"不可重试的错误不重试", 3, 1, errors.New("failed"), 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, nil, &txRecord{})
+			manager := NewTxManager(db, config.TransactionConfig{})
+			attempts := 0
+			err := manager.WithinTxOptions(context.Background(), TxOptions{MaxRetries: tt.maxRetries, RetryInterval: time.Millisecond}, func(ctx context.Context) error {
+				attempts++
+				if err := insert(ctx, db, "a"); err != nil {
+					return err
+				}
+				if attempts <= tt.failures {
+					return tt.failWith
+				}
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithinTxOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("执行次数 = %d, want %d", attempts, tt.wantAttempts)
+			}
+			// 失败的尝试均已回滚，只保留成功的一次
+			wantRows := int64(0)
+			if !tt.wantErr {
+				wantRows = 1
+			}
+			if got := countRecords(t, db); got != wantRows {
+				t.Errorf("行数 = %d, want %d", got, wantRows)
+			}
+		})
+	}
+}
+
+func TestDBFromContext(t *testing.T) {
+	db := openTestDB(t, nil, &txRecord{})
+	if _, ok := TxFromContext(context.Background()); ok {
+		t.Error("空上下文 TxFromContext() ok = true")
+	}
+	tx := db.Begin()
+	defer tx.Rollback()
+	ctx := ContextWithTx(context.Background(), tx)
+	if got := DBFromContext(ctx, db); got.Statement.ConnPool != tx.Statement.ConnPool {
+		t.Error("DBFromContext() 未使用上下文中的事务")
+	}
+	if got := DBFromContext(context.Background(), db); got.Statement.ConnPool != db.Statement.ConnPool {
+		t.Error("DBFromContext() 未使用 db")
+	}
+}
+
+func TestParseIsolationLevel(t *testing.T) {
+	tests := map[string]sql.IsolationLevel{
+		"":                 sql.LevelDefault,
+		"read_uncommitted": sql.LevelReadUncommitted,
+		"read_committed":   sql.LevelReadCommitted,
+		"repeatable_read":  sql.LevelRepeatableRead,
+		"serializable":     sql.LevelSerializable,
+		"unknown":          sql.LevelDefault,
+	}
+	for level, want := range tests {
+		if got := ParseIsolationLevel(level); got != want {
+			t.Errorf("ParseIsolationLevel(%q) = %v, want %v", level, got, want)
+		}
+	}
+}
+
+// sqlStateError 提供 SQLSTATE 的驱动错误
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestIsRetryableTxError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"MySQL 死锁", &mysql.MySQLError{Number: 1213}, true},
+		{"MySQL 序列化失败", &mysql.MySQLError{Number: 1, SQLState: [5]byte{'4', '0', '0', '0', '1'}}, true},
+		{"MySQL 唯一索引冲突", &mysql.MySQLError{Number: 1062}, false},
+		{"包装的死锁", fmt.Errorf("x: %w", &mysql.MySQLError{Number: 1213}), true},
+		{"PostgreSQL 死锁", sqlStateError("40P01"), true},
+		{"PostgreSQL 序列化失败", sqlStateError("40001"), true},
+		{"其他 SQLSTATE", sqlStateError("23505"), false},
+		{"普通错误", errors.New("x"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableTxError(tt.err); got != tt.want {
+				t.Errorf("IsRetryableTxError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+)
+
+// utf8BOM Excel 打开 CSV 时依据 BOM 识别 UTF-8 编码
+const utf8BOM = "\ufeff"
+
+// csvWriter CSV 导出写入器，BOM 和表头同样经过缓冲，缓冲写满后才输出到底层
+type csvWriter struct {
+	buf    *bufio.Writer
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column) (*csvWriter, error) {
+	buf := bufio.NewWriter(w)
+	if _, err := buf.WriteString(utf8BOM); err != nil {
+		return nil, err
+	}
+	writer := &csvWriter{buf: buf, w: csv.NewWriter(buf), record: make([]string, len(columns))}
+	for i, column := range columns {
+		writer.record[i] = column.Header
+	}
+	if err := writer.w.Write(writer.record); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// WriteRow 写入一行
+func (c *csvWriter) WriteRow(values []any) error {
+	for i := range c.record {
+		c.record[i] = formatCell(values[i])
+	}
+	return c.w.Write(c.record)
+}
+
+// Close 刷新缓冲
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return err
+	}
+	return c.buf.Flush()
+}
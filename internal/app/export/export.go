@@ -0,0 +1,108 @@
+// Package export 流式导出：将逐行产生的数据写为 CSV、Excel（xlsx）或 NDJSON，不在内存中保留全部数据
+package export
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+)
+
+// Format 导出格式
+type Format string
+
+const (
+	FormatCSV    Format = "csv"    // CSV，带 UTF-8 BOM 以便 Excel 正确识别中文
+	FormatXLSX   Format = "xlsx"   // Excel 工作簿（单个工作表）
+	FormatNDJSON Format = "ndjson" // 每行一个 JSON 对象，键为列的 Key
+)
+
+// Column 导出列
+type Column struct {
+	Key    string // 列标识，与查询白名单中的字段名一致，NDJSON 中作为键
+	Header string // 表头（CSV / xlsx 首行），通常为已翻译的文本
+}
+
+// Writer 导出写入器，创建时写入表头，逐行写入后必须调用 Close 输出剩余内容
+// 写入器自带缓冲，缓冲写满前不会向底层输出，调用方可据此在输出开始前改为返回错误响应
+type Writer interface {
+	// WriteRow 写入一行，values 与列一一对应
+	WriteRow(values []any) error
+	// Close 写入结尾内容并刷新缓冲，不关闭底层的 io.Writer
+	Close() error
+}
+
+// NewWriter 按格式创建导出写入器
+func NewWriter(format Format, w io.Writer, columns []Column) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	case FormatNDJSON:
+		return newNDJSONWriter(w, columns), nil
+	default:
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+}
+
+// ContentType 返回导出格式的 Content-Type
+func ContentType(format Format) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// ContentDisposition 返回以附件形式下载的 Content-Disposition，文件名为 名称-时间.扩展名（UTC 时间）
+func ContentDisposition(name string, format Format, now time.Time) string {
+	filename := fmt.Sprintf("%s-%s.%s", name, now.UTC().Format("20060102T150405Z"), format)
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
+
+// formatValue 将单元格取值转换为文本：指针取其指向的值，nil 为空字符串，时间按 RFC 3339 输出
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formulaPrefixes 电子表格软件将以这些字符开头的单元格解析为公式
+const formulaPrefixes = "=+-@\t\r"
+
+// formatCell 将 CSV / xlsx 单元格取值转换为文本，文本取值以公式字符开头时前加单引号，防止 CSV 注入
+// 数值（如 -5）不是用户输入的文本，不做处理
+func formatCell(value any) string {
+	text := formatValue(value)
+	switch value.(type) {
+	case string, *string:
+		if text != "" && strings.ContainsRune(formulaPrefixes, rune(text[0])) {
+			return "'" + text
+		}
+	}
+	return text
+}
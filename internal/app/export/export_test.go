@@ -0,0 +1,193 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testColumns 测试用的导出列
+var testColumns = []Column{{Key: "id", Header: "编号"}, {Key: "name", Header: "名称"}, {Key: "time", Header: "时间"}}
+
+// testTime 测试用的时间取值
+var testTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// writeAll 使用指定格式写入全部行并返回输出内容
+func writeAll(t *testing.T, format Format, rows [][]any) string {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := NewWriter(format, &buf, testColumns)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow() error = %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestFormatCell(t *testing.T) {
+	name := "=cmd"
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"空值", nil, ""},
+		{"普通文本", "abc", "abc"},
+		{"等号开头", "=1+1", "'=1+1"},
+		{"加号开头", "+1", "'+1"},
+		{"减号开头", "-1", "'-1"},
+		{"@ 开头", "@SUM(A1)", "'@SUM(A1)"},
+		{"制表符开头", "\t=1", "'\t=1"},
+		{"回车开头", "\r=1", "'\r=1"},
+		{"中间的公式字符不处理", "a=b", "a=b"},
+		{"字符串指针", &name, "'=cmd"},
+		{"空字符串指针", (*string)(nil), ""},
+		{"负数不处理", -5, "-5"},
+		{"时间", testTime, "2024-01-02T03:04:05Z"},
+		{"时间指针", &testTime, "2024-01-02T03:04:05Z"},
+		{"空时间指针", (*time.Time)(nil), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatCell(tt.value); got != tt.want {
+				t.Errorf("formatCell() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		rows [][]any
+		want string
+	}{
+		{"只有表头", nil, "\ufeff编号,名称,时间\n"},
+		{"基本取值", [][]any{{1, "a", testTime}}, "\ufeff编号,名称,时间\n1,a,2024-01-02T03:04:05Z\n"},
+		{"逗号和引号需要引用", [][]any{{2, `a,"b"`, nil}}, "\ufeff编号,名称,时间\n2,\"a,\"\"b\"\"\",\n"},
+		{"公式前加单引号", [][]any{{-3, "=HYPERLINK(\"x\")", nil}}, "\ufeff编号,名称,时间\n-3,\"'=HYPERLINK(\"\"x\"\")\",\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := writeAll(t, FormatCSV, tt.rows); got != tt.want {
+				t.Errorf("CSV = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		rows [][]any
+		want string
+	}{
+		{"无数据", nil, ""},
+		{"键按列顺序并保持类型", [][]any{{1, "a", testTime}}, `{"id":1,"name":"a","time":"2024-01-02T03:04:05Z"}` + "\n"},
+		{"空值和 HTML 字符", [][]any{{2, "<b>&", (*time.Time)(nil)}}, `{"id":2,"name":"<b>&","time":null}` + "\n"},
+		{"公式不转义", [][]any{{3, "=1+1", nil}}, `{"id":3,"name":"=1+1","time":null}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := writeAll(t, FormatNDJSON, tt.rows); got != tt.want {
+				t.Errorf("NDJSON = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	output := writeAll(t, FormatXLSX, [][]any{
+		{1, "a<b", testTime},
+		{2.5, "=1+1", nil},
+		{true, "", nil},
+	})
+
+	archive, err := zip.NewReader(strings.NewReader(output), int64(len(output)))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+	var sheet string
+	names := make(map[string]bool)
+	for _, file := range archive.File {
+		names[file.Name] = true
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			r, err := file.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := io.ReadAll(r)
+			r.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sheet = string(content)
+		}
+	}
+	for _, part := range xlsxParts {
+		if !names[part.name] {
+			t.Errorf("缺少部件 %s", part.name)
+		}
+	}
+
+	wants := []string{
+		`<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">编号</t></is></c>`,
+		`<c r="A2"><v>1</v></c><c r="B2" t="inlineStr"><is><t xml:space="preserve">a&lt;b</t></is></c><c r="C2" t="inlineStr"><is><t xml:space="preserve">2024-01-02T03:04:05Z</t></is></c></row>`,
+		`<row r="3"><c r="A3"><v>2.5</v></c><c r="B3" t="inlineStr"><is><t xml:space="preserve">&#39;=1+1</t></is></c></row>`,
+		`<row r="4"><c r="A4" t="b"><v>1</v></c></row>`,
+		`</sheetData></worksheet>`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("工作表缺少 %s\n工作表: %s", want, sheet)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if got := columnName(tt.index); got != tt.want {
+			t.Errorf("columnName(%d) = %q, want %q", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestNewWriterUnsupportedFormat(t *testing.T) {
+	if _, err := NewWriter("pdf", io.Discard, testColumns); err == nil {
+		t.Error("NewWriter() error = nil")
+	}
+}
+
+func TestContentDisposition(t *testing.T) {
+	now := time.Date(2024, 1, 1, 16, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	want := `attachment; filename=demo-20240101T080000Z.csv`
+	if got := ContentDisposition("demo", FormatCSV, now); got != want {
+		t.Errorf("ContentDisposition() = %q, want %q", got, want)
+	}
+}
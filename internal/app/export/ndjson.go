@@ -0,0 +1,63 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ndjsonWriter NDJSON 导出写入器，每行一个以列 Key 为键的 JSON 对象，键按列顺序输出，保持数值、布尔等原始类型
+type ndjsonWriter struct {
+	w       *bufio.Writer
+	keys    [][]byte      // 已编码的键（含冒号）
+	value   bytes.Buffer  // 单个取值的编码缓冲
+	encoder *json.Encoder // 写入 value，不转义 HTML 字符
+}
+
+func newNDJSONWriter(w io.Writer, columns []Column) *ndjsonWriter {
+	keys := make([][]byte, len(columns))
+	for i, column := range columns {
+		key, _ := json.Marshal(column.Key)
+		keys[i] = append(key, ':')
+	}
+	writer := &ndjsonWriter{w: bufio.NewWriter(w), keys: keys}
+	writer.encoder = json.NewEncoder(&writer.value)
+	writer.encoder.SetEscapeHTML(false)
+	return writer
+}
+
+// WriteRow 写入一行，时间按 RFC 3339 输出
+func (n *ndjsonWriter) WriteRow(values []any) error {
+	n.w.WriteByte('{')
+	for i, key := range n.keys {
+		if i > 0 {
+			n.w.WriteByte(',')
+		}
+		value := values[i]
+		switch v := value.(type) {
+		case time.Time, *time.Time:
+			if text := formatValue(v); text != "" {
+				value = text
+			} else {
+				value = nil
+			}
+		}
+		n.value.Reset()
+		if err := n.encoder.Encode(value); err != nil {
+			return err
+		}
+		n.w.Write(key)
+		// Encode 在取值后追加换行，写入时去掉
+		n.w.Write(bytes.TrimSuffix(n.value.Bytes(), []byte("\n")))
+	}
+	// bufio.Writer 出错后的写入均返回同一错误，只需检查最后一次
+	_, err := n.w.WriteString("}\n")
+	return err
+}
+
+// Close 刷新缓冲
+func (n *ndjsonWriter) Close() error {
+	return n.w.Flush()
+}
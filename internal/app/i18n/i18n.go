@@ -0,0 +1,259 @@
+// Package i18n 多语言消息支持：加载各语言的消息目录，按消息键和参数翻译面向用户的提示信息
+package i18n
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"gin-template/internal/app/config"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// 内置消息目录，文件名（不含扩展名）即语言标签，如 zh-CN.yaml
+//
+//go:embed locales/*.yaml
+var embeddedLocales embed.FS
+
+// Bundle 消息目录集合，保存所有语言的消息并负责翻译
+type Bundle struct {
+	mu            sync.RWMutex
+	defaultLocale string                       // 默认语言，找不到对应语言的消息时回退到默认语言
+	messages      map[string]map[string]string // 语言 -> 消息键 -> 消息模板
+	templates     sync.Map                     // 已解析的消息模板缓存，键为 "语言|消息键"
+	matcher       language.Matcher             // 语言匹配器，根据已加载的语言构建
+	locales       []string                     // 已加载的语言列表，顺序与 matcher 一致
+}
+
+// NewBundle 创建消息目录集合
+func NewBundle(defaultLocale string) *Bundle {
+	return &Bundle{
+		defaultLocale: defaultLocale,
+		messages:      make(map[string]map[string]string),
+	}
+}
+
+// LoadFS 从文件系统目录加载消息目录，支持 .yaml/.yml/.json 文件
+// 同一语言多次加载时，后加载的消息覆盖先加载的同名消息键
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("读取消息目录失败: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, filepath.ToSlash(filepath.Join(dir, name)))
+		if err != nil {
+			return fmt.Errorf("读取消息文件 %s 失败: %w", name, err)
+		}
+		if err := b.AddMessages(strings.TrimSuffix(name, filepath.Ext(name)), ext, data); err != nil {
+			return fmt.Errorf("解析消息文件 %s 失败: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// AddMessages 解析一份消息目录数据并加入指定语言，嵌套结构会展开为以 "." 连接的消息键
+func (b *Bundle) AddMessages(locale, ext string, data []byte) error {
+	var raw map[string]any
+	var err error
+	if ext == ".json" {
+		err = json.Unmarshal(data, &raw)
+	} else {
+		err = yaml.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return err
+	}
+
+	flat := make(map[string]string)
+	flattenMessages("", raw, flat)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.messages[locale] == nil {
+		b.messages[locale] = make(map[string]string)
+	}
+	for key, msg := range flat {
+		b.messages[locale][key] = msg
+	}
+	b.templates.Clear()
+	b.buildMatcher()
+
+	return nil
+}
+
+// flattenMessages 将嵌套的消息结构展开为扁平的 "a.b.c" 键
+func flattenMessages(prefix string, raw map[string]any, out map[string]string) {
+	for key, value := range raw {
+		fullKey := key
+		if prefix != "" {
+			fullKey = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			flattenMessages(fullKey, v, out)
+		case nil:
+			continue
+		default:
+			out[fullKey] = fmt.Sprint(v)
+		}
+	}
+}
+
+// buildMatcher 根据已加载的语言重建语言匹配器，默认语言排在首位作为匹配失败时的结果
+// 调用方需持有写锁
+func (b *Bundle) buildMatcher() {
+	locales := make([]string, 0, len(b.messages))
+	for locale := range b.messages {
+		if locale != b.defaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+	locales = append([]string{b.defaultLocale}, locales...)
+
+	tags := make([]language.Tag, 0, len(locales))
+	for _, locale := range locales {
+		tags = append(tags, language.Make(locale))
+	}
+	b.locales = locales
+	b.matcher = language.NewMatcher(tags)
+}
+
+// DefaultLocale 返回默认语言
+func (b *Bundle) DefaultLocale() string {
+	return b.defaultLocale
+}
+
+// Match 按优先级从候选语言中匹配一个已加载的语言，匹配失败时返回默认语言
+// 候选值可以是语言标签（如 en-US），也可以是 Accept-Language 请求头的完整内容（如 "en-US,en;q=0.9"）
+func (b *Bundle) Match(candidates ...string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.matcher == nil {
+		return b.defaultLocale
+	}
+
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		tags, _, err := language.ParseAcceptLanguage(candidate)
+		if err != nil || len(tags) == 0 {
+			continue
+		}
+		if _, index, confidence := b.matcher.Match(tags...); confidence != language.No {
+			return b.locales[index]
+		}
+	}
+
+	return b.defaultLocale
+}
+
+// Has 判断指定语言或默认语言中是否存在消息键
+func (b *Bundle) Has(locale, key string) bool {
+	_, _, ok := b.lookup(locale, key)
+	return ok
+}
+
+// Translate 翻译消息键，依次查找指定语言、默认语言，均不存在时原样返回消息键
+// 因此未登记在消息目录中的普通文本也可以直接作为消息使用
+func (b *Bundle) Translate(locale, key string, params map[string]any) string {
+	foundLocale, msg, ok := b.lookup(locale, key)
+	if !ok {
+		return key
+	}
+	if len(params) == 0 || !strings.Contains(msg, "{{") {
+		return msg
+	}
+
+	// 解析并缓存消息模板
+	cacheKey := foundLocale + "|" + key
+	cached, ok := b.templates.Load(cacheKey)
+	if !ok {
+		tmpl, err := template.New(cacheKey).Option("missingkey=zero").Parse(msg)
+		if err != nil {
+			return msg
+		}
+		cached, _ = b.templates.LoadOrStore(cacheKey, tmpl)
+	}
+
+	var buf bytes.Buffer
+	if err := cached.(*template.Template).Execute(&buf, params); err != nil {
+		return msg
+	}
+	return buf.String()
+}
+
+// lookup 查找消息模板，返回实际命中的语言
+func (b *Bundle) lookup(locale, key string) (string, string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if msg, ok := b.messages[locale][key]; ok {
+		return locale, msg, true
+	}
+	if msg, ok := b.messages[b.defaultLocale][key]; ok {
+		return b.defaultLocale, msg, true
+	}
+	return "", "", false
+}
+
+// 全局消息目录集合，默认只包含内置消息目录，服务启动时通过 Init 按配置重新初始化
+var bundle = newEmbeddedBundle("zh-CN")
+
+// newEmbeddedBundle 创建仅包含内置消息目录的集合（内置文件随程序编译，加载失败只可能是编码问题，忽略错误）
+func newEmbeddedBundle(defaultLocale string) *Bundle {
+	b := NewBundle(defaultLocale)
+	_ = b.LoadFS(embeddedLocales, "locales")
+	return b
+}
+
+// Init 根据配置初始化全局消息目录：先加载内置消息目录，再加载配置的外部目录（可覆盖内置消息）
+func Init(cfg config.I18nConfig) error {
+	b := newEmbeddedBundle(cfg.DefaultLocale)
+	if cfg.Dir != "" {
+		if err := b.LoadFS(os.DirFS(cfg.Dir), "."); err != nil {
+			return err
+		}
+	}
+	bundle = b
+	return nil
+}
+
+// Default 返回全局消息目录集合
+func Default() *Bundle {
+	return bundle
+}
+
+// T 使用全局消息目录翻译消息键
+func T(locale, key string, params map[string]any) string {
+	return bundle.Translate(locale, key, params)
+}
+
+// Match 使用全局消息目录匹配语言
+func Match(candidates ...string) string {
+	return bundle.Match(candidates...)
+}
@@ -0,0 +1,115 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+// testBundle 创建包含 zh-CN、en-US 两种语言的测试消息目录，默认语言为 zh-CN
+func testBundle(t *testing.T) *Bundle {
+	t.Helper()
+	b := NewBundle("zh-CN")
+	fsys := fstest.MapFS{
+		"locales/zh-CN.yaml": {Data: []byte("common:\n  success: 成功\ndemo:\n  duplicate: \"{{.field}} 重复\"\n  only_zh: 仅中文\n")},
+		"locales/en-US.json": {Data: []byte(`{"common": {"success": "Success"}, "demo": {"duplicate": "{{.field}} duplicated"}}`)},
+		"locales/README.md":  {Data: []byte("ignored")},
+		"locales/sub/x.yaml": {Data: []byte("ignored: true")},
+	}
+	if err := b.LoadFS(fsys, "locales"); err != nil {
+		t.Fatalf("LoadFS() error = %v", err)
+	}
+	return b
+}
+
+func TestBundleTranslate(t *testing.T) {
+	b := testBundle(t)
+	tests := []struct {
+		name   string
+		locale string
+		key    string
+		params map[string]any
+		want   string
+	}{
+		{"指定语言", "en-US", "common.success", nil, "Success"},
+		{"嵌套键展开", "zh-CN", "common.success", nil, "成功"},
+		{"模板参数", "en-US", "demo.duplicate", map[string]any{"field": "field1"}, "field1 duplicated"},
+		{"缺少参数为空值", "zh-CN", "demo.duplicate", map[string]any{"other": 1}, "<no value> 重复"},
+		{"回退到默认语言", "en-US", "demo.only_zh", nil, "仅中文"},
+		{"未知语言回退到默认语言", "fr-FR", "common.success", nil, "成功"},
+		{"未登记的键原样返回", "en-US", "plain text", nil, "plain text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Translate(tt.locale, tt.key, tt.params); got != tt.want {
+				t.Errorf("Translate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if !b.Has("en-US", "demo.only_zh") || b.Has("en-US", "missing") {
+		t.Error("Has() 未回退到默认语言或误判不存在的键")
+	}
+}
+
+func TestBundleAddMessagesOverrides(t *testing.T) {
+	b := testBundle(t)
+	// 外部目录覆盖内置消息，已缓存的模板随之失效
+	b.Translate("en-US", "demo.duplicate", map[string]any{"field": "x"})
+	if err := b.AddMessages("en-US", ".yaml", []byte("demo:\n  duplicate: \"{{.field}} exists\"\n")); err != nil {
+		t.Fatalf("AddMessages() error = %v", err)
+	}
+	if got := b.Translate("en-US", "demo.duplicate", map[string]any{"field": "x"}); got != "x exists" {
+		t.Errorf("覆盖后 Translate() = %q", got)
+	}
+	if got := b.Translate("en-US", "common.success", nil); got != "Success" {
+		t.Errorf("未覆盖的键 Translate() = %q", got)
+	}
+	if err := b.AddMessages("en-US", ".json", []byte("{bad")); err == nil {
+		t.Error("无效的 JSON AddMessages() error = nil")
+	}
+}
+
+func TestBundleMatch(t *testing.T) {
+	b := testBundle(t)
+	tests := []struct {
+		name       string
+		candidates []string
+		want       string
+	}{
+		{"无候选值时为默认语言", nil, "zh-CN"},
+		{"精确匹配", []string{"en-US"}, "en-US"},
+		{"只有语种", []string{"en"}, "en-US"},
+		{"其他地区", []string{"en-GB"}, "en-US"},
+		{"按权重选择", []string{"fr;q=0.9, en-US;q=0.8, zh-CN;q=0.1"}, "en-US"},
+		{"前面的候选值优先", []string{"en-US", "zh-CN"}, "en-US"},
+		{"跳过空值和无效值", []string{"", "!!!", "en-US"}, "en-US"},
+		{"无法匹配时为默认语言", []string{"fr-FR"}, "zh-CN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.Match(tt.candidates...); got != tt.want {
+				t.Errorf("Match(%q) = %q, want %q", tt.candidates, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddedLocalesHaveSameKeys(t *testing.T) {
+	b := newEmbeddedBundle("zh-CN")
+	zh, en := b.messages["zh-CN"], b.messages["en-US"]
+	if len(zh) == 0 || len(en) == 0 {
+		t.Fatalf("内置消息目录未加载: zh-CN %d 条, en-US %d 条", len(zh), len(en))
+	}
+	for key := range zh {
+		if _, ok := en[key]; !ok {
+			t.Errorf("en-US 缺少消息键 %s", key)
+		}
+	}
+	for key := range en {
+		if _, ok := zh[key]; !ok {
+			t.Errorf("zh-CN 缺少消息键 %s", key)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// utf8BOM Excel 另存的 CSV 文件通常带有 BOM
+const utf8BOM = "\ufeff"
+
+// ErrDuplicateColumn CSV 表头中同一字段出现多次
+var ErrDuplicateColumn = errors.New("重复的列")
+
+// HeaderError CSV 表头无效，无法继续读取
+type HeaderError struct {
+	Column string // 无效的列名
+	Err    error  // ErrUnknownField / ErrDuplicateColumn
+}
+
+func (e *HeaderError) Error() string {
+	return fmt.Sprintf("表头列 %s: %v", e.Column, e.Err)
+}
+
+// Unwrap 返回错误类别，便于通过 errors.Is 判断
+func (e *HeaderError) Unwrap() error {
+	return e.Err
+}
+
+// csvReader CSV 导入读取器，首行为表头，列名与结构体字段的 JSON 名称匹配（不区分大小写），空单元格保留字段零值
+type csvReader struct {
+	r       *csv.Reader
+	header  []string
+	typ     reflect.Type // 字段映射对应的结构体类型
+	columns [][]int      // 每列对应的字段下标路径
+}
+
+func newCSVReader(r io.Reader) *csvReader {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	reader.TrimLeadingSpace = true
+	return &csvReader{r: reader}
+}
+
+// Next 读取下一行数据
+func (c *csvReader) Next(dst any) (int, error) {
+	target := reflect.ValueOf(dst).Elem()
+	if c.header == nil {
+		if err := c.readHeader(); err != nil {
+			return 0, err
+		}
+	}
+	if c.typ != target.Type() {
+		if err := c.mapColumns(target.Type()); err != nil {
+			return 0, err
+		}
+	}
+
+	record, err := c.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return parseErr.StartLine, &RowError{Line: parseErr.StartLine, Err: ErrMalformed}
+		}
+		return 0, err
+	}
+	line, _ := c.r.FieldPos(0)
+
+	target.SetZero()
+	for i, value := range record {
+		if value == "" {
+			continue
+		}
+		if err := setValue(target.FieldByIndex(c.columns[i]), value); err != nil {
+			return line, &RowError{Line: line, Field: c.header[i], Err: ErrInvalidValue}
+		}
+	}
+	return line, nil
+}
+
+// readHeader 读取表头，列数决定后续每行的列数
+func (c *csvReader) readHeader() error {
+	record, err := c.r.Read()
+	if err != nil {
+		return err
+	}
+	c.header = make([]string, len(record))
+	for i, name := range record {
+		if i == 0 {
+			name = strings.TrimPrefix(name, utf8BOM)
+		}
+		c.header[i] = strings.TrimSpace(name)
+	}
+	return nil
+}
+
+// mapColumns 将表头各列映射到结构体字段
+func (c *csvReader) mapColumns(typ reflect.Type) error {
+	fields := make(map[string][]int)
+	for _, field := range reflect.VisibleFields(typ) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		if name := jsonName(field); name != "" {
+			fields[strings.ToLower(name)] = field.Index
+		}
+	}
+
+	columns := make([][]int, len(c.header))
+	seen := make(map[string]bool, len(c.header))
+	for i, name := range c.header {
+		key := strings.ToLower(name)
+		index, ok := fields[key]
+		if !ok {
+			return &HeaderError{Column: name, Err: ErrUnknownField}
+		}
+		if seen[key] {
+			return &HeaderError{Column: name, Err: ErrDuplicateColumn}
+		}
+		seen[key] = true
+		columns[i] = index
+	}
+	c.typ, c.columns = typ, columns
+	return nil
+}
+
+// setValue 将单元格文本转换为字段类型并赋值，指针字段先分配再赋值
+func setValue(field reflect.Value, text string) error {
+	if field.Kind() == reflect.Pointer {
+		value := reflect.New(field.Type().Elem())
+		if err := setValue(value.Elem(), text); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(text), 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(text), 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("不支持的字段类型: %s", field.Type())
+	}
+	return nil
+}
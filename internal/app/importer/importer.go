@@ -0,0 +1,111 @@
+// Package importer 流式导入：逐行读取 CSV 或 NDJSON 文件并解码到结构体，单行格式错误不影响后续行的读取
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Format 导入文件格式
+type Format string
+
+const (
+	FormatCSV    Format = "csv"    // CSV，首行为表头（字段的 JSON 名称），允许带 UTF-8 BOM
+	FormatNDJSON Format = "ndjson" // 每行一个 JSON 对象，空行忽略
+)
+
+// maxLineSize 单行数据的最大字节数，超过时停止读取，避免异常文件占用过多内存
+const maxLineSize = 1 << 20
+
+var (
+	// ErrInvalidValue 字段取值无法转换为目标类型
+	ErrInvalidValue = errors.New("字段取值无效")
+	// ErrUnknownField 数据中包含目标结构体不存在的字段
+	ErrUnknownField = errors.New("未知字段")
+	// ErrMalformed 行格式错误（如 JSON 语法错误、CSV 列数与表头不一致）
+	ErrMalformed = errors.New("行格式错误")
+	// ErrLineTooLong 单行数据超过长度上限，无法继续读取
+	ErrLineTooLong = errors.New("单行数据过长")
+)
+
+// RowError 单行数据解码失败，调用方记录后可继续读取下一行
+type RowError struct {
+	Line  int    // 行号（从 1 开始，CSV 含表头行）
+	Field string // 出错的字段（JSON 名称），无法定位到字段时为空
+	Err   error  // ErrInvalidValue / ErrUnknownField / ErrMalformed
+}
+
+func (e *RowError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("第 %d 行字段 %s: %v", e.Line, e.Field, e.Err)
+	}
+	return fmt.Sprintf("第 %d 行: %v", e.Line, e.Err)
+}
+
+// Unwrap 返回错误类别，便于通过 errors.Is 判断
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader 导入读取器
+type Reader interface {
+	// Next 读取下一行数据并解码到 dst（结构体指针，按 json 标签匹配字段），返回该行的行号
+	//   - 读取完毕返回 io.EOF
+	//   - 单行数据无效时返回 *RowError，dst 中的内容不可用，可继续调用 Next
+	//   - 其他错误（如表头无效、读取失败）表示无法继续读取
+	Next(dst any) (int, error)
+}
+
+// NewReader 按格式创建导入读取器
+func NewReader(format Format, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r), nil
+	case FormatNDJSON:
+		return newNDJSONReader(r), nil
+	default:
+		return nil, fmt.Errorf("不支持的导入格式: %s", format)
+	}
+}
+
+// FormatOf 根据文件扩展名判断导入格式，.json / .jsonl 视为 NDJSON
+func FormatOf(filename string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, true
+	case ".ndjson", ".jsonl", ".json":
+		return FormatNDJSON, true
+	default:
+		return "", false
+	}
+}
+
+// FieldName 返回结构体字段的 JSON 名称，用于将校验错误中的字段名转换为导入文件中的列名
+func FieldName(dst any, structField string) string {
+	t := reflect.TypeOf(dst)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if field, ok := t.FieldByName(structField); ok {
+		if name := jsonName(field); name != "" {
+			return name
+		}
+	}
+	return structField
+}
+
+// jsonName 返回字段的 JSON 名称，json:"-" 的字段返回空字符串
+func jsonName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return field.Name
+}
@@ -0,0 +1,171 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// row 测试用的导入目标结构体
+type row struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Rate  *float64 `json:"rate"`
+	OK    bool     `json:"ok"`
+	Skip  string   `json:"-"`
+}
+
+// readResult 一次 Next 调用的结果
+type readResult struct {
+	line int
+	row  row
+	err  error
+}
+
+// readAll 读取全部行，直到 io.EOF 或无法继续读取的错误
+func readAll(t *testing.T, format Format, input string) []readResult {
+	t.Helper()
+	reader, err := NewReader(format, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	var results []readResult
+	for {
+		var dst row
+		line, err := reader.Next(&dst)
+		if errors.Is(err, io.EOF) {
+			return results
+		}
+		var rowErr *RowError
+		if err != nil && !errors.As(err, &rowErr) {
+			return append(results, readResult{line: line, err: err})
+		}
+		if err != nil {
+			dst = row{}
+		}
+		results = append(results, readResult{line: line, row: dst, err: err})
+	}
+}
+
+func TestReader(t *testing.T) {
+	rate := 1.5
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+		want   []readResult
+	}{
+		{
+			name:   "CSV 表头带 BOM、不区分大小写，空单元格保留零值",
+			format: FormatCSV,
+			input:  "\ufeffName, COUNT,rate,ok\na,1,1.5,true\nb,,,\n",
+			want: []readResult{
+				{line: 2, row: row{Name: "a", Count: 1, Rate: &rate, OK: true}},
+				{line: 3, row: row{Name: "b"}},
+			},
+		},
+		{
+			name:   "CSV 取值无效和列数不一致不影响后续行",
+			format: FormatCSV,
+			input:  "name,count\na,x\nb\nc,3\n",
+			want: []readResult{
+				{line: 2, err: &RowError{Line: 2, Field: "count", Err: ErrInvalidValue}},
+				{line: 3, err: &RowError{Line: 3, Err: ErrMalformed}},
+				{line: 4, row: row{Name: "c", Count: 3}},
+			},
+		},
+		{
+			name:   "CSV 未知列",
+			format: FormatCSV,
+			input:  "name,skip\na,b\n",
+			want:   []readResult{{err: &HeaderError{Column: "skip", Err: ErrUnknownField}}},
+		},
+		{
+			name:   "CSV 重复列",
+			format: FormatCSV,
+			input:  "name,Name\na,b\n",
+			want:   []readResult{{err: &HeaderError{Column: "Name", Err: ErrDuplicateColumn}}},
+		},
+		{
+			name:   "NDJSON 跳过空行，行号按文件行计算",
+			format: FormatNDJSON,
+			input:  "\ufeff{\"name\":\"a\",\"count\":1,\"rate\":1.5,\"ok\":true}\n\n  \n{\"name\":\"b\"}\n",
+			want: []readResult{
+				{line: 1, row: row{Name: "a", Count: 1, Rate: &rate, OK: true}},
+				{line: 4, row: row{Name: "b"}},
+			},
+		},
+		{
+			name:   "NDJSON 单行错误不影响后续行",
+			format: FormatNDJSON,
+			input:  "{\"count\":\"x\"}\n{\"other\":1}\n{\"name\":\n{\"name\":\"a\"} {}\n{\"name\":\"b\"}\n",
+			want: []readResult{
+				{line: 1, err: &RowError{Line: 1, Field: "count", Err: ErrInvalidValue}},
+				{line: 2, err: &RowError{Line: 2, Field: "other", Err: ErrUnknownField}},
+				{line: 3, err: &RowError{Line: 3, Err: ErrMalformed}},
+				{line: 4, err: &RowError{Line: 4, Err: ErrMalformed}},
+				{line: 5, row: row{Name: "b"}},
+			},
+		},
+		{
+			name:   "NDJSON 单行过长停止读取",
+			format: FormatNDJSON,
+			input:  "{\"name\":\"a\"}\n" + strings.Repeat(" ", maxLineSize+1) + "\n",
+			want: []readResult{
+				{line: 1, row: row{Name: "a"}},
+				{line: 2, err: ErrLineTooLong},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := readAll(t, tt.format, tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Next() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewReaderUnsupportedFormat(t *testing.T) {
+	if _, err := NewReader("xlsx", strings.NewReader("")); err == nil {
+		t.Error("NewReader(xlsx) error = nil")
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     Format
+		wantOK   bool
+	}{
+		{"data.csv", FormatCSV, true},
+		{"DATA.CSV", FormatCSV, true},
+		{"data.ndjson", FormatNDJSON, true},
+		{"data.jsonl", FormatNDJSON, true},
+		{"data.json", FormatNDJSON, true},
+		{"data.xlsx", "", false},
+		{"data", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := FormatOf(tt.filename); got != tt.want || ok != tt.wantOK {
+			t.Errorf("FormatOf(%q) = %q, %v, want %q, %v", tt.filename, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	tests := map[string]string{
+		"Name":    "name",
+		"Skip":    "Skip",
+		"Missing": "Missing",
+	}
+	for field, want := range tests {
+		if got := FieldName(&row{}, field); got != want {
+			t.Errorf("FieldName(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// ndjsonReader NDJSON 导入读取器，每行一个 JSON 对象，键与结构体字段的 JSON 名称匹配，不允许未知字段
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &ndjsonReader{scanner: scanner}
+}
+
+// Next 读取下一行数据，空行跳过
+func (n *ndjsonReader) Next(dst any) (int, error) {
+	for n.scanner.Scan() {
+		n.line++
+		data := bytes.TrimSpace(n.scanner.Bytes())
+		if n.line == 1 {
+			data = bytes.TrimPrefix(data, []byte(utf8BOM))
+		}
+		if len(data) == 0 {
+			continue
+		}
+		reflect.ValueOf(dst).Elem().SetZero()
+		if err := decodeLine(data, dst); err != nil {
+			err.Line = n.line
+			return n.line, err
+		}
+		return n.line, nil
+	}
+	if err := n.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return n.line + 1, ErrLineTooLong
+		}
+		return 0, err
+	}
+	return 0, io.EOF
+}
+
+// decodeLine 解码单行 JSON，错误转换为对应的错误类别
+func decodeLine(data []byte, dst any) *RowError {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(dst)
+	if err == nil {
+		// 一行中只能有一个 JSON 对象
+		if decoder.More() {
+			return &RowError{Err: ErrMalformed}
+		}
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &RowError{Field: typeErr.Field, Err: ErrInvalidValue}
+	}
+	// encoding/json 未导出未知字段的错误类型，只能从错误信息中解析字段名
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return &RowError{Field: strings.Trim(field, `"`), Err: ErrUnknownField}
+	}
+	return &RowError{Err: ErrMalformed}
+}
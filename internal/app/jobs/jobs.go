@@ -0,0 +1,296 @@
+// Package jobs 进程内异步任务：提交后由后台工作协程执行，调用方通过任务ID查询状态、进度和结果
+//
+// 任务只保存在当前进程的内存中，服务重启后丢失；多实例部署时查询请求需路由到提交任务的实例。
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Status 任务状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // 排队等待执行
+	StatusRunning   Status = "running"   // 执行中
+	StatusSucceeded Status = "succeeded" // 执行成功
+	StatusFailed    Status = "failed"    // 执行失败
+)
+
+var (
+	// ErrQueueFull 排队的任务数已达上限
+	ErrQueueFull = errors.New("异步任务队列已满")
+	// ErrShutdown 任务管理器已关闭：关闭后不再接受新任务，尚未开始执行的任务以该错误结束
+	ErrShutdown = errors.New("服务正在关闭，任务未执行")
+)
+
+// Func 任务函数，ctx 派生自提交时传入的上下文（调用方应传入不随请求结束而取消的上下文），任务管理器关闭时等待超时则被取消；执行中可通过 job.SetProgress 更新进度
+// 返回错误时任务为失败状态，同时返回的结果仍会保存（如失败前已处理的部分）
+type Func func(ctx context.Context, job *Job) (any, error)
+
+// Options 任务管理器选项
+type Options struct {
+	Workers   int           // 同时执行的任务数，<= 0 时为 1
+	QueueSize int           // 排队等待的任务数上限，<= 0 时为 10
+	Retention time.Duration // 已结束的任务保留时间，超过后无法再查询，<= 0 时为 1 小时
+}
+
+// Manager 异步任务管理器接口
+type Manager interface {
+	// Submit 提交任务，owner 为任务归属（如租户标识），查询时由调用方校验；队列已满时返回 ErrQueueFull
+	Submit(ctx context.Context, owner string, fn Func) (*Job, error)
+	// Get 按ID获取任务，不存在或已过保留时间时返回 false
+	Get(id string) (*Job, bool)
+	// Shutdown 停止接受新任务，等待执行中的任务结束；ctx 取消时取消执行中任务的 ctx，不再等待并返回 ctx 的错误
+	Shutdown(ctx context.Context) error
+}
+
+// MemoryManager 基于内存的任务管理器实现
+type MemoryManager struct {
+	mu        sync.Mutex
+	jobs      map[string]*Job
+	queue     chan *Job
+	retention time.Duration
+	closed    bool           // 已关闭，不再接受新任务
+	workers   sync.WaitGroup // 工作协程
+
+	stop   context.Context    // 关闭时等待超时则取消，执行中任务的 ctx 随之取消
+	cancel context.CancelFunc // 取消 stop
+}
+
+// NewManager 创建任务管理器并启动工作协程
+func NewManager(opts Options) Manager {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 10
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = time.Hour
+	}
+	m := &MemoryManager{
+		jobs:      make(map[string]*Job),
+		queue:     make(chan *Job, opts.QueueSize),
+		retention: opts.Retention,
+	}
+	m.stop, m.cancel = context.WithCancel(context.Background())
+	m.workers.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go m.work()
+	}
+	return m
+}
+
+// Submit 提交任务
+func (m *MemoryManager) Submit(ctx context.Context, owner string, fn Func) (*Job, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	job := &Job{id: id, owner: owner, status: StatusPending, createTime: time.Now(), ctx: ctx, fn: fn}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, ErrShutdown
+	}
+	m.prune()
+	select {
+	case m.queue <- job:
+	default:
+		return nil, ErrQueueFull
+	}
+	m.jobs[id] = job
+	return job, nil
+}
+
+// Get 按ID获取任务
+func (m *MemoryManager) Get(id string) (*Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prune()
+	job, ok := m.jobs[id]
+	return job, ok
+}
+
+// Shutdown 关闭任务管理器：排队中的任务不再正常执行，以 ErrShutdown 结束；等待执行中的任务结束
+// ctx 取消时取消执行中任务的 ctx，使其尽快返回并释放资源（如回滚事务、删除临时文件），不再等待其结束
+func (m *MemoryManager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.queue)
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		m.cancel()
+		return nil
+	case <-ctx.Done():
+		m.cancel()
+		return ctx.Err()
+	}
+}
+
+// isClosed 判断任务管理器是否已关闭
+func (m *MemoryManager) isClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// prune 清理超过保留时间的已结束任务，调用方持有锁
+func (m *MemoryManager) prune() {
+	deadline := time.Now().Add(-m.retention)
+	for id, job := range m.jobs {
+		if finish := job.Snapshot().FinishTime; finish != nil && finish.Before(deadline) {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+// work 工作协程，依次执行队列中的任务，队列取空后退出
+// 关闭后队列中剩余的任务以已取消的 ctx 执行，便于任务函数尽快返回并释放资源（如删除临时文件），任务以 ErrShutdown 结束
+func (m *MemoryManager) work() {
+	defer m.workers.Done()
+	for job := range m.queue {
+		if m.isClosed() {
+			job.skip()
+			continue
+		}
+		job.run(m.stop)
+	}
+}
+
+// newID 生成随机任务ID
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("生成任务ID失败: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Job 异步任务
+type Job struct {
+	id    string
+	owner string
+	ctx   context.Context
+	fn    Func
+
+	mu         sync.Mutex
+	status     Status
+	progress   any
+	result     any
+	err        error
+	createTime time.Time
+	startTime  *time.Time
+	finishTime *time.Time
+}
+
+// Snapshot 任务在某一时刻的状态
+type Snapshot struct {
+	ID         string
+	Owner      string
+	Status     Status
+	Progress   any   // 最近一次 SetProgress 设置的进度
+	Result     any   // 任务函数的返回值，执行失败时可能为失败前的部分结果
+	Err        error // 执行失败的原因
+	CreateTime time.Time
+	StartTime  *time.Time
+	FinishTime *time.Time
+}
+
+// ID 返回任务ID
+func (j *Job) ID() string {
+	return j.id
+}
+
+// SetProgress 更新任务进度，进度值应为不再修改的副本
+func (j *Job) SetProgress(progress any) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.progress = progress
+}
+
+// Snapshot 返回任务当前状态
+func (j *Job) Snapshot() Snapshot {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return Snapshot{
+		ID:         j.id,
+		Owner:      j.owner,
+		Status:     j.status,
+		Progress:   j.progress,
+		Result:     j.result,
+		Err:        j.err,
+		CreateTime: j.createTime,
+		StartTime:  j.startTime,
+		FinishTime: j.finishTime,
+	}
+}
+
+// run 执行任务，stop 取消时任务的 ctx 随之取消；任务函数 panic 时视为执行失败
+func (j *Job) run(stop context.Context) {
+	now := time.Now()
+	j.mu.Lock()
+	j.status, j.startTime = StatusRunning, &now
+	j.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(j.ctx)
+	defer cancel()
+	release := context.AfterFunc(stop, cancel)
+	defer release()
+
+	result, err := j.call(ctx)
+	if err != nil {
+		logrus.WithError(err).WithField("jobId", j.id).Error("异步任务执行失败")
+	}
+	j.finish(result, err)
+}
+
+// skip 任务管理器关闭后跳过任务：以已取消的 ctx 调用任务函数使其释放资源，任务以 ErrShutdown 结束
+func (j *Job) skip() {
+	ctx, cancel := context.WithCancel(j.ctx)
+	cancel()
+	_, _ = j.call(ctx)
+	j.finish(nil, ErrShutdown)
+}
+
+// call 调用任务函数，panic 转换为错误
+func (j *Job) call(ctx context.Context) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("任务执行异常: %v", r)
+		}
+	}()
+	return j.fn(ctx, j)
+}
+
+// finish 记录任务结束的结果，err 不为空时为失败状态
+func (j *Job) finish(result any, err error) {
+	finish := time.Now()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.finishTime, j.result = &finish, result
+	if err != nil {
+		j.status, j.err = StatusFailed, err
+		return
+	}
+	j.status = StatusSucceeded
+}
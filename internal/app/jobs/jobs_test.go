@@ -0,0 +1,213 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitFinished 等待任务结束并返回其状态，超时则测试失败
+func waitFinished(t *testing.T, job *Job) Snapshot {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if snapshot := job.Snapshot(); snapshot.FinishTime != nil {
+			return snapshot
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("任务 %s 未在超时前结束", job.ID())
+	return Snapshot{}
+}
+
+// blockingJob 返回阻塞到 release 关闭或 ctx 取消的任务函数，开始执行时关闭 started
+func blockingJob(started, release chan struct{}) Func {
+	return func(ctx context.Context, job *Job) (any, error) {
+		close(started)
+		select {
+		case <-release:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func TestManagerRunsJobs(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name       string
+		fn         Func
+		wantStatus Status
+		wantResult any
+		wantErr    string
+	}{
+		{
+			name: "执行成功并记录进度",
+			fn: func(ctx context.Context, job *Job) (any, error) {
+				job.SetProgress(1)
+				return "ok", nil
+			},
+			wantStatus: StatusSucceeded,
+			wantResult: "ok",
+		},
+		{
+			name: "执行失败时保留部分结果",
+			fn: func(ctx context.Context, job *Job) (any, error) {
+				return "partial", errFailed
+			},
+			wantStatus: StatusFailed,
+			wantResult: "partial",
+			wantErr:    "failed",
+		},
+		{
+			name: "panic 视为执行失败",
+			fn: func(ctx context.Context, job *Job) (any, error) {
+				panic("boom")
+			},
+			wantStatus: StatusFailed,
+			wantErr:    "任务执行异常: boom",
+		},
+	}
+
+	manager := NewManager(Options{})
+	defer manager.Shutdown(context.Background())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := manager.Submit(context.Background(), "acme", tt.fn)
+			if err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+			snapshot := waitFinished(t, job)
+			if snapshot.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s", snapshot.Status, tt.wantStatus)
+			}
+			if snapshot.Result != tt.wantResult {
+				t.Errorf("Result = %v, want %v", snapshot.Result, tt.wantResult)
+			}
+			gotErr := ""
+			if snapshot.Err != nil {
+				gotErr = snapshot.Err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("Err = %q, want %q", gotErr, tt.wantErr)
+			}
+			if snapshot.Owner != "acme" || snapshot.StartTime == nil {
+				t.Errorf("Owner = %q, StartTime = %v", snapshot.Owner, snapshot.StartTime)
+			}
+			if got, ok := manager.Get(job.ID()); !ok || got != job {
+				t.Errorf("Get() = %v, %v", got, ok)
+			}
+		})
+	}
+}
+
+func TestManagerQueueFull(t *testing.T) {
+	manager := NewManager(Options{Workers: 1, QueueSize: 1})
+	started, release := make(chan struct{}), make(chan struct{})
+	defer manager.Shutdown(context.Background())
+	defer close(release)
+
+	// 第一个任务占用工作协程，第二个任务占满队列
+	if _, err := manager.Submit(context.Background(), "", blockingJob(started, release)); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-started
+	queued, err := manager.Submit(context.Background(), "", func(ctx context.Context, job *Job) (any, error) { return nil, nil })
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if status := queued.Snapshot().Status; status != StatusPending {
+		t.Errorf("排队任务 Status = %s, want %s", status, StatusPending)
+	}
+
+	if _, err := manager.Submit(context.Background(), "", func(ctx context.Context, job *Job) (any, error) { return nil, nil }); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Submit() error = %v, want ErrQueueFull", err)
+	}
+}
+
+func TestManagerShutdown(t *testing.T) {
+	manager := NewManager(Options{Workers: 1, QueueSize: 1})
+	started, release := make(chan struct{}), make(chan struct{})
+	running, err := manager.Submit(context.Background(), "", blockingJob(started, release))
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-started
+
+	// 排队中的任务在关闭后以已取消的 ctx 调用，便于释放资源
+	skippedCtx := make(chan error, 1)
+	queued, err := manager.Submit(context.Background(), "", func(ctx context.Context, job *Job) (any, error) {
+		skippedCtx <- ctx.Err()
+		return "ignored", nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	// 执行中的任务未结束时，ctx 超时则取消执行中任务的 ctx，不再等待
+	expired, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := manager.Shutdown(expired); !errors.Is(err, context.Canceled) {
+		t.Errorf("Shutdown() error = %v, want context.Canceled", err)
+	}
+	if _, err := manager.Submit(context.Background(), "", func(ctx context.Context, job *Job) (any, error) { return nil, nil }); !errors.Is(err, ErrShutdown) {
+		t.Errorf("关闭后 Submit() error = %v, want ErrShutdown", err)
+	}
+
+	if snapshot := waitFinished(t, running); snapshot.Status != StatusFailed || !errors.Is(snapshot.Err, context.Canceled) {
+		t.Errorf("执行中的任务 Status = %s, Err = %v, want context.Canceled", snapshot.Status, snapshot.Err)
+	}
+	if err := manager.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	snapshot := waitFinished(t, queued)
+	if snapshot.Status != StatusFailed || !errors.Is(snapshot.Err, ErrShutdown) || snapshot.Result != nil {
+		t.Errorf("排队任务 Status = %s, Err = %v, Result = %v", snapshot.Status, snapshot.Err, snapshot.Result)
+	}
+	if err := <-skippedCtx; !errors.Is(err, context.Canceled) {
+		t.Errorf("跳过的任务 ctx.Err() = %v, want context.Canceled", err)
+	}
+}
+
+func TestManagerShutdownWaits(t *testing.T) {
+	manager := NewManager(Options{})
+	started, release := make(chan struct{}), make(chan struct{})
+	running, err := manager.Submit(context.Background(), "", blockingJob(started, release))
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	<-started
+
+	// ctx 未取消时等待执行中的任务正常结束
+	time.AfterFunc(20*time.Millisecond, func() { close(release) })
+	if err := manager.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if snapshot := running.Snapshot(); snapshot.Status != StatusSucceeded {
+		t.Errorf("执行中的任务 Status = %s, Err = %v, want %s", snapshot.Status, snapshot.Err, StatusSucceeded)
+	}
+}
+
+func TestManagerRetention(t *testing.T) {
+	manager := NewManager(Options{Retention: 20 * time.Millisecond})
+	defer manager.Shutdown(context.Background())
+
+	job, err := manager.Submit(context.Background(), "", func(ctx context.Context, job *Job) (any, error) { return nil, nil })
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	waitFinished(t, job)
+	if _, ok := manager.Get(job.ID()); !ok {
+		t.Fatal("保留时间内 Get() = false")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := manager.Get(job.ID()); ok {
+		t.Error("超过保留时间后 Get() = true")
+	}
+	if _, ok := manager.Get("missing"); ok {
+		t.Error("不存在的任务 Get() = true")
+	}
+}
@@ -0,0 +1,32 @@
+// Package middleware 语言解析中间件：根据查询参数或 Accept-Language 请求头确定本次请求使用的语言
+package middleware
+
+import (
+	"gin-template/internal/app/config"
+	"gin-template/internal/app/i18n"
+	"gin-template/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Locale 语言解析中间件
+// 优先使用查询参数（如 ?lang=en-US）指定的语言，其次使用 Accept-Language 请求头，均无法匹配时使用默认语言
+// bundle 为消息目录集合，作为翻译器写入上下文，响应时据此翻译提示信息
+func Locale(cfg config.I18nConfig, bundle *i18n.Bundle) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		locale := bundle.Match(
+			ctx.Query(cfg.QueryParam),
+			ctx.GetHeader("Accept-Language"),
+		)
+
+		// 存入上下文，响应时据此翻译提示信息
+		ctx.Set("locale", locale)
+		ctx.Set(utils.TranslatorKey, bundle)
+
+		// 响应头返回实际使用的语言
+		ctx.Writer.Header().Set("Content-Language", locale)
+
+		// 继续执行后续中间件/接口
+		ctx.Next()
+	}
+}
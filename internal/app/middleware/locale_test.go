@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"encoding/json"
+	"gin-template/internal/app/config"
+	"gin-template/internal/app/i18n"
+	"gin-template/internal/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLocale(t *testing.T) {
+	bundle := i18n.NewBundle("zh-CN")
+	if err := bundle.AddMessages("zh-CN", ".yaml", []byte("common:\n  success: 成功\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := bundle.AddMessages("en-US", ".yaml", []byte("common:\n  success: Success\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) { ctx.Set("requestId", "r1") })
+	router.Use(Locale(config.I18nConfig{QueryParam: "lang"}, bundle))
+	router.GET("/", func(ctx *gin.Context) { utils.Success(ctx, "", nil) })
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		wantLocale     string
+		wantMessage    string
+	}{
+		{"默认语言", "", "", "zh-CN", "成功"},
+		{"Accept-Language", "", "en-US,en;q=0.9", "en-US", "Success"},
+		{"查询参数优先", "?lang=zh-CN", "en-US", "zh-CN", "成功"},
+		{"查询参数无法匹配时使用请求头", "?lang=fr", "en", "en-US", "Success"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if got := w.Header().Get("Content-Language"); got != tt.wantLocale {
+				t.Errorf("Content-Language = %q, want %q", got, tt.wantLocale)
+			}
+			var body utils.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", body.Message, tt.wantMessage)
+			}
+		})
+	}
+}
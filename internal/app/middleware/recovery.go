@@ -17,6 +17,11 @@ func Recovery() gin.HandlerFunc {
 		// 使用defer+recover捕获panic
 		defer func() {
 			if err := recover(); err != nil {
+				// http.ErrAbortHandler 表示处理函数主动中断响应（如流式导出中途失败），交给 net/http 断开连接，不再写入错误响应
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+
 				// 捕获完整堆栈信息
 				stack := make([]byte, 4096)           // 预分配4096字节
 				length := runtime.Stack(stack, false) // 通过标准库 runtime 包获取调用栈信息
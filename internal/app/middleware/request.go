@@ -2,6 +2,8 @@
 package middleware
 
 import (
+	"gin-template/internal/utils"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -16,8 +18,9 @@ func RequestIdInject() gin.HandlerFunc {
 			requestId = uuid.NewString()
 		}
 
-		// 存入上下文，便于后续使用
-		ctx.Set("requestId", requestId)
+		// 存入上下文，便于后续使用；客户端 IP 一并存入，供审计日志等非 HTTP 层读取
+		ctx.Set(utils.RequestIdKey, requestId)
+		ctx.Set(utils.ClientIPKey, ctx.ClientIP())
 
 		// 响应头返回 requestId，便于前端获取
 		ctx.Writer.Header().Set("X-Request-Id", requestId)
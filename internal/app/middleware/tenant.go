@@ -0,0 +1,91 @@
+// Package middleware 租户解析中间件：从请求头、子域名或已校验的 JWT 声明中解析租户并存入上下文
+package middleware
+
+import (
+	"fmt"
+	"gin-template/internal/app/config"
+	"gin-template/internal/app/tenant"
+	"gin-template/internal/utils"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tenant 租户解析中间件
+// 按配置的来源顺序解析租户标识，多个来源解析出的租户不一致时拒绝请求（如请求头试图覆盖令牌中的租户）。
+// JWT 声明由上游认证中间件校验签名后存入上下文，本中间件只读取，不解析请求中未经校验的令牌。
+func Tenant(cfg config.TenantConfig) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var tenantID string
+		for _, source := range cfg.Sources {
+			id := resolveTenant(ctx, cfg, source)
+			if id == "" {
+				continue
+			}
+			if tenantID != "" && id != tenantID {
+				utils.HandlerFunc(ctx, utils.NewBusinessError(utils.ErrCodePermissionDenied, "tenant.mismatch"))
+				return
+			}
+			tenantID = id
+		}
+
+		if tenantID == "" {
+			if cfg.Required {
+				utils.HandlerFunc(ctx, utils.NewBusinessError(utils.ErrCodeTenantRequired, "tenant.required"))
+				return
+			}
+			ctx.Next()
+			return
+		}
+
+		// 校验格式；database 模式下租户必须已配置数据库连接
+		if !tenant.ValidID(tenantID) {
+			utils.HandlerFunc(ctx, utils.NewBusinessError(utils.ErrCodeTenantInvalid, "tenant.invalid"))
+			return
+		}
+		if cfg.Mode == tenant.ModeDatabase {
+			if _, ok := cfg.Databases[tenantID]; !ok {
+				utils.HandlerFunc(ctx, utils.NewBusinessError(utils.ErrCodeTenantInvalid, "tenant.invalid"))
+				return
+			}
+		}
+
+		// 存入上下文，Repository 通过 GORM 租户插件自动按租户隔离数据
+		ctx.Set(tenant.ContextKey, tenantID)
+
+		// 继续执行后续中间件/接口
+		ctx.Next()
+	}
+}
+
+// resolveTenant 从指定来源解析租户标识，解析不到时返回空字符串
+func resolveTenant(ctx *gin.Context, cfg config.TenantConfig, source string) string {
+	switch source {
+	case "header":
+		return strings.TrimSpace(ctx.GetHeader(cfg.Header))
+	case "subdomain":
+		host := ctx.Request.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		suffix := "." + strings.ToLower(cfg.BaseDomain)
+		host = strings.ToLower(host)
+		if !strings.HasSuffix(host, suffix) {
+			return ""
+		}
+		return strings.TrimSuffix(host, suffix)
+	case "claim":
+		value, ok := ctx.Get(cfg.ClaimsKey)
+		if !ok {
+			return ""
+		}
+		claims, ok := value.(map[string]any)
+		if !ok || claims[cfg.Claim] == nil {
+			return ""
+		}
+		return fmt.Sprint(claims[cfg.Claim])
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,16 @@
+// Package model 可复用的数据模型基础字段
+package model
+
+import "time"
+
+// BaseModel 通用基础字段，匿名嵌入到数据模型中使用
+//   - create_time / update_time 由 GORM 在创建、更新时自动填充，时区取决于数据库连接的 time_zone 配置
+//   - created_by / updated_by 由 database.TrackingPlugin 在创建、更新时按上下文中的操作人（utils.OperatorKey）填充
+//   - deleted_by 由软删除（database.SoftDeleteUpdates）填充，恢复时清空
+type BaseModel struct {
+	CreateTime *time.Time `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+	UpdateTime *time.Time `json:"update_time" gorm:"column:update_time;autoUpdateTime"`
+	CreatedBy  *string    `json:"created_by" gorm:"type:varchar(64);column:created_by"`
+	UpdatedBy  *string    `json:"updated_by" gorm:"type:varchar(64);column:updated_by"`
+	DeletedBy  *string    `json:"deleted_by" gorm:"type:varchar(64);column:deleted_by"`
+}
@@ -0,0 +1,214 @@
+// Package module 业务模块注册：各业务模块实现 Module 接口，由 Registry 按依赖顺序初始化、注册路由、迁移表结构、
+// 汇总健康检查，服务退出时按相反顺序关闭。新增模块只需实现接口并在 main 中注册，无需修改路由文件。
+package module
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-template/internal/app/config"
+	"gin-template/internal/app/database"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// HealthCheck 健康检查函数，返回错误表示未就绪
+type HealthCheck func(ctx context.Context) error
+
+// Module 业务模块接口
+type Module interface {
+	// Name 模块名称，唯一，对应配置 modules.enabled 中的键
+	Name() string
+	// DependsOn 依赖的模块名称，依赖的模块先初始化、后关闭，且必须启用
+	DependsOn() []string
+	// Init 初始化模块（创建仓库、服务、控制器等），可通过 deps.Module 获取已初始化的依赖模块
+	Init(deps *Deps) error
+	// RegisterRoutes 在 /api 路由组下注册模块的路由
+	RegisterRoutes(group *gin.RouterGroup)
+	// Migrations 需要自动迁移的数据模型，启用 modules.auto_migrate 时在启动时迁移
+	Migrations() []any
+	// HealthChecks 就绪检查，名称 -> 检查函数，汇总到 /health/ready
+	HealthChecks() map[string]HealthCheck
+	// Shutdown 释放模块持有的资源（如停止后台任务），ctx 取消时应尽快返回
+	Shutdown(ctx context.Context) error
+}
+
+// OptionalDependencies 可选实现：声明可选依赖的模块，已注册且启用时先初始化、后关闭，否则忽略
+// 模块初始化时通过 deps.Module 判断可选依赖是否可用，不可用时停用相关功能
+type OptionalDependencies interface {
+	OptionalDependsOn() []string
+}
+
+// Deps 模块初始化依赖
+type Deps struct {
+	Config    *config.Config
+	DBManager database.Manager
+
+	initialized map[string]Module // 已初始化的模块
+}
+
+// Module 返回已初始化的模块，只能获取当前模块声明的依赖（未声明的模块可能尚未初始化），可选依赖未启用时返回 false
+func (d *Deps) Module(name string) (Module, bool) {
+	m, ok := d.initialized[name]
+	return m, ok
+}
+
+// Registry 模块注册表
+type Registry struct {
+	modules []Module // 注册的全部模块
+	enabled []Module // 已初始化的模块，按初始化顺序排列
+}
+
+// NewRegistry 创建模块注册表，modules 的顺序为没有依赖关系时的初始化顺序
+func NewRegistry(modules ...Module) *Registry {
+	return &Registry{modules: modules}
+}
+
+// Init 按配置过滤出启用的模块，按依赖顺序依次初始化
+// 模块名称重复、配置了未注册的模块、依赖的模块未注册或未启用、存在循环依赖时返回错误（未注册或未启用的可选依赖忽略）；
+// 任一模块初始化失败时关闭已初始化的模块并返回错误
+func (r *Registry) Init(ctx context.Context, deps *Deps) error {
+	ordered, err := r.resolve(deps.Config.Modules)
+	if err != nil {
+		return err
+	}
+
+	deps.initialized = make(map[string]Module, len(ordered))
+	for _, m := range ordered {
+		if err := m.Init(deps); err != nil {
+			if shutdownErr := r.Shutdown(ctx); shutdownErr != nil {
+				logrus.WithError(shutdownErr).Error("关闭已初始化的模块失败")
+			}
+			return fmt.Errorf("初始化模块 '%s' 失败: %w", m.Name(), err)
+		}
+		deps.initialized[m.Name()] = m
+		r.enabled = append(r.enabled, m)
+		logrus.WithField("module", m.Name()).Info("模块已初始化")
+	}
+	return nil
+}
+
+// resolve 校验模块配置，返回启用的模块按依赖关系的拓扑顺序（依赖在前），无依赖关系的模块保持注册顺序
+func (r *Registry) resolve(cfg config.ModulesConfig) ([]Module, error) {
+	registered := make(map[string]Module, len(r.modules))
+	for _, m := range r.modules {
+		if _, ok := registered[m.Name()]; ok {
+			return nil, fmt.Errorf("模块名称重复: %s", m.Name())
+		}
+		registered[m.Name()] = m
+	}
+	for name := range cfg.Enabled {
+		if _, ok := registered[name]; !ok {
+			return nil, fmt.Errorf("配置 modules.enabled 中的模块 '%s' 未注册", name)
+		}
+	}
+
+	enabled := func(name string) bool {
+		on, ok := cfg.Enabled[name]
+		return !ok || on
+	}
+
+	// 深度优先遍历：visiting 为当前路径上的模块，用于检测循环依赖
+	var (
+		ordered  []Module
+		visited  = make(map[string]bool)
+		visiting []string
+	)
+	var visit func(m Module) error
+	visit = func(m Module) error {
+		name := m.Name()
+		if visited[name] {
+			return nil
+		}
+		if slices.Contains(visiting, name) {
+			return fmt.Errorf("模块存在循环依赖: %s -> %s", strings.Join(visiting, " -> "), name)
+		}
+		visiting = append(visiting, name)
+		for _, dep := range m.DependsOn() {
+			target, ok := registered[dep]
+			if !ok {
+				return fmt.Errorf("模块 '%s' 依赖的模块 '%s' 未注册", name, dep)
+			}
+			if !enabled(dep) {
+				return fmt.Errorf("模块 '%s' 依赖的模块 '%s' 未启用", name, dep)
+			}
+			if err := visit(target); err != nil {
+				return err
+			}
+		}
+		if optional, ok := m.(OptionalDependencies); ok {
+			for _, dep := range optional.OptionalDependsOn() {
+				target, ok := registered[dep]
+				if !ok || !enabled(dep) {
+					continue
+				}
+				if err := visit(target); err != nil {
+					return err
+				}
+			}
+		}
+		visiting = visiting[:len(visiting)-1]
+		visited[name] = true
+		ordered = append(ordered, m)
+		return nil
+	}
+	for _, m := range r.modules {
+		if !enabled(m.Name()) {
+			logrus.WithField("module", m.Name()).Info("模块未启用")
+			continue
+		}
+		if err := visit(m); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Migrate 按初始化顺序迁移各模块的数据模型
+func (r *Registry) Migrate(ctx context.Context, db *gorm.DB) error {
+	for _, m := range r.enabled {
+		models := m.Migrations()
+		if len(models) == 0 {
+			continue
+		}
+		if err := db.WithContext(ctx).AutoMigrate(models...); err != nil {
+			return fmt.Errorf("迁移模块 '%s' 的表结构失败: %w", m.Name(), err)
+		}
+	}
+	return nil
+}
+
+// RegisterRoutes 按初始化顺序注册各模块的路由
+func (r *Registry) RegisterRoutes(group *gin.RouterGroup) {
+	for _, m := range r.enabled {
+		m.RegisterRoutes(group)
+	}
+}
+
+// HealthChecks 汇总各模块的就绪检查，名称为 模块名.检查名
+func (r *Registry) HealthChecks() map[string]HealthCheck {
+	checks := make(map[string]HealthCheck)
+	for _, m := range r.enabled {
+		for name, check := range m.HealthChecks() {
+			checks[m.Name()+"."+name] = check
+		}
+	}
+	return checks
+}
+
+// Shutdown 按初始化的相反顺序关闭各模块，单个模块关闭失败不影响其他模块，返回全部错误
+func (r *Registry) Shutdown(ctx context.Context) error {
+	var errs []error
+	for i := len(r.enabled) - 1; i >= 0; i-- {
+		m := r.enabled[i]
+		if err := m.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("关闭模块 '%s' 失败: %w", m.Name(), err))
+		}
+	}
+	r.enabled = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,100 @@
+package module
+
+import (
+	"context"
+	"gin-template/internal/app/config"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testModule 测试用的模块
+type testModule struct {
+	name     string
+	deps     []string
+	optional []string
+}
+
+func (m *testModule) Name() string                         { return m.name }
+func (m *testModule) DependsOn() []string                  { return m.deps }
+func (m *testModule) Init(*Deps) error                     { return nil }
+func (m *testModule) RegisterRoutes(*gin.RouterGroup)      {}
+func (m *testModule) Migrations() []any                    { return nil }
+func (m *testModule) HealthChecks() map[string]HealthCheck { return nil }
+func (m *testModule) Shutdown(context.Context) error       { return nil }
+
+// testOptionalModule 声明可选依赖的测试模块
+type testOptionalModule struct{ testModule }
+
+func (m *testOptionalModule) OptionalDependsOn() []string { return m.optional }
+
+func TestRegistryResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		modules []Module
+		enabled map[string]bool
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "依赖先初始化",
+			modules: []Module{&testModule{name: "b", deps: []string{"a"}}, &testModule{name: "a"}},
+			want:    []string{"a", "b"},
+		},
+		{
+			name:    "依赖未启用",
+			modules: []Module{&testModule{name: "a"}, &testModule{name: "b", deps: []string{"a"}}},
+			enabled: map[string]bool{"a": false},
+			wantErr: true,
+		},
+		{
+			name:    "依赖未注册",
+			modules: []Module{&testModule{name: "b", deps: []string{"a"}}},
+			wantErr: true,
+		},
+		{
+			name:    "循环依赖",
+			modules: []Module{&testModule{name: "a", deps: []string{"b"}}, &testModule{name: "b", deps: []string{"a"}}},
+			wantErr: true,
+		},
+		{
+			name:    "配置了未注册的模块",
+			modules: []Module{&testModule{name: "a"}},
+			enabled: map[string]bool{"x": true},
+			wantErr: true,
+		},
+		{
+			name:    "可选依赖启用时先初始化",
+			modules: []Module{&testOptionalModule{testModule{name: "b", optional: []string{"a"}}}, &testModule{name: "a"}},
+			want:    []string{"a", "b"},
+		},
+		{
+			name:    "可选依赖未启用时忽略",
+			modules: []Module{&testModule{name: "a"}, &testOptionalModule{testModule{name: "b", optional: []string{"a"}}}},
+			enabled: map[string]bool{"a": false},
+			want:    []string{"b"},
+		},
+		{
+			name:    "可选依赖未注册时忽略",
+			modules: []Module{&testOptionalModule{testModule{name: "b", optional: []string{"a"}}}},
+			want:    []string{"b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered, err := NewRegistry(tt.modules...).resolve(config.ModulesConfig{Enabled: tt.enabled})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, m := range ordered {
+				got = append(got, m.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
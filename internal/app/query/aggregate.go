@@ -0,0 +1,262 @@
+package query
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 统计规格的数量限制
+const (
+	maxGroupBy = 3  // 最多分组字段数
+	maxMetrics = 10 // 最多聚合指标数
+)
+
+// Interval 时间字段的分组粒度
+type Interval string
+
+const (
+	IntervalDay   Interval = "day"   // 按天
+	IntervalWeek  Interval = "week"  // 按周，周一为一周的第一天
+	IntervalMonth Interval = "month" // 按月
+)
+
+// AggFunc 聚合函数
+type AggFunc string
+
+const (
+	AggCount AggFunc = "count" // 记录数
+	AggSum   AggFunc = "sum"   // 求和
+	AggAvg   AggFunc = "avg"   // 平均值
+	AggMin   AggFunc = "min"   // 最小值
+	AggMax   AggFunc = "max"   // 最大值
+)
+
+// GroupBy 分组字段
+type GroupBy struct {
+	Field    string
+	Column   string
+	Type     FieldType
+	Interval Interval // Time 类型的分组粒度
+}
+
+// Metric 聚合指标
+type Metric struct {
+	Func   AggFunc
+	Field  string // count 为空
+	Column string
+}
+
+// Name 指标在结果中的名称：count、sum_field1 等
+func (m Metric) Name() string {
+	if m.Field == "" {
+		return string(m.Func)
+	}
+	return string(m.Func) + "_" + m.Field
+}
+
+// Aggregation 解析后的统计规格
+type Aggregation struct {
+	Groups  []GroupBy
+	Metrics []Metric
+}
+
+// AggregateRow 统计结果中的一行
+type AggregateRow struct {
+	Keys   map[string]any // 分组字段 -> 分组值，时间分组为区间起始日期（如 2024-01-01）
+	Values map[string]any // 指标名 -> 值，count 为整数，其他为浮点数，没有可聚合的值时为 nil
+}
+
+// ParseAggregation 解析 group_by / metrics 查询参数并按白名单校验，不允许的字段、粒度和聚合函数返回参数错误的业务异常
+//
+//	?group_by=field1,create_time:month&metrics=count,sum:field1,avg:field1
+//
+// 未指定 group_by 时统计全部数据（结果只有一行）；未指定 metrics 时为 count
+func ParseAggregation(values url.Values, allow Allowlist) (*Aggregation, error) {
+	agg := &Aggregation{}
+
+	for _, item := range splitList(values.Get("group_by")) {
+		name, interval, _ := strings.Cut(item, ":")
+		field, ok := allow[name]
+		if !ok || !field.Groupable {
+			return nil, invalid("query.not_groupable", map[string]any{"field": name})
+		}
+		group := GroupBy{Field: name, Column: field.Column, Type: field.Type, Interval: Interval(interval)}
+		switch {
+		case field.Type == Time && !slices.Contains([]Interval{IntervalDay, IntervalWeek, IntervalMonth}, group.Interval),
+			field.Type != Time && interval != "":
+			return nil, invalid("query.invalid_interval", map[string]any{"field": name, "interval": interval})
+		}
+		if slices.ContainsFunc(agg.Groups, func(g GroupBy) bool { return g.Field == name }) {
+			return nil, invalid("query.duplicate_group", map[string]any{"field": name})
+		}
+		agg.Groups = append(agg.Groups, group)
+	}
+	if len(agg.Groups) > maxGroupBy {
+		return nil, invalid("query.too_many_groups", map[string]any{"max": maxGroupBy})
+	}
+
+	for _, item := range splitList(values.Get("metrics")) {
+		fn, name, _ := strings.Cut(item, ":")
+		metric := Metric{Func: AggFunc(fn)}
+		switch metric.Func {
+		case AggCount:
+			if name != "" {
+				return nil, invalid("query.unsupported_metric", map[string]any{"metric": item})
+			}
+		case AggSum, AggAvg, AggMin, AggMax:
+			field, ok := allow[name]
+			if !ok || !field.Aggregable || (field.Type != Int && field.Type != Float) {
+				return nil, invalid("query.not_aggregable", map[string]any{"field": name})
+			}
+			metric.Field, metric.Column = name, field.Column
+		default:
+			return nil, invalid("query.unsupported_metric", map[string]any{"metric": item})
+		}
+		// 重复的指标只保留一个
+		if !slices.Contains(agg.Metrics, metric) {
+			agg.Metrics = append(agg.Metrics, metric)
+		}
+	}
+	if len(agg.Metrics) == 0 {
+		agg.Metrics = []Metric{{Func: AggCount}}
+	}
+	if len(agg.Metrics) > maxMetrics {
+		return nil, invalid("query.too_many_metrics", map[string]any{"max": maxMetrics})
+	}
+
+	return agg, nil
+}
+
+// Key 统计规格的规范化表示，可与 Spec.FilterKey 一起作为缓存键
+func (a *Aggregation) Key() string {
+	parts := make([]string, 0, len(a.Groups)+len(a.Metrics))
+	for _, group := range a.Groups {
+		parts = append(parts, "g:"+group.Field+":"+string(group.Interval))
+	}
+	for _, metric := range a.Metrics {
+		parts = append(parts, "m:"+metric.Name())
+	}
+	return strings.Join(parts, ",")
+}
+
+// Scope 将统计规格编译为 GORM 查询作用域：选择分组值和指标，按分组值分组并升序排序
+// 分组值和指标按顺序选出，与 Scan 的扫描目标一一对应；时间分段使用 MySQL 的日期函数，其他驱动返回错误
+func (a *Aggregation) Scope() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		selects := make([]string, 0, len(a.Groups)+len(a.Metrics))
+		groups := make([]string, 0, len(a.Groups))
+		for _, group := range a.Groups {
+			expr, err := group.expression(db)
+			if err != nil {
+				_ = db.AddError(err)
+				return db
+			}
+			selects = append(selects, expr)
+			groups = append(groups, expr)
+		}
+		for _, metric := range a.Metrics {
+			if metric.Func == AggCount {
+				selects = append(selects, "COUNT(*)")
+				continue
+			}
+			selects = append(selects, strings.ToUpper(string(metric.Func))+"("+quoteColumn(db, metric.Column)+")")
+		}
+
+		db = db.Select(strings.Join(selects, ", "))
+		if len(groups) > 0 {
+			db = db.Group(strings.Join(groups, ", ")).Order(strings.Join(groups, ", "))
+		}
+		return db
+	}
+}
+
+// Scan 按 Scope 选出的列读取当前行
+func (a *Aggregation) Scan(rows *sql.Rows) (AggregateRow, error) {
+	dest := make([]any, 0, len(a.Groups)+len(a.Metrics))
+	for _, group := range a.Groups {
+		switch {
+		case group.Interval != "", group.Type == String:
+			dest = append(dest, new(sql.NullString))
+		case group.Type == Int:
+			dest = append(dest, new(sql.NullInt64))
+		case group.Type == Float:
+			dest = append(dest, new(sql.NullFloat64))
+		default:
+			dest = append(dest, new(sql.NullBool))
+		}
+	}
+	for _, metric := range a.Metrics {
+		if metric.Func == AggCount {
+			dest = append(dest, new(sql.NullInt64))
+			continue
+		}
+		dest = append(dest, new(sql.NullFloat64))
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return AggregateRow{}, err
+	}
+
+	row := AggregateRow{Keys: make(map[string]any, len(a.Groups)), Values: make(map[string]any, len(a.Metrics))}
+	for i, group := range a.Groups {
+		row.Keys[group.Field] = nullValue(dest[i])
+	}
+	for i, metric := range a.Metrics {
+		row.Values[metric.Name()] = nullValue(dest[len(a.Groups)+i])
+	}
+	return row, nil
+}
+
+// expression 分组值表达式，时间字段按粒度转换为区间起始日期（YYYY-MM-DD），按数据库中存储的时间计算
+func (g GroupBy) expression(db *gorm.DB) (string, error) {
+	column := quoteColumn(db, g.Column)
+	if g.Interval == "" {
+		return column, nil
+	}
+
+	// 仅支持 MySQL（见 database.openDialector）
+	if dialect := db.Dialector.Name(); dialect != "mysql" {
+		return "", fmt.Errorf("不支持按时间分段统计的数据库: %s", dialect)
+	}
+	switch g.Interval {
+	case IntervalWeek:
+		return "DATE_FORMAT(DATE_SUB(" + column + ", INTERVAL WEEKDAY(" + column + ") DAY), '%Y-%m-%d')", nil
+	case IntervalMonth:
+		return "DATE_FORMAT(" + column + ", '%Y-%m-01')", nil
+	default:
+		return "DATE_FORMAT(" + column + ", '%Y-%m-%d')", nil
+	}
+}
+
+// quoteColumn 返回转义后的列名，作用域执行时尚未解析表名，统计查询只涉及单表，不加表名
+func quoteColumn(db *gorm.DB, column string) string {
+	return db.Statement.Quote(clause.Column{Name: column})
+}
+
+// nullValue 取出 sql.Null* 中的值，NULL 为 nil
+func nullValue(v any) any {
+	switch v := v.(type) {
+	case *sql.NullString:
+		if v.Valid {
+			return v.String
+		}
+	case *sql.NullInt64:
+		if v.Valid {
+			return v.Int64
+		}
+	case *sql.NullFloat64:
+		if v.Valid {
+			return v.Float64
+		}
+	case *sql.NullBool:
+		if v.Valid {
+			return v.Bool
+		}
+	}
+	return nil
+}
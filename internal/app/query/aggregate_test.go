@@ -0,0 +1,175 @@
+package query
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+// statsAllowlist 测试用的统计字段白名单
+var statsAllowlist = Allowlist{
+	"id":          {Column: "id", Type: Int, Filterable: true},
+	"name":        {Column: "name", Type: String, Groupable: true},
+	"score":       {Column: "score", Type: Float, Groupable: true, Aggregable: true},
+	"amount":      {Column: "amount", Type: Int, Aggregable: true},
+	"label":       {Column: "label", Type: String, Aggregable: true},
+	"create_time": {Column: "create_time", Type: Time, Groupable: true},
+}
+
+func TestParseAggregation(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    *Aggregation
+		wantErr string
+	}{
+		{
+			name:  "默认统计记录数",
+			query: "",
+			want:  &Aggregation{Metrics: []Metric{{Func: AggCount}}},
+		},
+		{
+			name:  "分组和指标",
+			query: "group_by=name,create_time:month&metrics=count,sum:amount,avg:score",
+			want: &Aggregation{
+				Groups: []GroupBy{
+					{Field: "name", Column: "name", Type: String},
+					{Field: "create_time", Column: "create_time", Type: Time, Interval: IntervalMonth},
+				},
+				Metrics: []Metric{{Func: AggCount}, {Func: AggSum, Field: "amount", Column: "amount"}, {Func: AggAvg, Field: "score", Column: "score"}},
+			},
+		},
+		{
+			name:  "重复的指标只保留一个",
+			query: "metrics=max:score,max:score",
+			want:  &Aggregation{Metrics: []Metric{{Func: AggMax, Field: "score", Column: "score"}}},
+		},
+		{name: "分组字段不在白名单", query: "group_by=secret", wantErr: "query.not_groupable"},
+		{name: "字段不允许分组", query: "group_by=id", wantErr: "query.not_groupable"},
+		{name: "时间字段缺少粒度", query: "group_by=create_time", wantErr: "query.invalid_interval"},
+		{name: "时间字段粒度无效", query: "group_by=create_time:year", wantErr: "query.invalid_interval"},
+		{name: "非时间字段指定粒度", query: "group_by=name:day", wantErr: "query.invalid_interval"},
+		{name: "重复的分组字段", query: "group_by=name,name", wantErr: "query.duplicate_group"},
+		{name: "聚合字段不在白名单", query: "metrics=sum:secret", wantErr: "query.not_aggregable"},
+		{name: "字段不允许聚合", query: "metrics=sum:id", wantErr: "query.not_aggregable"},
+		{name: "非数值字段不能聚合", query: "metrics=avg:label", wantErr: "query.not_aggregable"},
+		{name: "count 不接受字段", query: "metrics=count:amount", wantErr: "query.unsupported_metric"},
+		{name: "不支持的聚合函数", query: "metrics=median:amount", wantErr: "query.unsupported_metric"},
+		{
+			name:    "指标数量超过上限",
+			query:   "metrics=count,sum:amount,avg:amount,min:amount,max:amount,sum:score,avg:score,min:score,max:score,sum:extra,avg:extra",
+			wantErr: "query.too_many_metrics",
+		},
+	}
+
+	allow := Allowlist{"extra": {Column: "extra", Type: Int, Aggregable: true}}
+	for name, field := range statsAllowlist {
+		allow[name] = field
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ParseAggregation(values, allow)
+			if tt.wantErr != "" {
+				if msg := errorMessage(err); msg != tt.wantErr {
+					t.Fatalf("ParseAggregation() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAggregation() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAggregation() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAggregationTooManyGroups(t *testing.T) {
+	allow := Allowlist{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		allow[name] = Field{Column: name, Type: String, Groupable: true}
+	}
+	values := url.Values{"group_by": {"a,b,c,d"}}
+	if _, err := ParseAggregation(values, allow); errorMessage(err) != "query.too_many_groups" {
+		t.Errorf("ParseAggregation() error = %v, want query.too_many_groups", err)
+	}
+}
+
+func TestAggregationScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"全部数据", "", "SELECT COUNT(*) FROM `test_records`"},
+		{
+			"按字段分组",
+			"group_by=name&metrics=count,sum:amount,min:score",
+			"SELECT `name`, COUNT(*), SUM(`amount`), MIN(`score`) FROM `test_records` GROUP BY `name` ORDER BY `name`",
+		},
+		{
+			"按天分段",
+			"group_by=create_time:day",
+			"SELECT DATE_FORMAT(`create_time`, '%Y-%m-%d'), COUNT(*) FROM `test_records` GROUP BY DATE_FORMAT(`create_time`, '%Y-%m-%d') ORDER BY DATE_FORMAT(`create_time`, '%Y-%m-%d')",
+		},
+		{
+			"按周分段",
+			"group_by=create_time:week",
+			"SELECT DATE_FORMAT(DATE_SUB(`create_time`, INTERVAL WEEKDAY(`create_time`) DAY), '%Y-%m-%d'), COUNT(*) FROM `test_records` GROUP BY DATE_FORMAT(DATE_SUB(`create_time`, INTERVAL WEEKDAY(`create_time`) DAY), '%Y-%m-%d') ORDER BY DATE_FORMAT(DATE_SUB(`create_time`, INTERVAL WEEKDAY(`create_time`) DAY), '%Y-%m-%d')",
+		},
+		{
+			"按月分段并与其他字段组合",
+			"group_by=create_time:month,name&metrics=avg:score",
+			"SELECT DATE_FORMAT(`create_time`, '%Y-%m-01'), `name`, AVG(`score`) FROM `test_records` GROUP BY DATE_FORMAT(`create_time`, '%Y-%m-01'), `name` ORDER BY DATE_FORMAT(`create_time`, '%Y-%m-01'), `name`",
+		},
+	}
+
+	db := dryRunDB(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			agg, err := ParseAggregation(values, statsAllowlist)
+			if err != nil {
+				t.Fatalf("ParseAggregation() error = %v", err)
+			}
+			got := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tx.Model(&testRecord{}).Scopes(agg.Scope()).Find(&[]map[string]any{})
+			})
+			if got != tt.want {
+				t.Errorf("SQL = %s\nwant  %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregationScopeUnsupportedDialect(t *testing.T) {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	agg := &Aggregation{Groups: []GroupBy{{Field: "create_time", Column: "create_time", Type: Time, Interval: IntervalDay}}}
+	if err := db.Model(&testRecord{}).Scopes(agg.Scope()).Find(&[]map[string]any{}).Error; err == nil {
+		t.Error("非 MySQL 驱动按时间分段 error = nil")
+	}
+}
+
+func TestAggregationKey(t *testing.T) {
+	agg := &Aggregation{
+		Groups:  []GroupBy{{Field: "name"}, {Field: "create_time", Interval: IntervalWeek}},
+		Metrics: []Metric{{Func: AggCount}, {Func: AggSum, Field: "amount"}},
+	}
+	if got, want := agg.Key(), "g:name:,g:create_time:week,m:count,m:sum_amount"; got != want {
+		t.Errorf("Key() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Scopes 将查询规格编译为 GORM 查询作用域（过滤条件和排序）
+// 列名来自白名单而非请求参数，并经过 GORM 转义；取值均以参数绑定方式传入，不拼接 SQL
+func (s *Spec) Scopes() []func(*gorm.DB) *gorm.DB {
+	scopes := s.FilterScopes()
+	if s != nil && len(s.Sorts) > 0 {
+		columns := make([]clause.OrderByColumn, 0, len(s.Sorts))
+		for _, sort := range s.Sorts {
+			columns = append(columns, clause.OrderByColumn{Column: clause.Column{Name: sort.Column}, Desc: sort.Desc})
+		}
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Order(clause.OrderBy{Columns: columns})
+		})
+	}
+
+	return scopes
+}
+
+// FilterScopes 只编译过滤条件，用于自行处理排序的查询（如游标分页）
+func (s *Spec) FilterScopes() []func(*gorm.DB) *gorm.DB {
+	if s == nil {
+		return nil
+	}
+
+	scopes := make([]func(*gorm.DB) *gorm.DB, 0, len(s.Filters)+1)
+	for _, filter := range s.Filters {
+		expr := filter.expression()
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where(expr)
+		})
+	}
+	return scopes
+}
+
+// FilterKey 过滤条件的规范化表示（与参数顺序无关），可作为缓存键的一部分
+func (s *Spec) FilterKey() string {
+	if s == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(s.Filters))
+	for _, filter := range s.Filters {
+		parts = append(parts, fmt.Sprintf("%s[%s]=%#v", filter.Field, filter.Op, filter.Values))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "&")
+}
+
+// HasSort 判断查询规格是否指定了排序
+func (s *Spec) HasSort() bool {
+	return s != nil && len(s.Sorts) > 0
+}
+
+// expression 将过滤条件转换为 GORM 表达式
+func (f Filter) expression() clause.Expression {
+	column := clause.Column{Table: clause.CurrentTable, Name: f.Column}
+	switch f.Op {
+	case OpNe:
+		return clause.Neq{Column: column, Value: f.Values[0]}
+	case OpGt:
+		return clause.Gt{Column: column, Value: f.Values[0]}
+	case OpGte:
+		return clause.Gte{Column: column, Value: f.Values[0]}
+	case OpLt:
+		return clause.Lt{Column: column, Value: f.Values[0]}
+	case OpLte:
+		return clause.Lte{Column: column, Value: f.Values[0]}
+	case OpLike:
+		return clause.Expr{SQL: "? LIKE ? ESCAPE '" + likeEscape + "'", Vars: []any{column, "%" + escapeLike(f.Values[0].(string)) + "%"}}
+	case OpIn:
+		return clause.IN{Column: column, Values: f.Values}
+	case OpNin:
+		return clause.Not(clause.IN{Column: column, Values: f.Values})
+	case OpNull:
+		// Eq / Neq 的值为 nil 时生成 IS NULL / IS NOT NULL
+		if f.Values[0].(bool) {
+			return clause.Eq{Column: column, Value: nil}
+		}
+		return clause.Neq{Column: column, Value: nil}
+	default:
+		return clause.Eq{Column: column, Value: f.Values[0]}
+	}
+}
+
+// LIKE 转义字符，不使用反斜杠以避免各数据库字符串字面量转义规则不同
+const likeEscape = "!"
+
+// escapeLike 转义 LIKE 通配符，使用户输入的 % 和 _ 按字面匹配
+func escapeLike(value string) string {
+	return strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_").Replace(value)
+}
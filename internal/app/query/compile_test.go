@@ -0,0 +1,104 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// testRecord 测试用的模型
+type testRecord struct {
+	ID   int
+	Name string
+}
+
+// dryRunDB 创建只生成 SQL 不连接数据库的 GORM 实例
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "user:pass@tcp(127.0.0.1:3306)/test", SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestSpecScopes(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"无条件", "", "SELECT * FROM `test_records`"},
+		{"等于", "filter[id]=3", "SELECT * FROM `test_records` WHERE `test_records`.`id` = 3"},
+		{"不等于", "filter[id][ne]=3", "SELECT * FROM `test_records` WHERE `test_records`.`id` <> 3"},
+		{"范围", "filter[score][gt]=1&filter[score][lte]=2", "SELECT * FROM `test_records` WHERE `test_records`.`score` > 1 AND `test_records`.`score` <= 2"},
+		{"包含并转义通配符", "filter[name][like]=50%25_a!", "SELECT * FROM `test_records` WHERE `test_records`.`name` LIKE '%50!%!_a!!%' ESCAPE '!'"},
+		{"在列表中", "filter[id][in]=1,2", "SELECT * FROM `test_records` WHERE `test_records`.`id` IN (1,2)"},
+		{"不在列表中", "filter[id][nin]=1,2", "SELECT * FROM `test_records` WHERE `test_records`.`id` NOT IN (1,2)"},
+		{"为空", "filter[name][null]=true", "SELECT * FROM `test_records` WHERE `test_records`.`name` IS NULL"},
+		{"不为空", "filter[name][null]=false", "SELECT * FROM `test_records` WHERE `test_records`.`name` IS NOT NULL"},
+		{"排序", "sort=-create_time,id", "SELECT * FROM `test_records` ORDER BY `create_time` DESC,`id`"},
+	}
+
+	db := dryRunDB(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			spec, err := Parse(values, testAllowlist)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tx.Scopes(spec.Scopes()...).Find(&[]testRecord{})
+			})
+			if got != tt.want {
+				t.Errorf("SQL = %s\nwant  %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterScopesIgnoreSort(t *testing.T) {
+	spec, err := Parse(url.Values{"filter[id]": {"1"}, "sort": {"id"}}, testAllowlist)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	got := dryRunDB(t).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Scopes(spec.FilterScopes()...).Find(&[]testRecord{})
+	})
+	if want := "SELECT * FROM `test_records` WHERE `test_records`.`id` = 1"; got != want {
+		t.Errorf("SQL = %s\nwant  %s", got, want)
+	}
+
+	var nilSpec *Spec
+	if scopes := nilSpec.Scopes(); len(scopes) != 0 {
+		t.Errorf("nil Spec Scopes() = %d scopes", len(scopes))
+	}
+}
+
+func TestFilterKey(t *testing.T) {
+	a, err := Parse(url.Values{"filter[id]": {"1"}, "filter[name][like]": {"x"}}, testAllowlist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseFilterMap(map[string]map[string]any{"name": {"like": "x"}, "id": {"eq": 1}}, testAllowlist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := Parse(url.Values{"filter[id]": {"2"}, "filter[name][like]": {"x"}}, testAllowlist)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.FilterKey() != b.FilterKey() {
+		t.Errorf("FilterKey() 与参数顺序有关: %q != %q", a.FilterKey(), b.FilterKey())
+	}
+	if a.FilterKey() == c.FilterKey() {
+		t.Errorf("不同取值的 FilterKey() 相同: %q", a.FilterKey())
+	}
+}
@@ -0,0 +1,93 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCursorCodecRoundTrip(t *testing.T) {
+	codec := NewCursorCodec("secret")
+	cursor := Cursor{
+		Sort:     "-create_time,id",
+		Backward: true,
+		Values:   []json.RawMessage{json.RawMessage(`"2024-01-01T00:00:00Z"`), json.RawMessage(`42`)},
+	}
+
+	token, err := codec.Encode(cursor)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	got, err := codec.Decode(token)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(*got, cursor) {
+		t.Errorf("Decode() = %#v, want %#v", *got, cursor)
+	}
+
+	// 相同密钥的编解码器（如多实例部署）可以解码
+	if _, err := NewCursorCodec("secret").Decode(token); err != nil {
+		t.Errorf("相同密钥 Decode() error = %v", err)
+	}
+}
+
+func TestCursorCodecRejectsInvalidTokens(t *testing.T) {
+	codec := NewCursorCodec("secret")
+	token, err := codec.Encode(Cursor{Sort: "id", Values: []json.RawMessage{json.RawMessage(`1`)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, sig, _ := strings.Cut(token, ".")
+
+	// 篡改排序键取值后保留原签名
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"id","v":[1000]}`)) + "." + sig
+	// 签名本身被修改
+	raw, _ := base64.RawURLEncoding.DecodeString(sig)
+	raw[0] ^= 0xff
+	badSig := payload + "." + base64.RawURLEncoding.EncodeToString(raw)
+	// 用其他密钥签名的游标
+	otherToken, err := NewCursorCodec("other").Encode(Cursor{Sort: "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 签名正确但内容不是合法的 JSON
+	notJSON := base64.RawURLEncoding.EncodeToString([]byte("not json"))
+	notJSON += "." + base64.RawURLEncoding.EncodeToString(codec.sign([]byte("not json")))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"空字符串", ""},
+		{"缺少签名", payload},
+		{"内容不是 base64", "!!!." + sig},
+		{"签名不是 base64", payload + ".!!!"},
+		{"篡改内容", tampered},
+		{"篡改签名", badSig},
+		{"其他密钥签名", otherToken},
+		{"内容不是 JSON", notJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.Decode(tt.token)
+			if got := errorMessage(err); got != "query.invalid_cursor" {
+				t.Errorf("Decode() error = %v, want query.invalid_cursor", err)
+			}
+		})
+	}
+}
+
+func TestNewCursorCodecRandomSecret(t *testing.T) {
+	token, err := NewCursorCodec("").Encode(Cursor{Sort: "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 未配置密钥时每个编解码器使用各自的随机密钥
+	if _, err := NewCursorCodec("").Decode(token); err == nil {
+		t.Error("随机密钥的编解码器不应解码其他实例的游标")
+	}
+}
@@ -0,0 +1,45 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Project 按 fields 裁剪响应数据，只保留指定的 JSON 字段；fields 为空时原样返回
+// data 可以是单个对象或对象切片，字段名与 JSON 标签一致
+func Project(data any, fields []string) (any, error) {
+	if len(fields) == 0 || data == nil {
+		return data, nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("字段裁剪序列化失败: %w", err)
+	}
+
+	// 先按切片解析，失败时按单个对象解析
+	var list []map[string]any
+	if err := json.Unmarshal(raw, &list); err == nil {
+		for i, item := range list {
+			list[i] = pick(item, fields)
+		}
+		return list, nil
+	}
+
+	var item map[string]any
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return nil, fmt.Errorf("字段裁剪反序列化失败: %w", err)
+	}
+	return pick(item, fields), nil
+}
+
+// pick 从对象中挑选指定字段
+func pick(item map[string]any, fields []string) map[string]any {
+	picked := make(map[string]any, len(fields))
+	for _, field := range fields {
+		if value, ok := item[field]; ok {
+			picked[field] = value
+		}
+	}
+	return picked
+}
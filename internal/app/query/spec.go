@@ -0,0 +1,296 @@
+// Package query 通用列表查询规格：解析 filter / sort / fields 查询参数，按模型字段白名单校验后编译为 GORM 查询作用域
+//
+// 查询参数格式：
+//
+//	?filter[field1][gte]=3&filter[field2][like]=abc&filter[id][in]=1,2,3&sort=-create_time,id&fields=id,field2
+//
+// filter[字段]=值 等价于 filter[字段][eq]=值；sort 中字段前加 "-" 表示降序；fields 指定响应中返回的字段。
+package query
+
+import (
+	"fmt"
+	"gin-template/internal/utils"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 查询规格的数量限制，避免构造过于复杂的查询
+const (
+	maxFilters  = 20  // 最多过滤条件数
+	maxSorts    = 5   // 最多排序字段数
+	maxInValues = 100 // in / nin 最多取值数
+)
+
+// Operator 过滤操作符
+type Operator string
+
+const (
+	OpEq   Operator = "eq"   // 等于
+	OpNe   Operator = "ne"   // 不等于
+	OpGt   Operator = "gt"   // 大于
+	OpGte  Operator = "gte"  // 大于等于
+	OpLt   Operator = "lt"   // 小于
+	OpLte  Operator = "lte"  // 小于等于
+	OpLike Operator = "like" // 包含（LIKE %值%）
+	OpIn   Operator = "in"   // 在列表中，值以逗号分隔
+	OpNin  Operator = "nin"  // 不在列表中，值以逗号分隔
+	OpNull Operator = "null" // 为空（值为 true）或不为空（值为 false）
+)
+
+// FieldType 字段值类型，决定取值的解析方式和默认允许的操作符
+type FieldType int
+
+const (
+	String FieldType = iota
+	Int
+	Float
+	Bool
+	Time // 支持 RFC 3339 和 2006-01-02 格式，只有日期时按 SetLocation 设置的时区取当天零点
+)
+
+// location 只有日期的时间取值的解析时区，默认 UTC
+var location = time.UTC
+
+// SetLocation 设置只有日期的时间取值的解析时区，应与数据库的存储时区（time_zone 配置）一致，启动时调用
+func SetLocation(loc *time.Location) {
+	if loc != nil {
+		location = loc
+	}
+}
+
+// 各类型默认允许的操作符
+var defaultOperators = map[FieldType][]Operator{
+	String: {OpEq, OpNe, OpLike, OpIn, OpNin, OpNull},
+	Int:    {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpNin, OpNull},
+	Float:  {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpNin, OpNull},
+	Bool:   {OpEq, OpNe, OpNull},
+	Time:   {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpNull},
+}
+
+// Field 白名单中的字段定义
+type Field struct {
+	Column     string     // 数据库列名
+	Type       FieldType  // 值类型
+	Operators  []Operator // 允许的过滤操作符，为空时使用类型的默认操作符
+	Filterable bool       // 是否允许过滤
+	Sortable   bool       // 是否允许排序
+	Selectable bool       // 是否允许通过 fields 选择返回（键名需与响应 JSON 字段名一致）
+	Groupable  bool       // 是否允许作为统计的分组字段（group_by），Time 类型按 day / week / month 分段
+	Aggregable bool       // 是否允许 sum / avg / min / max 聚合，仅用于 Int / Float 类型
+}
+
+// Allowlist 模型的查询字段白名单：查询参数中的字段名 -> 字段定义，未登记的字段一律拒绝
+type Allowlist map[string]Field
+
+// Filter 过滤条件
+type Filter struct {
+	Field  string
+	Column string
+	Op     Operator
+	Values []any // 已按字段类型解析的取值，in / nin 为多个，其余为一个
+}
+
+// Sort 排序字段
+type Sort struct {
+	Field  string
+	Column string
+	Desc   bool
+}
+
+// Spec 解析后的查询规格
+type Spec struct {
+	Filters []Filter
+	Sorts   []Sort
+	Fields  []string // 响应中返回的字段，为空返回全部字段
+}
+
+// filter[字段] 或 filter[字段][操作符]
+var filterKeyPattern = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
+
+// Parse 解析查询参数并按白名单校验，无法识别或不允许的字段、操作符和取值返回参数错误的业务异常
+// 与 filter / sort / fields 无关的查询参数（如分页参数）会被忽略
+func Parse(values url.Values, allow Allowlist) (*Spec, error) {
+	spec := &Spec{}
+
+	// 按参数名顺序解析，使过滤条件的顺序（及生成的 SQL）稳定
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		vals := values[key]
+		matches := filterKeyPattern.FindStringSubmatch(key)
+		if matches == nil {
+			continue
+		}
+		name, op := matches[1], Operator(matches[2])
+		if op == "" {
+			op = OpEq
+		}
+		for _, raw := range vals {
+			filter, err := parseFilter(allow, name, op, raw)
+			if err != nil {
+				return nil, err
+			}
+			spec.Filters = append(spec.Filters, filter)
+		}
+	}
+	if len(spec.Filters) > maxFilters {
+		return nil, invalid("query.too_many_filters", map[string]any{"max": maxFilters})
+	}
+
+	if raw := values.Get("sort"); raw != "" {
+		for _, item := range splitList(raw) {
+			desc := strings.HasPrefix(item, "-")
+			name := strings.TrimPrefix(strings.TrimPrefix(item, "-"), "+")
+			field, ok := allow[name]
+			if !ok || !field.Sortable {
+				return nil, invalid("query.not_sortable", map[string]any{"field": name})
+			}
+			spec.Sorts = append(spec.Sorts, Sort{Field: name, Column: field.Column, Desc: desc})
+		}
+		if len(spec.Sorts) > maxSorts {
+			return nil, invalid("query.too_many_sorts", map[string]any{"max": maxSorts})
+		}
+	}
+
+	if raw := values.Get("fields"); raw != "" {
+		for _, name := range splitList(raw) {
+			field, ok := allow[name]
+			if !ok || !field.Selectable {
+				return nil, invalid("query.not_selectable", map[string]any{"field": name})
+			}
+			spec.Fields = append(spec.Fields, name)
+		}
+	}
+
+	return spec, nil
+}
+
+// ParseFilterMap 解析 JSON 请求体中的过滤条件（字段 -> 操作符 -> 取值），规则与查询参数中的 filter 相同
+// 例如 {"field1": {"gte": 3}, "id": {"in": [1, 2]}}，数组取值按逗号拼接；JSON 数字解码为 float64，按十进制格式转换，避免大整数变成科学计数法
+func ParseFilterMap(filter map[string]map[string]any, allow Allowlist) (*Spec, error) {
+	values := make(url.Values, len(filter))
+	for name, ops := range filter {
+		for op, value := range ops {
+			key := "filter[" + name + "][" + op + "]"
+			if items, ok := value.([]any); ok {
+				parts := make([]string, 0, len(items))
+				for _, item := range items {
+					parts = append(parts, formatValue(item))
+				}
+				values.Add(key, strings.Join(parts, ","))
+				continue
+			}
+			values.Add(key, formatValue(value))
+		}
+	}
+	return Parse(values, allow)
+}
+
+// formatValue 将 JSON 解码后的取值转换为查询参数格式，数字不使用科学计数法（如 1000000 不转换为 1e+06）
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// parseFilter 校验并解析单个过滤条件
+func parseFilter(allow Allowlist, name string, op Operator, raw string) (Filter, error) {
+	field, ok := allow[name]
+	if !ok || !field.Filterable {
+		return Filter{}, invalid("query.not_filterable", map[string]any{"field": name})
+	}
+
+	operators := field.Operators
+	if len(operators) == 0 {
+		operators = defaultOperators[field.Type]
+	}
+	if !containsOperator(operators, op) {
+		return Filter{}, invalid("query.unsupported_operator", map[string]any{"field": name, "op": string(op)})
+	}
+
+	filter := Filter{Field: name, Column: field.Column, Op: op}
+	switch op {
+	case OpNull:
+		isNull, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Filter{}, invalid("query.invalid_value", map[string]any{"field": name, "value": raw})
+		}
+		filter.Values = []any{isNull}
+	case OpIn, OpNin:
+		items := splitList(raw)
+		if len(items) == 0 || len(items) > maxInValues {
+			return Filter{}, invalid("query.invalid_value", map[string]any{"field": name, "value": raw})
+		}
+		for _, item := range items {
+			value, err := parseValue(field.Type, item)
+			if err != nil {
+				return Filter{}, invalid("query.invalid_value", map[string]any{"field": name, "value": item})
+			}
+			filter.Values = append(filter.Values, value)
+		}
+	default:
+		value, err := parseValue(field.Type, raw)
+		if err != nil {
+			return Filter{}, invalid("query.invalid_value", map[string]any{"field": name, "value": raw})
+		}
+		filter.Values = []any{value}
+	}
+	return filter, nil
+}
+
+// parseValue 按字段类型解析取值
+func parseValue(fieldType FieldType, raw string) (any, error) {
+	switch fieldType {
+	case Int:
+		return strconv.ParseInt(raw, 10, 64)
+	case Float:
+		return strconv.ParseFloat(raw, 64)
+	case Bool:
+		return strconv.ParseBool(raw)
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		return time.ParseInLocation(time.DateOnly, raw, location)
+	default:
+		return raw, nil
+	}
+}
+
+// splitList 按逗号拆分并去除空白项
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// containsOperator 判断操作符是否在允许列表中
+func containsOperator(operators []Operator, op Operator) bool {
+	for _, item := range operators {
+		if item == op {
+			return true
+		}
+	}
+	return false
+}
+
+// invalid 创建查询参数错误的业务异常
+func invalid(message string, params map[string]any) error {
+	return utils.NewBusinessErrorWithParams(utils.ErrCodeParamInvalid, message, params)
+}
@@ -0,0 +1,288 @@
+package query
+
+import (
+	"encoding/json"
+	"gin-template/internal/utils"
+	"net/url"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// testAllowlist 测试用的字段白名单
+var testAllowlist = Allowlist{
+	"id":          {Column: "id", Type: Int, Filterable: true, Sortable: true, Selectable: true},
+	"name":        {Column: "name", Type: String, Filterable: true, Selectable: true},
+	"score":       {Column: "score", Type: Float, Filterable: true, Sortable: true},
+	"active":      {Column: "is_active", Type: Bool, Filterable: true},
+	"create_time": {Column: "create_time", Type: Time, Filterable: true, Sortable: true},
+	"status":      {Column: "status", Type: String, Operators: []Operator{OpEq}, Filterable: true},
+	"secret":      {Column: "secret", Type: String},
+}
+
+// errorMessage 返回业务异常的消息键，非业务异常返回空字符串
+func errorMessage(err error) string {
+	if bizErr, ok := utils.GetBusinessError(err); ok {
+		return bizErr.Message
+	}
+	return ""
+}
+
+func TestParseFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []Filter
+	}{
+		{
+			name:  "默认操作符为 eq",
+			query: "filter[id]=3",
+			want:  []Filter{{Field: "id", Column: "id", Op: OpEq, Values: []any{int64(3)}}},
+		},
+		{
+			name:  "显式操作符",
+			query: "filter[score][gte]=1.5",
+			want:  []Filter{{Field: "score", Column: "score", Op: OpGte, Values: []any{1.5}}},
+		},
+		{
+			name:  "in 按逗号拆分并去除空白项",
+			query: "filter[id][in]=1, 2,,3",
+			want:  []Filter{{Field: "id", Column: "id", Op: OpIn, Values: []any{int64(1), int64(2), int64(3)}}},
+		},
+		{
+			name:  "null 取值为布尔值",
+			query: "filter[name][null]=false",
+			want:  []Filter{{Field: "name", Column: "name", Op: OpNull, Values: []any{false}}},
+		},
+		{
+			name:  "列名取自白名单",
+			query: "filter[active]=true",
+			want:  []Filter{{Field: "active", Column: "is_active", Op: OpEq, Values: []any{true}}},
+		},
+		{
+			name:  "时间支持 RFC 3339",
+			query: "filter[create_time][lt]=2024-01-02T03:04:05Z",
+			want:  []Filter{{Field: "create_time", Column: "create_time", Op: OpLt, Values: []any{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}}},
+		},
+		{
+			name:  "时间支持日期",
+			query: "filter[create_time][gte]=2024-01-02",
+			want:  []Filter{{Field: "create_time", Column: "create_time", Op: OpGte, Values: []any{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}}},
+		},
+		{
+			name:  "忽略无关参数",
+			query: "page=1&page_size=10&filter=x",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			spec, err := Parse(values, testAllowlist)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(spec.Filters, tt.want) {
+				t.Errorf("Filters = %#v, want %#v", spec.Filters, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDateLocation(t *testing.T) {
+	// 只有日期的取值不受服务器时区影响
+	local := time.Local
+	time.Local = time.FixedZone("UTC+8", 8*3600)
+	defer func() { time.Local = local }()
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		want time.Time
+	}{
+		{"默认 UTC", nil, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"配置的时区", time.FixedZone("UTC-5", -5*3600), time.Date(2024, 1, 2, 5, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.loc != nil {
+				SetLocation(tt.loc)
+				defer SetLocation(time.UTC)
+			}
+			spec, err := Parse(url.Values{"filter[create_time][gte]": {"2024-01-02"}}, testAllowlist)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got := spec.Filters[0].Values[0].(time.Time)
+			if !got.Equal(tt.want) || got.Location() != location {
+				t.Errorf("取值 = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterOrder(t *testing.T) {
+	values := url.Values{"filter[score][lt]": {"9"}, "filter[id][gte]": {"1"}, "filter[name]": {"a"}}
+	for range 20 {
+		spec, err := Parse(values, testAllowlist)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		var fields []string
+		for _, filter := range spec.Filters {
+			fields = append(fields, filter.Field)
+		}
+		if !reflect.DeepEqual(fields, []string{"id", "name", "score"}) {
+			t.Fatalf("过滤条件顺序 = %v", fields)
+		}
+	}
+}
+
+func TestParseSortAndFields(t *testing.T) {
+	values := url.Values{"sort": {"-create_time, +id"}, "fields": {"id,name"}}
+	spec, err := Parse(values, testAllowlist)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	wantSorts := []Sort{{Field: "create_time", Column: "create_time", Desc: true}, {Field: "id", Column: "id"}}
+	if !reflect.DeepEqual(spec.Sorts, wantSorts) {
+		t.Errorf("Sorts = %#v, want %#v", spec.Sorts, wantSorts)
+	}
+	if !reflect.DeepEqual(spec.Fields, []string{"id", "name"}) {
+		t.Errorf("Fields = %v", spec.Fields)
+	}
+	if !spec.HasSort() {
+		t.Error("HasSort() = false")
+	}
+	if got := SortSignature(spec.Sorts); got != "-create_time,id" {
+		t.Errorf("SortSignature() = %q", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tooManyFilters := url.Values{}
+	for i := 0; i <= maxFilters; i++ {
+		tooManyFilters.Add("filter[id][ne]", "1")
+	}
+
+	tests := []struct {
+		name   string
+		values url.Values
+		want   string
+	}{
+		{"未登记的字段", url.Values{"filter[unknown]": {"1"}}, "query.not_filterable"},
+		{"不允许过滤的字段", url.Values{"filter[secret]": {"x"}}, "query.not_filterable"},
+		{"类型不支持的操作符", url.Values{"filter[active][gt]": {"true"}}, "query.unsupported_operator"},
+		{"字段限定的操作符", url.Values{"filter[status][ne]": {"x"}}, "query.unsupported_operator"},
+		{"未知操作符", url.Values{"filter[id][between]": {"1"}}, "query.unsupported_operator"},
+		{"整数取值无效", url.Values{"filter[id]": {"abc"}}, "query.invalid_value"},
+		{"in 中的取值无效", url.Values{"filter[id][in]": {"1,x"}}, "query.invalid_value"},
+		{"in 取值为空", url.Values{"filter[id][in]": {" , "}}, "query.invalid_value"},
+		{"null 取值无效", url.Values{"filter[id][null]": {"maybe"}}, "query.invalid_value"},
+		{"时间取值无效", url.Values{"filter[create_time]": {"yesterday"}}, "query.invalid_value"},
+		{"过滤条件过多", tooManyFilters, "query.too_many_filters"},
+		{"不允许排序的字段", url.Values{"sort": {"name"}}, "query.not_sortable"},
+		{"排序字段过多", url.Values{"sort": {"id,-id,score,-score,create_time,-create_time"}}, "query.too_many_sorts"},
+		{"不允许选择的字段", url.Values{"fields": {"id,score"}}, "query.not_selectable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.values, testAllowlist)
+			if err == nil {
+				t.Fatal("Parse() error = nil")
+			}
+			if got := errorMessage(err); got != tt.want {
+				t.Errorf("error = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	type item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Note string `json:"note"`
+	}
+
+	tests := []struct {
+		name   string
+		data   any
+		fields []string
+		want   any
+	}{
+		{"未指定字段时原样返回", item{ID: 1}, nil, item{ID: 1}},
+		{"单个对象", item{ID: 1, Name: "a", Note: "b"}, []string{"id", "name"}, map[string]any{"id": float64(1), "name": "a"}},
+		{"对象切片", []item{{ID: 1}, {ID: 2}}, []string{"id"}, []map[string]any{{"id": float64(1)}, {"id": float64(2)}}},
+		{"忽略不存在的字段", item{ID: 1}, []string{"missing"}, map[string]any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Project(tt.data, tt.fields)
+			if err != nil {
+				t.Fatalf("Project() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Project() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterMap(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Filter
+	}{
+		{
+			name: "大整数不转换为科学计数法",
+			body: `{"id": {"gte": 1000000}}`,
+			want: []Filter{{Field: "id", Column: "id", Op: OpGte, Values: []any{int64(1000000)}}},
+		},
+		{
+			name: "数组取值",
+			body: `{"id": {"in": [1, 25000000]}}`,
+			want: []Filter{{Field: "id", Column: "id", Op: OpIn, Values: []any{int64(1), int64(25000000)}}},
+		},
+		{
+			name: "小数",
+			body: `{"score": {"lt": 0.0000001}}`,
+			want: []Filter{{Field: "score", Column: "score", Op: OpLt, Values: []any{0.0000001}}},
+		},
+		{
+			name: "布尔值和字符串",
+			body: `{"active": {"eq": true}, "name": {"like": "a,b"}}`,
+			want: []Filter{
+				{Field: "active", Column: "is_active", Op: OpEq, Values: []any{true}},
+				{Field: "name", Column: "name", Op: OpLike, Values: []any{"a,b"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filter map[string]map[string]any
+			if err := json.Unmarshal([]byte(tt.body), &filter); err != nil {
+				t.Fatal(err)
+			}
+			spec, err := ParseFilterMap(filter, testAllowlist)
+			if err != nil {
+				t.Fatalf("ParseFilterMap() error = %v", err)
+			}
+			got := spec.Filters
+			sort.Slice(got, func(i, j int) bool { return got[i].Field < got[j].Field })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filters = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"gin-template/internal/app/query"
+)
+
+// Aggregate 按统计规格分组聚合，scopes 为过滤条件；最多返回 limit 个分组，超出时截断并返回 true
+func (r *BaseRepository[T]) Aggregate(ctx context.Context, agg *query.Aggregation, limit int, scopes ...Scope) ([]query.AggregateRow, bool, error) {
+	rows, err := r.Model(ctx).Scopes(scopes...).Scopes(agg.Scope()).Limit(limit + 1).Rows()
+	if err != nil {
+		return nil, false, r.TranslateError(err, "aggregate", nil)
+	}
+	defer rows.Close()
+
+	result := make([]query.AggregateRow, 0)
+	for rows.Next() {
+		if len(result) == limit {
+			return result, true, nil
+		}
+		row, err := agg.Scan(rows)
+		if err != nil {
+			return nil, false, r.TranslateError(err, "aggregate", nil)
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, r.TranslateError(err, "aggregate", nil)
+	}
+	return result, false, nil
+}
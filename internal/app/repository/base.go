@@ -0,0 +1,319 @@
+// Package repository 通用数据访问层：提供泛型 BaseRepository，封装各模块通用的 CRUD、分页、批量操作及错误转换。
+// 各模块的 Repository 嵌入 BaseRepository，只需实现与通用逻辑不同的部分。
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-template/internal/app/database"
+	"gin-template/internal/app/query"
+	"gin-template/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// BaseRepository 泛型数据访问基类，T 为 GORM 数据模型
+type BaseRepository[T any] struct {
+	db              *gorm.DB
+	entity          string             // 实体名称，记录在系统错误的结构化字段中，便于排查
+	notFoundMessage string             // 资源不存在时的消息键
+	uniqueMessages  map[string]string  // 唯一索引字段名 -> 冲突时的消息键（消息模板可使用 {{.value}} 引用冲突值）
+	versionColumn   string             // 乐观锁版本号字段，为空表示不启用
+	cursorCodec     *query.CursorCodec // 游标编解码器，为空表示不支持游标分页
+}
+
+// NewBaseRepository 创建泛型数据访问基类实例
+func NewBaseRepository[T any](db *gorm.DB, entity string) *BaseRepository[T] {
+	return &BaseRepository[T]{
+		db:              db,
+		entity:          entity,
+		notFoundMessage: "error.not_found",
+		uniqueMessages:  make(map[string]string),
+	}
+}
+
+// WithNotFoundMessage 设置资源不存在时的消息键
+func (r *BaseRepository[T]) WithNotFoundMessage(message string) *BaseRepository[T] {
+	r.notFoundMessage = message
+	return r
+}
+
+// WithUniqueMessage 设置唯一索引冲突时的消息键，未设置的唯一索引冲突使用通用消息 error.duplicate_key
+func (r *BaseRepository[T]) WithUniqueMessage(field, message string) *BaseRepository[T] {
+	r.uniqueMessages[field] = message
+	return r
+}
+
+// WithVersion 启用乐观锁：每次更新（含软删除、恢复）时版本号字段自增，UpdateByIDWithVersion 按版本号条件更新
+func (r *BaseRepository[T]) WithVersion(column string) *BaseRepository[T] {
+	r.versionColumn = column
+	return r
+}
+
+// DB 返回绑定上下文的数据库会话，WithContext 将上下文与数据库操作关联，支持超时和取消
+// 上下文中存在事务（由 TxManager.WithinTx 开启）时自动加入该事务
+func (r *BaseRepository[T]) DB(ctx context.Context) *gorm.DB {
+	return database.DBFromContext(ctx, r.db)
+}
+
+// Model 返回绑定上下文和数据模型的数据库会话
+func (r *BaseRepository[T]) Model(ctx context.Context) *gorm.DB {
+	return r.DB(ctx).Model(new(T))
+}
+
+// Find 按查询作用域查询列表
+func (r *BaseRepository[T]) Find(ctx context.Context, scopes ...Scope) ([]*T, error) {
+	var list []*T
+	if err := r.DB(ctx).Scopes(scopes...).Find(&list).Error; err != nil {
+		return nil, r.TranslateError(err, "find", nil)
+	}
+	return list, nil
+}
+
+// FindPage 按查询作用域分页查询，返回当前页数据和总条数
+func (r *BaseRepository[T]) FindPage(ctx context.Context, page, pageSize int, scopes ...Scope) ([]*T, int64, error) {
+	// 构建基础查询
+	query := r.Model(ctx).Scopes(scopes...)
+
+	// 计算总数
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, r.TranslateError(err, "count", nil)
+	}
+
+	// 查询数据
+	var list []*T
+	if err := query.Scopes(Paginate(page, pageSize)).Find(&list).Error; err != nil {
+		return nil, 0, r.TranslateError(err, "find_page", nil)
+	}
+
+	return list, total, nil
+}
+
+// First 按查询作用域查询第一条记录（默认按主键升序），不存在时返回资源不存在的业务异常
+func (r *BaseRepository[T]) First(ctx context.Context, scopes ...Scope) (*T, error) {
+	entity := new(T)
+	if err := r.DB(ctx).Scopes(scopes...).First(entity).Error; err != nil {
+		return nil, r.TranslateError(err, "first", nil)
+	}
+	return entity, nil
+}
+
+// FindByID 根据主键查询，不存在时返回资源不存在的业务异常
+func (r *BaseRepository[T]) FindByID(ctx context.Context, id any, scopes ...Scope) (*T, error) {
+	entity := new(T)
+	if err := r.DB(ctx).Scopes(scopes...).Scopes(ByID(id)).First(entity).Error; err != nil {
+		return nil, r.TranslateError(err, "get", id)
+	}
+	return entity, nil
+}
+
+// Stream 按查询作用域逐行读取记录并交给 fn 处理，不在内存中保留全部结果，适用于导出等大数据量场景
+// fn 返回错误或 ctx 被取消（如客户端断开连接）时停止读取并返回该错误；查询期间占用一个数据库连接
+func (r *BaseRepository[T]) Stream(ctx context.Context, fn func(*T) error, scopes ...Scope) error {
+	db := r.Model(ctx).Scopes(scopes...)
+	rows, err := db.Rows()
+	if err != nil {
+		return r.TranslateError(err, "stream", nil)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entity := new(T)
+		if err := db.ScanRows(rows, entity); err != nil {
+			return r.TranslateError(err, "stream", nil)
+		}
+		if err := fn(entity); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return r.TranslateError(err, "stream", nil)
+	}
+	return nil
+}
+
+// Pluck 按查询作用域查询单列的值到 dest（如 *[]int）
+func (r *BaseRepository[T]) Pluck(ctx context.Context, column string, dest any, scopes ...Scope) error {
+	if err := r.Model(ctx).Scopes(scopes...).Pluck(column, dest).Error; err != nil {
+		return r.TranslateError(err, "pluck", nil)
+	}
+	return nil
+}
+
+// Count 按查询作用域统计条数
+func (r *BaseRepository[T]) Count(ctx context.Context, scopes ...Scope) (int64, error) {
+	var total int64
+	if err := r.Model(ctx).Scopes(scopes...).Count(&total).Error; err != nil {
+		return 0, r.TranslateError(err, "count", nil)
+	}
+	return total, nil
+}
+
+// Exists 判断是否存在符合查询作用域的记录
+func (r *BaseRepository[T]) Exists(ctx context.Context, scopes ...Scope) (bool, error) {
+	total, err := r.Count(ctx, scopes...)
+	return total > 0, err
+}
+
+// Create 创建记录，创建成功后主键回填到 entity 中
+func (r *BaseRepository[T]) Create(ctx context.Context, entity *T) error {
+	if err := r.DB(ctx).Create(entity).Error; err != nil {
+		return r.TranslateError(err, "create", nil)
+	}
+	return nil
+}
+
+// BatchCreate 批量创建记录，batchSize > 0 时按批次分多条 INSERT 语句执行
+func (r *BaseRepository[T]) BatchCreate(ctx context.Context, entities []*T, batchSize int) error {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	var err error
+	if batchSize > 0 {
+		err = r.DB(ctx).CreateInBatches(entities, batchSize).Error
+	} else {
+		err = r.DB(ctx).Create(entities).Error
+	}
+	if err != nil {
+		return r.TranslateError(err, "batch_create", nil)
+	}
+	return nil
+}
+
+// UpdateByID 根据主键更新指定字段，记录不存在（或已被软删除）时返回资源不存在的业务异常
+func (r *BaseRepository[T]) UpdateByID(ctx context.Context, id any, fields map[string]interface{}, scopes ...Scope) error {
+	return r.UpdateByIDWithVersion(ctx, id, nil, fields, scopes...)
+}
+
+// UpdateByIDWithVersion 根据主键和期望的版本号条件更新（WHERE version IN (...)），版本号在同一条 UPDATE 语句中自增
+//   - versions 为空时不校验版本号
+//   - 记录存在但版本号不匹配时返回版本冲突的业务异常（ErrCodeVersionConflict）
+//   - 记录不存在（或已被软删除）时返回资源不存在的业务异常
+func (r *BaseRepository[T]) UpdateByIDWithVersion(ctx context.Context, id any, versions []int, fields map[string]interface{}, scopes ...Scope) error {
+	query := r.Model(ctx).Scopes(scopes...).Scopes(ByID(id))
+	if len(versions) > 0 && r.versionColumn != "" {
+		query = query.Scopes(In(r.versionColumn, versions))
+	}
+
+	result := query.Updates(r.bumpVersion(fields))
+	if result.Error != nil {
+		return r.TranslateError(result.Error, "update", id)
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// 未更新任何行：区分版本冲突和记录不存在，从主库读取避免复制延迟导致误判
+	if len(versions) > 0 && r.versionColumn != "" {
+		checkScopes := append(append([]Scope{}, scopes...), ByID(id), database.UsePrimary)
+		exists, err := r.Exists(ctx, checkScopes...)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return utils.WithErrorFields(
+				utils.NewBusinessError(utils.ErrCodeVersionConflict, "error.version_conflict"),
+				utils.ErrorFields{"entity": r.entity, "id": id, "expectedVersions": versions},
+			)
+		}
+	}
+	return utils.NewBusinessError(utils.ErrCodeResourceNotFound, "error.not_found_or_deleted")
+}
+
+// bumpVersion 启用乐观锁时在更新字段中追加版本号自增，不修改调用方传入的 map
+func (r *BaseRepository[T]) bumpVersion(fields map[string]interface{}) map[string]interface{} {
+	if r.versionColumn == "" {
+		return fields
+	}
+	bumped := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		bumped[k] = v
+	}
+	bumped[r.versionColumn] = gorm.Expr(r.versionColumn + " + 1")
+	return bumped
+}
+
+// UpdateWhere 按查询作用域批量更新指定字段，返回受影响的行数
+func (r *BaseRepository[T]) UpdateWhere(ctx context.Context, fields map[string]interface{}, scopes ...Scope) (int64, error) {
+	result := r.Model(ctx).Scopes(scopes...).Updates(fields)
+	if result.Error != nil {
+		return 0, r.TranslateError(result.Error, "update_where", nil)
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteByID 根据主键物理删除，记录不存在（或已被软删除）时返回资源不存在的业务异常
+func (r *BaseRepository[T]) DeleteByID(ctx context.Context, id any, scopes ...Scope) error {
+	result := r.DB(ctx).Scopes(scopes...).Scopes(ByID(id)).Delete(new(T))
+	if result.Error != nil {
+		return r.TranslateError(result.Error, "delete", id)
+	}
+	if result.RowsAffected == 0 {
+		return utils.NewBusinessError(utils.ErrCodeResourceNotFound, "error.not_found_or_deleted")
+	}
+	return nil
+}
+
+// DeleteWhere 按查询作用域批量物理删除，返回受影响的行数
+// 未传入任何作用域时 GORM 会拒绝执行（ErrMissingWhereClause），避免误删全表
+func (r *BaseRepository[T]) DeleteWhere(ctx context.Context, scopes ...Scope) (int64, error) {
+	result := r.DB(ctx).Scopes(scopes...).Delete(new(T))
+	if result.Error != nil {
+		return 0, r.TranslateError(result.Error, "delete_where", nil)
+	}
+	return result.RowsAffected, nil
+}
+
+// SoftDeleteByID 根据主键软删除，记录删除时间和删除人，已删除的数据不会被重复删除
+func (r *BaseRepository[T]) SoftDeleteByID(ctx context.Context, id any) error {
+	return r.UpdateByID(ctx, id, database.SoftDeleteUpdates(ctx))
+}
+
+// RestoreByID 根据主键恢复已软删除的数据，未删除或不存在的数据均返回资源不存在的业务异常
+func (r *BaseRepository[T]) RestoreByID(ctx context.Context, id any) error {
+	result := r.Model(ctx).Scopes(database.OnlyDeleted, ByID(id)).Updates(r.bumpVersion(database.RestoreUpdates()))
+	if result.Error != nil {
+		return r.TranslateError(result.Error, "restore", id)
+	}
+	if result.RowsAffected == 0 {
+		return utils.NewBusinessError(utils.ErrCodeResourceNotFound, r.notFoundMessage)
+	}
+	return nil
+}
+
+// TranslateError 将数据库错误转换为统一的业务异常或系统异常
+//   - 记录不存在：资源不存在的业务异常
+//   - 唯一索引冲突：重复键业务异常，消息键由 WithUniqueMessage 配置
+//   - 其他错误：携带实体、主键、操作等结构化字段的系统异常
+func (r *BaseRepository[T]) TranslateError(err error, operation string, id any) error {
+	if err == nil {
+		return nil
+	}
+
+	// 已经转换过的异常直接返回
+	if _, ok := utils.GetBusinessError(err); ok {
+		return err
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.WrapBusinessError(utils.ErrCodeResourceNotFound, r.notFoundMessage, err)
+	}
+
+	// 检查是否是重复键错误（需在数据库层配置唯一约束）
+	if exist, fieldName, value := utils.IsUniqueConstraintError(err); exist {
+		params := map[string]any{"field": fieldName, "value": value}
+		if message, ok := r.uniqueMessages[fieldName]; ok {
+			return utils.NewBusinessErrorWithParams(utils.ErrCodeDuplicateKey, message, params)
+		}
+		return utils.NewBusinessErrorWithParams(utils.ErrCodeDuplicateKey, "error.duplicate_key", params)
+	}
+
+	fields := utils.ErrorFields{"entity": r.entity, "operation": operation}
+	if id != nil {
+		fields["id"] = id
+	}
+	return utils.WithErrorFields(utils.NewSystemError(fmt.Errorf("数据库操作失败(%s.%s): %w", r.entity, operation, err)), fields)
+}
@@ -0,0 +1,403 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-template/internal/app/database"
+	"gin-template/internal/app/tenant"
+	"gin-template/internal/utils"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ConflictStrategy 创建记录遇到唯一键冲突时的处理策略
+type ConflictStrategy string
+
+const (
+	ConflictFail   ConflictStrategy = "fail"   // 冲突的记录创建失败（默认）
+	ConflictSkip   ConflictStrategy = "skip"   // 跳过已存在的记录
+	ConflictUpdate ConflictStrategy = "update" // 以新值更新已存在的记录，已软删除的记录同时恢复
+)
+
+// 批量创建中单条记录的处理结果
+const (
+	BatchCreated = "created"
+	BatchSkipped = "skipped"
+	BatchUpdated = "updated"
+	BatchFailed  = "failed"
+)
+
+// BatchCreateOptions 分批创建选项
+type BatchCreateOptions struct {
+	ChunkSize       int              // 每批记录数，<= 0 时为 100
+	OnConflict      ConflictStrategy // 唯一键冲突处理策略，为空时为 fail
+	ConflictColumns []string         // 判断冲突的唯一键列，skip / update 策略必填；模型包含 tenant_id 时无需列出，唯一索引需为 (唯一键列, tenant_id)
+	UpdateColumns   []string         // update 策略下以新值覆盖的列，自动更新时间列和版本号会自动处理
+}
+
+// BatchCreateItem 单条记录的创建结果，下标与传入的记录一致
+type BatchCreateItem struct {
+	Status string // created / skipped / updated / failed
+	Err    error  // 失败原因，已转换为业务异常或系统异常
+}
+
+// CreateInChunks 按批次创建记录，每批在独立事务中执行（上下文中已有事务时为保存点），单条记录失败不影响其他记录
+//   - 整批插入失败时逐条重试（每条使用保存点），定位失败的记录
+//   - skip / update 策略通过 ON CONFLICT 处理已存在的记录，新建和命中的记录主键均回填到 entities 中
+//   - 请求内唯一键重复的记录：skip 策略跳过，其他策略视为重复键失败
+//
+// 只有无法继续执行的错误（如配置错误、事务无法开启或提交）才通过 error 返回
+//
+// 不使用 GORM 的 CreateInBatches：它在同一个事务中插入所有批次，遇到第一个失败的批次即中止，
+// 无法按批提交、逐条定位失败的记录，也无法在每批插入前查询已存在的唯一键以区分新建和跳过 / 更新
+func (r *BaseRepository[T]) CreateInChunks(ctx context.Context, entities []*T, opts BatchCreateOptions) ([]BatchCreateItem, error) {
+	items := make([]BatchCreateItem, len(entities))
+	if len(entities) == 0 {
+		return items, nil
+	}
+	if opts.OnConflict == "" {
+		opts.OnConflict = ConflictFail
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = 100
+	}
+
+	// skip / update 策略需要按唯一键识别已存在和请求内重复的记录
+	var sch *schema.Schema
+	var keyFields []*schema.Field
+	if opts.OnConflict != ConflictFail {
+		var err error
+		if sch, err = r.schema(); err != nil {
+			return nil, err
+		}
+		if keyFields, err = lookupFields(sch, opts.ConflictColumns); err != nil {
+			return nil, err
+		}
+	}
+
+	pending := make([]int, 0, len(entities))
+	seen := make(map[string]bool, len(entities))
+	for i, entity := range entities {
+		if keyFields != nil {
+			key := entityKey(ctx, keyFields, entity)
+			if seen[key] {
+				if opts.OnConflict == ConflictSkip {
+					items[i].Status = BatchSkipped
+				} else {
+					items[i] = BatchCreateItem{Status: BatchFailed, Err: utils.NewBusinessError(utils.ErrCodeDuplicateKey, "error.duplicate_in_request")}
+				}
+				continue
+			}
+			seen[key] = true
+		}
+		pending = append(pending, i)
+	}
+
+	conflict, err := r.conflictClause(sch, opts)
+	if err != nil {
+		return nil, err
+	}
+	for start := 0; start < len(pending); start += opts.ChunkSize {
+		chunk := pending[start:min(start+opts.ChunkSize, len(pending))]
+		err := r.DB(ctx).Transaction(func(tx *gorm.DB) error {
+			return r.createChunk(database.ContextWithTx(ctx, tx), entities, chunk, keyFields, opts.OnConflict, conflict, items)
+		})
+		if err != nil {
+			return nil, r.TranslateError(err, "batch_create", nil)
+		}
+	}
+
+	// ON CONFLICT 时数据库返回的自增主键不可靠，按唯一键回查主键
+	if keyFields != nil {
+		if err := r.backfillPrimaryKeys(ctx, sch, keyFields, entities, items, opts.ChunkSize); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// Upsert 按唯一键创建或更新记录：不存在时创建，已存在时以 entity 的值更新 updateColumns（版本号自增），已软删除的记录同时恢复
+// 基于 ON CONFLICT 实现，兼容 MySQL、PostgreSQL、SQLite；返回是否为新建，主键回填到 entity 中
+func (r *BaseRepository[T]) Upsert(ctx context.Context, entity *T, conflictColumns, updateColumns []string) (bool, error) {
+	items, err := r.CreateInChunks(ctx, []*T{entity}, BatchCreateOptions{
+		ChunkSize:       1,
+		OnConflict:      ConflictUpdate,
+		ConflictColumns: conflictColumns,
+		UpdateColumns:   updateColumns,
+	})
+	if err != nil {
+		return false, err
+	}
+	if items[0].Status == BatchFailed {
+		return false, items[0].Err
+	}
+	return items[0].Status == BatchCreated, nil
+}
+
+// createChunk 创建一批记录，需在事务中调用
+//   - 插入前按唯一键查询并锁定（SELECT ... FOR UPDATE）已存在的记录，预判每条记录是新建还是跳过 / 更新
+//   - 处理结果以写入本身为准：整批写入的影响行数与预判不一致时（如其他事务在查询后插入了相同的唯一键），
+//     回滚整批写入并逐条插入，按每条的影响行数确定结果
+func (r *BaseRepository[T]) createChunk(ctx context.Context, entities []*T, chunk []int, keyFields []*schema.Field, strategy ConflictStrategy, conflict []clause.Expression, items []BatchCreateItem) error {
+	var existing map[string]any
+	if keyFields != nil {
+		var err error
+		if existing, err = r.lookupByKeys(ctx, keyFields, entities, chunk, true); err != nil {
+			return err
+		}
+	}
+	predicted := func(i int) string {
+		if keyFields == nil {
+			return BatchCreated
+		}
+		if _, ok := existing[entityKey(ctx, keyFields, entities[i])]; !ok {
+			return BatchCreated
+		}
+		if strategy == ConflictSkip {
+			return BatchSkipped
+		}
+		return BatchUpdated
+	}
+
+	db := r.DB(ctx)
+	isMySQL := db.Dialector.Name() == "mysql"
+	rows := make([]*T, 0, len(chunk))
+	var expected int64
+	for _, i := range chunk {
+		rows = append(rows, entities[i])
+		expected += affectedRows(predicted(i), isMySQL)
+	}
+	// 整批插入，失败或影响行数与预判不一致时回滚到保存点后逐条插入
+	err := db.Transaction(func(sp *gorm.DB) error {
+		result := sp.Clauses(conflict...).Create(rows)
+		if result.Error == nil && result.RowsAffected != expected {
+			return errChunkMismatch
+		}
+		return result.Error
+	})
+	if err == nil {
+		for _, i := range chunk {
+			items[i].Status = predicted(i)
+		}
+		return nil
+	}
+
+	for _, i := range chunk {
+		var affected int64
+		err := db.Transaction(func(sp *gorm.DB) error {
+			result := sp.Clauses(conflict...).Create(entities[i])
+			affected = result.RowsAffected
+			return result.Error
+		})
+		if err != nil {
+			items[i] = BatchCreateItem{Status: BatchFailed, Err: r.TranslateError(err, "batch_create", nil)}
+			continue
+		}
+		items[i].Status = writtenStatus(strategy, predicted(i), affected, isMySQL)
+	}
+	return nil
+}
+
+// errChunkMismatch 整批写入的影响行数与预判不一致，需逐条插入确定每条记录的结果
+var errChunkMismatch = errors.New("批量写入的影响行数与预判不一致")
+
+// affectedRows 记录按预判结果写入时数据库返回的影响行数：新建为 1，跳过为 0；
+// 更新在 MySQL 中为 2（ON DUPLICATE KEY UPDATE 的约定），其他数据库为 1
+func affectedRows(status string, isMySQL bool) int64 {
+	switch {
+	case status == BatchSkipped:
+		return 0
+	case status == BatchUpdated && isMySQL:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// writtenStatus 按单条记录写入的影响行数确定处理结果
+func writtenStatus(strategy ConflictStrategy, predicted string, affected int64, isMySQL bool) string {
+	switch {
+	case strategy == ConflictFail:
+		return BatchCreated
+	case strategy == ConflictSkip:
+		if affected == 0 {
+			return BatchSkipped
+		}
+		return BatchCreated
+	case isMySQL:
+		// 1 为新建，2 为更新，0 为已存在且取值未变化
+		if affected == 1 {
+			return BatchCreated
+		}
+		return BatchUpdated
+	default:
+		// 其他数据库的 upsert 新建和更新均返回 1，沿用插入前锁定查询的结果
+		return predicted
+	}
+}
+
+// conflictClause 按冲突策略构建 ON CONFLICT 子句
+func (r *BaseRepository[T]) conflictClause(sch *schema.Schema, opts BatchCreateOptions) ([]clause.Expression, error) {
+	columns := make([]clause.Column, 0, len(opts.ConflictColumns)+1)
+	for _, column := range opts.ConflictColumns {
+		columns = append(columns, clause.Column{Name: column})
+	}
+	// 模型包含租户字段时唯一键在租户内唯一（唯一索引包含 tenant_id），ON CONFLICT 的目标列需与唯一索引一致；
+	// 查询已存在的记录时租户条件由租户插件追加
+	if sch != nil && sch.LookUpField(tenant.Column) != nil && !slices.Contains(opts.ConflictColumns, tenant.Column) {
+		columns = append(columns, clause.Column{Name: tenant.Column})
+	}
+
+	switch opts.OnConflict {
+	case ConflictFail:
+		return nil, nil
+	case ConflictSkip:
+		return []clause.Expression{clause.OnConflict{Columns: columns, DoNothing: true}}, nil
+	case ConflictUpdate:
+		if len(opts.UpdateColumns) == 0 {
+			return nil, utils.NewSystemError(fmt.Errorf("实体 %s 的 update 冲突策略未指定更新列", r.entity))
+		}
+		// 以新值覆盖指定列、自动更新时间列和更新人列，版本号在原值基础上自增
+		updates := append([]string{}, opts.UpdateColumns...)
+		for _, field := range sch.Fields {
+			if field.AutoUpdateTime > 0 && field.DBName != "" {
+				updates = append(updates, field.DBName)
+			}
+		}
+		if field := database.UpdatedByField(sch); field != nil {
+			updates = append(updates, field.DBName)
+		}
+		set := clause.AssignmentColumns(updates)
+		// 唯一键命中已软删除的记录时恢复该记录，否则更新后的记录仍不可见
+		if database.SoftDeleteField(sch) != nil {
+			restore := database.RestoreUpdates()
+			restoreColumns := make([]string, 0, len(restore))
+			for column := range restore {
+				if sch.LookUpField(column) != nil {
+					restoreColumns = append(restoreColumns, column)
+				}
+			}
+			sort.Strings(restoreColumns)
+			for _, column := range restoreColumns {
+				set = append(set, clause.Assignment{Column: clause.Column{Name: column}, Value: restore[column]})
+			}
+		}
+		if r.versionColumn != "" {
+			set = append(set, clause.Assignment{
+				Column: clause.Column{Name: r.versionColumn},
+				Value:  gorm.Expr("? + 1", clause.Column{Table: sch.Table, Name: r.versionColumn}),
+			})
+		}
+		return []clause.Expression{clause.OnConflict{Columns: columns, DoUpdates: set}}, nil
+	default:
+		return nil, utils.NewSystemError(fmt.Errorf("不支持的冲突处理策略: %s", opts.OnConflict))
+	}
+}
+
+// lookupByKeys 按唯一键查询已存在的记录（含已软删除的记录，它们同样占用唯一索引），返回唯一键 -> 主键
+// lock 为 true 时以 SELECT ... FOR UPDATE 锁定查到的记录，需在事务中调用
+func (r *BaseRepository[T]) lookupByKeys(ctx context.Context, keyFields []*schema.Field, entities []*T, indexes []int, lock bool) (map[string]any, error) {
+	sch, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	primary := sch.PrioritizedPrimaryField
+	if primary == nil {
+		return nil, utils.NewSystemError(fmt.Errorf("模型 %s 没有主键", sch.Name))
+	}
+
+	conditions := make([]clause.Expression, 0, len(indexes))
+	for _, i := range indexes {
+		rv := reflect.ValueOf(entities[i]).Elem()
+		equals := make([]clause.Expression, 0, len(keyFields))
+		for _, field := range keyFields {
+			value, _ := field.ValueOf(ctx, rv)
+			equals = append(equals, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value})
+		}
+		conditions = append(conditions, clause.And(equals...))
+	}
+
+	columns := []string{primary.DBName}
+	for _, field := range keyFields {
+		columns = append(columns, field.DBName)
+	}
+	db := r.DB(ctx).Scopes(database.WithDeleted).Select(columns).Where(clause.Or(conditions...))
+	if lock {
+		db = db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})
+	}
+	var found []*T
+	if err := db.Find(&found).Error; err != nil {
+		return nil, r.TranslateError(err, "lookup_by_keys", nil)
+	}
+
+	keys := make(map[string]any, len(found))
+	for _, entity := range found {
+		id, _ := primary.ValueOf(ctx, reflect.ValueOf(entity).Elem())
+		keys[entityKey(ctx, keyFields, entity)] = id
+	}
+	return keys, nil
+}
+
+// backfillPrimaryKeys 按唯一键回查未失败记录的主键并写回 entities
+func (r *BaseRepository[T]) backfillPrimaryKeys(ctx context.Context, sch *schema.Schema, keyFields []*schema.Field, entities []*T, items []BatchCreateItem, chunkSize int) error {
+	indexes := make([]int, 0, len(entities))
+	for i, item := range items {
+		if item.Status != BatchFailed {
+			indexes = append(indexes, i)
+		}
+	}
+
+	for start := 0; start < len(indexes); start += chunkSize {
+		chunk := indexes[start:min(start+chunkSize, len(indexes))]
+		keys, err := r.lookupByKeys(ctx, keyFields, entities, chunk, false)
+		if err != nil {
+			return err
+		}
+		for _, i := range chunk {
+			if id, ok := keys[entityKey(ctx, keyFields, entities[i])]; ok {
+				if err := sch.PrioritizedPrimaryField.Set(ctx, reflect.ValueOf(entities[i]).Elem(), id); err != nil {
+					return utils.NewSystemError(fmt.Errorf("回填实体 %s 的主键失败: %w", r.entity, err))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// lookupFields 按列名查找模型字段
+func lookupFields(sch *schema.Schema, columns []string) ([]*schema.Field, error) {
+	if len(columns) == 0 {
+		return nil, utils.NewSystemError(fmt.Errorf("模型 %s 未指定唯一键列", sch.Name))
+	}
+	fields := make([]*schema.Field, 0, len(columns))
+	for _, column := range columns {
+		field := sch.LookUpField(column)
+		if field == nil {
+			return nil, utils.NewSystemError(fmt.Errorf("模型 %s 不存在字段 %s", sch.Name, column))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// entityKey 记录的唯一键取值拼接成的字符串
+func entityKey[T any](ctx context.Context, fields []*schema.Field, entity *T) string {
+	rv := reflect.ValueOf(entity).Elem()
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		value, _ := field.ValueOf(ctx, rv)
+		// 指针字段按指向的值比较
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && !v.IsNil() {
+			value = v.Elem().Interface()
+		}
+		parts = append(parts, fmt.Sprint(value))
+	}
+	return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"gin-template/internal/utils"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// batchRecord 批量创建测试模型，code 为唯一键，支持软删除和乐观锁
+type batchRecord struct {
+	ID        int
+	Code      string `gorm:"uniqueIndex"`
+	Name      string
+	Version   int
+	IsDeleted string `gorm:"default:'N'"`
+}
+
+// openBatchRepository 创建批量创建测试仓库，写入已存在的记录 a 和已软删除的记录 d
+func openBatchRepository(t *testing.T) (*BaseRepository[batchRecord], *gorm.DB) {
+	t.Helper()
+	db := openTestDB(t, &batchRecord{})
+	existing := []batchRecord{{ID: 10, Code: "a", Name: "old", Version: 1, IsDeleted: "N"}, {ID: 20, Code: "d", Name: "old", Version: 1, IsDeleted: "Y"}}
+	if err := db.Create(&existing).Error; err != nil {
+		t.Fatal(err)
+	}
+	return NewBaseRepository[batchRecord](db, "batch_record").WithVersion("version"), db
+}
+
+// newBatch 按唯一键创建待写入的记录
+func newBatch(codes ...string) []*batchRecord {
+	entities := make([]*batchRecord, 0, len(codes))
+	for _, code := range codes {
+		entities = append(entities, &batchRecord{Code: code, Name: "new", Version: 1})
+	}
+	return entities
+}
+
+// statuses 返回各条记录的处理结果
+func statuses(items []BatchCreateItem) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.Status)
+	}
+	return result
+}
+
+// findRecord 按唯一键查询记录（含已软删除的记录）
+func findRecord(t *testing.T, db *gorm.DB, code string) batchRecord {
+	t.Helper()
+	var record batchRecord
+	if err := db.Where("code = ?", code).First(&record).Error; err != nil {
+		t.Fatal(err)
+	}
+	return record
+}
+
+func TestCreateInChunks(t *testing.T) {
+	tests := []struct {
+		name  string
+		codes []string
+		opts  BatchCreateOptions
+		want  []string
+	}{
+		{
+			name:  "fail 策略：冲突的记录失败，同批其他记录创建",
+			codes: []string{"b", "a", "c"},
+			opts:  BatchCreateOptions{ChunkSize: 2},
+			want:  []string{BatchCreated, BatchFailed, BatchCreated},
+		},
+		{
+			name:  "skip 策略：跳过已存在和请求内重复的记录",
+			codes: []string{"b", "a", "b", "d"},
+			opts:  BatchCreateOptions{OnConflict: ConflictSkip, ConflictColumns: []string{"code"}},
+			want:  []string{BatchCreated, BatchSkipped, BatchSkipped, BatchSkipped},
+		},
+		{
+			name:  "update 策略：更新已存在的记录，请求内重复的记录失败",
+			codes: []string{"a", "b", "b"},
+			opts:  BatchCreateOptions{OnConflict: ConflictUpdate, ConflictColumns: []string{"code"}, UpdateColumns: []string{"name"}},
+			want:  []string{BatchUpdated, BatchCreated, BatchFailed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, db := openBatchRepository(t)
+			entities := newBatch(tt.codes...)
+			items, err := repo.CreateInChunks(context.Background(), entities, tt.opts)
+			if err != nil {
+				t.Fatalf("CreateInChunks() error = %v", err)
+			}
+			if got := statuses(items); !slices.Equal(got, tt.want) {
+				t.Fatalf("结果 = %v, want %v", got, tt.want)
+			}
+			for i, item := range items {
+				if (item.Status == BatchFailed) != (item.Err != nil) {
+					t.Errorf("第 %d 条 Status = %s, Err = %v", i, item.Status, item.Err)
+				}
+				// 新建和命中的记录均回填主键
+				if item.Status != BatchFailed && entities[i].ID != findRecord(t, db, entities[i].Code).ID {
+					t.Errorf("第 %d 条主键 = %d, 未回填", i, entities[i].ID)
+				}
+			}
+		})
+	}
+
+	t.Run("请求内重复的记录为重复键业务异常", func(t *testing.T) {
+		repo, _ := openBatchRepository(t)
+		items, err := repo.CreateInChunks(context.Background(), newBatch("b", "b"), BatchCreateOptions{OnConflict: ConflictUpdate, ConflictColumns: []string{"code"}, UpdateColumns: []string{"name"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bizErr, ok := utils.GetBusinessError(items[1].Err); !ok || bizErr.Code != utils.ErrCodeDuplicateKey {
+			t.Errorf("Err = %v, want 重复键业务异常", items[1].Err)
+		}
+	})
+
+	t.Run("update 策略更新指定列、版本号自增并恢复已软删除的记录", func(t *testing.T) {
+		repo, db := openBatchRepository(t)
+		_, err := repo.CreateInChunks(context.Background(), newBatch("a", "d"), BatchCreateOptions{OnConflict: ConflictUpdate, ConflictColumns: []string{"code"}, UpdateColumns: []string{"name"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, code := range []string{"a", "d"} {
+			if record := findRecord(t, db, code); record.Name != "new" || record.Version != 2 || record.IsDeleted != "N" {
+				t.Errorf("记录 %s = %+v", code, record)
+			}
+		}
+	})
+
+	t.Run("update 策略未指定更新列", func(t *testing.T) {
+		repo, _ := openBatchRepository(t)
+		if _, err := repo.CreateInChunks(context.Background(), newBatch("b"), BatchCreateOptions{OnConflict: ConflictUpdate, ConflictColumns: []string{"code"}}); err == nil {
+			t.Error("CreateInChunks() error = nil")
+		}
+	})
+}
+
+func TestCreateInChunksConcurrentInsert(t *testing.T) {
+	repo, db := openBatchRepository(t)
+
+	// 模拟并发写入：锁定查询之后、批量插入之前，其他事务插入了相同的唯一键
+	inserted := false
+	err := db.Callback().Query().After("gorm:query").Register("test:concurrent_insert", func(tx *gorm.DB) {
+		if _, locking := tx.Statement.Clauses["FOR"]; !locking || inserted {
+			return
+		}
+		inserted = true
+		if err := tx.Session(&gorm.Session{NewDB: true}).Exec("INSERT INTO batch_records (code, name, version, is_deleted) VALUES ('c', 'other', 1, 'N')").Error; err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := repo.CreateInChunks(context.Background(), newBatch("b", "c"), BatchCreateOptions{OnConflict: ConflictSkip, ConflictColumns: []string{"code"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !inserted {
+		t.Fatal("插入前未执行锁定查询")
+	}
+	if got, want := statuses(items), []string{BatchCreated, BatchSkipped}; !slices.Equal(got, want) {
+		t.Errorf("结果 = %v, want %v", got, want)
+	}
+	if record := findRecord(t, db, "c"); record.Name != "other" {
+		t.Errorf("记录 c = %+v, 不应被覆盖", record)
+	}
+}
+
+func TestWrittenStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  ConflictStrategy
+		predicted string
+		affected  int64
+		isMySQL   bool
+		want      string
+	}{
+		{"skip 写入一行为新建", ConflictSkip, BatchSkipped, 1, false, BatchCreated},
+		{"skip 未写入为跳过", ConflictSkip, BatchCreated, 0, false, BatchSkipped},
+		{"MySQL update 一行为新建", ConflictUpdate, BatchUpdated, 1, true, BatchCreated},
+		{"MySQL update 两行为更新", ConflictUpdate, BatchCreated, 2, true, BatchUpdated},
+		{"MySQL update 取值未变化", ConflictUpdate, BatchCreated, 0, true, BatchUpdated},
+		{"其他数据库 update 沿用预判", ConflictUpdate, BatchUpdated, 1, false, BatchUpdated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := writtenStatus(tt.strategy, tt.predicted, tt.affected, tt.isMySQL); got != tt.want {
+				t.Errorf("writtenStatus() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"gin-template/internal/app/query"
+	"gin-template/internal/app/tenant"
+	"gin-template/internal/utils"
+	"reflect"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// CountMode 游标分页的总条数统计方式
+type CountMode string
+
+const (
+	CountNone     CountMode = "none"     // 不统计（默认）
+	CountExact    CountMode = "exact"    // COUNT(*) 精确统计，受过滤条件影响
+	CountEstimate CountMode = "estimate" // 按表统计信息估算全表行数，不考虑过滤条件；数据库不支持或存在租户上下文时回退为精确统计
+)
+
+// CursorQuery 游标分页查询参数
+type CursorQuery struct {
+	Cursor string       // 上一次结果返回的 Next / Prev 游标，为空查询第一页
+	Limit  int          // 每页条数
+	Sorts  []query.Sort // 排序字段，未包含主键时自动追加主键作为唯一排序（方向同最后一个排序字段）
+	Count  CountMode    // 总条数统计方式
+}
+
+// CursorPage 游标分页结果
+type CursorPage[T any] struct {
+	List      []*T
+	Next      string // 下一页游标，没有下一页时为空
+	Prev      string // 上一页游标，没有上一页时为空
+	Total     *int64 // 总条数，CountNone 时为 nil
+	Estimated bool   // Total 是否为估算值
+}
+
+// WithCursorCodec 设置游标编解码器，启用 FindCursor 游标分页
+func (r *BaseRepository[T]) WithCursorCodec(codec *query.CursorCodec) *BaseRepository[T] {
+	r.cursorCodec = codec
+	return r
+}
+
+// FindCursor 按排序键（keyset）游标分页查询，不使用 OFFSET，翻页性能与页码无关
+// scopes 只应包含过滤条件，排序通过 CursorQuery.Sorts 指定；排序字段应为非空列
+func (r *BaseRepository[T]) FindCursor(ctx context.Context, q CursorQuery, scopes ...Scope) (*CursorPage[T], error) {
+	if r.cursorCodec == nil {
+		return nil, utils.NewSystemError(fmt.Errorf("实体 %s 未配置游标编解码器", r.entity))
+	}
+	if q.Limit < 1 {
+		q.Limit = 10
+	}
+
+	sch, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	sorts, fields, err := cursorSorts(sch, q.Sorts)
+	if err != nil {
+		return nil, err
+	}
+	signature := query.SortSignature(sorts)
+
+	db := r.DB(ctx).Scopes(scopes...)
+	backward := false
+	if q.Cursor != "" {
+		cursor, err := r.cursorCodec.Decode(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// 游标必须来自排序相同的查询
+		if cursor.Sort != signature || len(cursor.Values) != len(sorts) {
+			return nil, utils.NewBusinessError(utils.ErrCodeParamInvalid, "query.invalid_cursor")
+		}
+		values, err := decodeCursorValues(fields, cursor.Values)
+		if err != nil {
+			return nil, utils.NewBusinessError(utils.ErrCodeParamInvalid, "query.invalid_cursor")
+		}
+		backward = cursor.Backward
+		db = db.Where(keysetCondition(sorts, values, backward))
+	}
+
+	// 多查一条判断是否还有更多数据；向前翻页时按相反方向查询，再恢复为正常顺序
+	var list []*T
+	if err := db.Order(cursorOrder(sorts, backward)).Limit(q.Limit + 1).Find(&list).Error; err != nil {
+		return nil, r.TranslateError(err, "find_cursor", nil)
+	}
+	hasMore := len(list) > q.Limit
+	if hasMore {
+		list = list[:q.Limit]
+	}
+	if backward {
+		slices.Reverse(list)
+	}
+
+	page := &CursorPage[T]{List: list}
+	if len(list) > 0 {
+		// 向后翻页：还有更多数据时存在下一页，带游标（非第一页）时存在上一页；向前翻页反之
+		hasNext, hasPrev := hasMore, q.Cursor != ""
+		if backward {
+			hasNext, hasPrev = true, hasMore
+		}
+		if hasNext {
+			if page.Next, err = r.encodeCursor(ctx, signature, fields, list[len(list)-1], false); err != nil {
+				return nil, err
+			}
+		}
+		if hasPrev {
+			if page.Prev, err = r.encodeCursor(ctx, signature, fields, list[0], true); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if page.Total, page.Estimated, err = r.cursorTotal(ctx, sch.Table, q.Count, scopes); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// schema 解析数据模型的 GORM schema
+func (r *BaseRepository[T]) schema() (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, utils.NewSystemError(fmt.Errorf("解析实体 %s 的模型失败: %w", r.entity, err))
+	}
+	return stmt.Schema, nil
+}
+
+// cursorSorts 补全主键排序并查找各排序字段的模型字段
+func cursorSorts(sch *schema.Schema, sorts []query.Sort) ([]query.Sort, []*schema.Field, error) {
+	primary := sch.PrioritizedPrimaryField
+	if primary == nil {
+		return nil, nil, utils.NewSystemError(fmt.Errorf("模型 %s 没有主键，不支持游标分页", sch.Name))
+	}
+
+	result := slices.Clone(sorts)
+	if !slices.ContainsFunc(result, func(sort query.Sort) bool { return sort.Column == primary.DBName }) {
+		desc := len(result) > 0 && result[len(result)-1].Desc
+		result = append(result, query.Sort{Field: primary.DBName, Column: primary.DBName, Desc: desc})
+	}
+
+	fields := make([]*schema.Field, 0, len(result))
+	for _, sort := range result {
+		field := sch.LookUpField(sort.Column)
+		if field == nil {
+			return nil, nil, utils.NewSystemError(fmt.Errorf("模型 %s 不存在排序字段 %s", sch.Name, sort.Column))
+		}
+		fields = append(fields, field)
+	}
+	return result, fields, nil
+}
+
+// keysetCondition 构建排序键比较条件：(k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+// 升序字段取更大的值、降序字段取更小的值，向前翻页时相反
+func keysetCondition(sorts []query.Sort, values []any, backward bool) clause.Expression {
+	ors := make([]clause.Expression, 0, len(sorts))
+	for i, sort := range sorts {
+		ands := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, clause.Eq{Column: cursorColumn(sorts[j]), Value: values[j]})
+		}
+		if sort.Desc != backward {
+			ands = append(ands, clause.Lt{Column: cursorColumn(sort), Value: values[i]})
+		} else {
+			ands = append(ands, clause.Gt{Column: cursorColumn(sort), Value: values[i]})
+		}
+		ors = append(ors, clause.And(ands...))
+	}
+	return clause.Or(ors...)
+}
+
+// cursorOrder 构建排序子句，向前翻页时各字段排序方向取反
+func cursorOrder(sorts []query.Sort, backward bool) clause.OrderBy {
+	columns := make([]clause.OrderByColumn, 0, len(sorts))
+	for _, sort := range sorts {
+		columns = append(columns, clause.OrderByColumn{Column: cursorColumn(sort), Desc: sort.Desc != backward})
+	}
+	return clause.OrderBy{Columns: columns}
+}
+
+// cursorColumn 排序字段对应的列
+func cursorColumn(sort query.Sort) clause.Column {
+	return clause.Column{Table: clause.CurrentTable, Name: sort.Column}
+}
+
+// encodeCursor 以记录的排序键取值生成游标
+func (r *BaseRepository[T]) encodeCursor(ctx context.Context, signature string, fields []*schema.Field, entity *T, backward bool) (string, error) {
+	cursor := query.Cursor{Sort: signature, Backward: backward, Values: make([]json.RawMessage, 0, len(fields))}
+	rv := reflect.ValueOf(entity).Elem()
+	for _, field := range fields {
+		value, _ := field.ValueOf(ctx, rv)
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", utils.NewSystemError(fmt.Errorf("游标字段 %s 序列化失败: %w", field.DBName, err))
+		}
+		cursor.Values = append(cursor.Values, raw)
+	}
+
+	token, err := r.cursorCodec.Encode(cursor)
+	if err != nil {
+		return "", utils.NewSystemError(err)
+	}
+	return token, nil
+}
+
+// decodeCursorValues 按模型字段类型还原游标中的排序键取值
+func decodeCursorValues(fields []*schema.Field, raws []json.RawMessage) ([]any, error) {
+	values := make([]any, 0, len(fields))
+	for i, field := range fields {
+		ptr := reflect.New(field.FieldType)
+		if err := json.Unmarshal(raws[i], ptr.Interface()); err != nil {
+			return nil, err
+		}
+		values = append(values, ptr.Elem().Interface())
+	}
+	return values, nil
+}
+
+// cursorTotal 按统计方式计算总条数
+func (r *BaseRepository[T]) cursorTotal(ctx context.Context, table string, mode CountMode, scopes []Scope) (*int64, bool, error) {
+	switch mode {
+	case CountExact:
+		total, err := r.Count(ctx, scopes...)
+		return &total, false, err
+	case CountEstimate:
+		// 估算值来自全表统计信息，租户隔离场景下会包含其他租户的数据，此时回退为精确统计
+		if _, ok := tenant.FromContext(ctx); !ok {
+			if total, ok, err := r.estimateCount(ctx, table); err != nil || ok {
+				return &total, ok, err
+			}
+		}
+		total, err := r.Count(ctx, scopes...)
+		return &total, false, err
+	default:
+		return nil, false, nil
+	}
+}
+
+// estimateCount 读取 MySQL 的表统计信息估算行数，其他驱动（如单元测试中的 SQLite）返回 false
+func (r *BaseRepository[T]) estimateCount(ctx context.Context, table string) (int64, bool, error) {
+	db := r.DB(ctx)
+	if db.Dialector.Name() != "mysql" {
+		return 0, false, nil
+	}
+	var rows sql.NullInt64
+	err := db.Raw("SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?", table).Scan(&rows).Error
+	if err != nil {
+		return 0, false, r.TranslateError(err, "estimate_count", nil)
+	}
+	// 统计信息缺失（如表从未分析过）时视为不支持
+	if !rows.Valid || rows.Int64 < 0 {
+		return 0, false, nil
+	}
+	return rows.Int64, true, nil
+}
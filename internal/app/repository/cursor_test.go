@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"gin-template/internal/app/query"
+	"gin-template/internal/utils"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// item 测试模型
+type item struct {
+	ID    int
+	Name  string
+	Score int
+}
+
+// openTestDB 打开测试用的 SQLite 数据库（临时文件）并迁移模型
+func openTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// errorMessage 返回业务异常的消息键，非业务异常返回空字符串
+func errorMessage(err error) string {
+	if bizErr, ok := utils.GetBusinessError(err); ok {
+		return bizErr.Message
+	}
+	return ""
+}
+
+// ids 返回记录的主键
+func ids(list []*item) []int {
+	result := make([]int, 0, len(list))
+	for _, record := range list {
+		result = append(result, record.ID)
+	}
+	return result
+}
+
+// openCursorRepository 创建支持游标分页的仓库，写入 7 条分数有重复的记录
+func openCursorRepository(t *testing.T) *BaseRepository[item] {
+	t.Helper()
+	db := openTestDB(t, &item{})
+	scores := []int{3, 1, 2, 3, 1, 2, 3}
+	for i, score := range scores {
+		if err := db.Create(&item{ID: i + 1, Score: score}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewBaseRepository[item](db, "item").WithCursorCodec(query.NewCursorCodec("secret"))
+}
+
+func TestKeysetCondition(t *testing.T) {
+	db := openTestDB(t, &item{}).Session(&gorm.Session{DryRun: true})
+	sorts := []query.Sort{{Column: "score", Desc: true}, {Column: "id"}}
+	tests := []struct {
+		name     string
+		backward bool
+		want     string
+	}{
+		{"向后翻页", false, "SELECT * FROM `items` WHERE (`items`.`score` < ? OR (`items`.`score` = ? AND `items`.`id` > ?))"},
+		{"向前翻页比较方向取反", true, "SELECT * FROM `items` WHERE (`items`.`score` > ? OR (`items`.`score` = ? AND `items`.`id` < ?))"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := db.Where(keysetCondition(sorts, []any{3, 3}, tt.backward)).Find(&[]item{}).Statement
+			if got := stmt.SQL.String(); got != tt.want {
+				t.Errorf("SQL = %s, want %s", got, tt.want)
+			}
+			if want := []any{3, 3, 3}; !slices.Equal(stmt.Vars, want) {
+				t.Errorf("Vars = %v, want %v", stmt.Vars, want)
+			}
+		})
+	}
+}
+
+func TestFindCursorPaging(t *testing.T) {
+	tests := []struct {
+		name  string
+		sorts []query.Sort
+		want  [][]int
+	}{
+		{"默认按主键升序", nil, [][]int{{1, 2, 3}, {4, 5, 6}, {7}}},
+		{"按分数降序且主键同向补充", []query.Sort{{Column: "score", Desc: true}}, [][]int{{7, 4, 1}, {6, 3, 5}, {2}}},
+		{"分数升序主键降序", []query.Sort{{Column: "score"}, {Column: "id", Desc: true}}, [][]int{{5, 2, 6}, {3, 7, 4}, {1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := openCursorRepository(t)
+			ctx := context.Background()
+
+			// 向后翻到最后一页
+			var pages []*CursorPage[item]
+			cursor := ""
+			for {
+				page, err := repo.FindCursor(ctx, CursorQuery{Cursor: cursor, Limit: 3, Sorts: tt.sorts})
+				if err != nil {
+					t.Fatalf("FindCursor() error = %v", err)
+				}
+				pages = append(pages, page)
+				if page.Next == "" || len(pages) > len(tt.want) {
+					break
+				}
+				cursor = page.Next
+			}
+			if len(pages) != len(tt.want) {
+				t.Fatalf("页数 = %d, want %d", len(pages), len(tt.want))
+			}
+			for i, page := range pages {
+				if got := ids(page.List); !slices.Equal(got, tt.want[i]) {
+					t.Errorf("第 %d 页 = %v, want %v", i+1, got, tt.want[i])
+				}
+			}
+			if pages[0].Prev != "" {
+				t.Error("第一页 Prev 不为空")
+			}
+
+			// 从最后一页向前翻回第一页，每页顺序与向后翻页时一致
+			cursor = pages[len(pages)-1].Prev
+			for i := len(tt.want) - 2; i >= 0; i-- {
+				page, err := repo.FindCursor(ctx, CursorQuery{Cursor: cursor, Limit: 3, Sorts: tt.sorts})
+				if err != nil {
+					t.Fatalf("FindCursor() error = %v", err)
+				}
+				if got := ids(page.List); !slices.Equal(got, tt.want[i]) {
+					t.Errorf("向前翻页第 %d 页 = %v, want %v", i+1, got, tt.want[i])
+				}
+				if page.Next == "" {
+					t.Errorf("向前翻页第 %d 页 Next 为空", i+1)
+				}
+				if (page.Prev == "") != (i == 0) {
+					t.Errorf("向前翻页第 %d 页 Prev = %q", i+1, page.Prev)
+				}
+				cursor = page.Prev
+			}
+		})
+	}
+}
+
+func TestFindCursorOptions(t *testing.T) {
+	repo := openCursorRepository(t)
+	ctx := context.Background()
+
+	t.Run("精确统计总条数", func(t *testing.T) {
+		page, err := repo.FindCursor(ctx, CursorQuery{Limit: 2, Count: CountExact}, func(db *gorm.DB) *gorm.DB {
+			return db.Where("score = ?", 3)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total == nil || *page.Total != 3 || page.Estimated || !slices.Equal(ids(page.List), []int{1, 4}) {
+			t.Errorf("page = %+v", page)
+		}
+	})
+
+	t.Run("不支持估算时回退为精确统计", func(t *testing.T) {
+		page, err := repo.FindCursor(ctx, CursorQuery{Limit: 2, Count: CountEstimate})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total == nil || *page.Total != 7 || page.Estimated {
+			t.Errorf("Total = %v, Estimated = %v", page.Total, page.Estimated)
+		}
+	})
+
+	t.Run("游标与排序不一致", func(t *testing.T) {
+		page, err := repo.FindCursor(ctx, CursorQuery{Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = repo.FindCursor(ctx, CursorQuery{Cursor: page.Next, Limit: 2, Sorts: []query.Sort{{Column: "score"}}})
+		if got := errorMessage(err); got != "query.invalid_cursor" {
+			t.Errorf("error = %v, want query.invalid_cursor", err)
+		}
+	})
+
+	t.Run("篡改的游标", func(t *testing.T) {
+		_, err := repo.FindCursor(ctx, CursorQuery{Cursor: "e30.AAAA", Limit: 2})
+		if got := errorMessage(err); got != "query.invalid_cursor" {
+			t.Errorf("error = %v, want query.invalid_cursor", err)
+		}
+	})
+}
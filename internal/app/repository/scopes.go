@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Scope 查询作用域，与 GORM 的 Scopes 参数类型一致，用于组合可复用的查询条件
+type Scope = func(*gorm.DB) *gorm.DB
+
+// Eq 等值条件：column = value，字段名会被安全转义
+func Eq(column string, value any) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(clause.Eq{Column: clause.Column{Name: column}, Value: value})
+	}
+}
+
+// EqIfNotZero 等值条件，value 为零值时不追加条件，适用于可选的查询参数
+func EqIfNotZero[V comparable](column string, value V) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		var zero V
+		if value == zero {
+			return db
+		}
+		return db.Where(clause.Eq{Column: clause.Column{Name: column}, Value: value})
+	}
+}
+
+// In 范围条件：column IN (values...)
+func In[V any](column string, values []V) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		items := make([]any, 0, len(values))
+		for _, v := range values {
+			items = append(items, v)
+		}
+		return db.Where(clause.IN{Column: clause.Column{Name: column}, Values: items})
+	}
+}
+
+// Like 模糊匹配条件：column LIKE %keyword%，keyword 为空时不追加条件
+func Like(column, keyword string) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		if keyword == "" {
+			return db
+		}
+		return db.Where(clause.Like{Column: clause.Column{Name: column}, Value: "%" + keyword + "%"})
+	}
+}
+
+// ByID 主键等值条件
+func ByID(id any) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(clause.Eq{Column: clause.PrimaryColumn, Value: id})
+	}
+}
+
+// OrderBy 排序，desc 为 true 时降序
+func OrderBy(column string, desc bool) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc})
+	}
+}
+
+// Paginate 分页，page 从 1 开始
+func Paginate(page, pageSize int) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		if page < 1 {
+			page = 1
+		}
+		return db.Offset((page - 1) * pageSize).Limit(pageSize)
+	}
+}
+
+// ForUpdate 加行级排他锁（SELECT ... FOR UPDATE），需在事务中使用
+func ForUpdate(db *gorm.DB) *gorm.DB {
+	return db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})
+}
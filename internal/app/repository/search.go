@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"gin-template/internal/app/search"
+)
+
+// SearchHit 全文检索结果，Score 为相关度，数值越大越相关，不同检索实现的取值范围不同
+type SearchHit[T any] struct {
+	Entity *T
+	Score  float64
+}
+
+// searchRow 检索查询的扫描目标：数据表的全部列和相关度列
+type searchRow[T any] struct {
+	Entity T       `gorm:"embedded"`
+	Score  float64 `gorm:"column:search_score"`
+}
+
+// SearchPage 全文检索分页查询，按相关度降序返回当前页结果和匹配总数；scopes 为附加的过滤条件
+// q.Terms() 为空时由调用方处理（各检索实现不接受空条件）
+func (r *BaseRepository[T]) SearchPage(ctx context.Context, searcher search.Searcher, q search.Query, page, pageSize int, scopes ...Scope) ([]SearchHit[T], int64, error) {
+	query := r.Model(ctx).Scopes(scopes...).Scopes(searcher.Match(q))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, r.TranslateError(err, "search_count", nil)
+	}
+
+	var rows []searchRow[T]
+	if err := query.Scopes(searcher.Rank(q), Paginate(page, pageSize)).Scan(&rows).Error; err != nil {
+		return nil, 0, r.TranslateError(err, "search", nil)
+	}
+
+	hits := make([]SearchHit[T], len(rows))
+	for i := range rows {
+		hits[i] = SearchHit[T]{Entity: &rows[i].Entity, Score: rows[i].Score}
+	}
+	return hits, total, nil
+}
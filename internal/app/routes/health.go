@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"gin-template/internal/app/database"
+	"gin-template/internal/app/module"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// registerHealthRoutes 注册健康检查路由
+//   - /health/live 存活探针：进程能处理请求即返回 200，不检查依赖，避免数据库故障导致容器被反复重启
+//   - /health/ready 就绪探针：任一数据库连接未就绪或业务模块的检查项失败时返回 503，负载均衡据此暂停向本实例转发流量
+//   - /health/stats 各数据库连接的连接池统计，仅在调试模式下注册
+func registerHealthRoutes(router *gin.Engine, dbManager database.Manager, checks map[string]module.HealthCheck, debug bool) {
+	health := router.Group("/health")
+	{
+		health.GET("/live", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		})
+		health.GET("/ready", func(c *gin.Context) {
+			status := http.StatusOK
+			databases := make(map[string]string)
+			for name, err := range dbManager.Health(c.Request.Context()) {
+				if err != nil {
+					// 失败原因只记录日志，不返回给调用方
+					logrus.WithError(err).WithField("database", name).Warn("就绪检查失败")
+					databases[name] = "not_ready"
+					status = http.StatusServiceUnavailable
+					continue
+				}
+				databases[name] = "ok"
+			}
+
+			modules := make(map[string]string, len(checks))
+			for name, check := range checks {
+				if err := check(c.Request.Context()); err != nil {
+					logrus.WithError(err).WithField("check", name).Warn("就绪检查失败")
+					modules[name] = "not_ready"
+					status = http.StatusServiceUnavailable
+					continue
+				}
+				modules[name] = "ok"
+			}
+
+			if status != http.StatusOK {
+				c.JSON(status, gin.H{"status": "not_ready", "databases": databases, "modules": modules})
+				return
+			}
+			c.JSON(status, gin.H{"status": "ok", "databases": databases, "modules": modules})
+		})
+		if debug {
+			health.GET("/stats", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"databases": dbManager.Stats()})
+			})
+		}
+	}
+}
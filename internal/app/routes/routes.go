@@ -2,27 +2,24 @@ package routes
 
 import (
 	"gin-template/internal/app/config"
-
-	democtr "gin-template/internal/demo/controller"
-	demorepo "gin-template/internal/demo/repository"
-	demosvc "gin-template/internal/demo/service"
+	"gin-template/internal/app/database"
+	"gin-template/internal/app/middleware"
+	"gin-template/internal/app/module"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-// SetupRoutes 初始化依赖，注册路由
-func SetupRoutes(cfg *config.Config, router *gin.Engine, db *gorm.DB) {
-	// 初始化依赖
-	// 初始化仓库层
-	demoRepo := demorepo.NewDemoRepository(db)
-	// 初始化服务层
-	demoSvc := demosvc.NewDemoService(demoRepo)
-	// 初始化控制器层
-	demoController := democtr.NewDemoController(demoSvc)
+// SetupRoutes 注册基础路由（健康检查、测试路由），并在 /api 路由组下注册已初始化的各业务模块的路由
+func SetupRoutes(cfg *config.Config, router *gin.Engine, dbManager database.Manager, modules *module.Registry) {
+	// 健康检查路由，就绪检查包括各模块的检查项
+	registerHealthRoutes(router, dbManager, modules.HealthChecks(), cfg.App.Debug)
 
 	// 初始化路由
 	api := router.Group("/api")
+	// 启用多租户时，业务接口需先解析租户（健康检查等基础路由不受影响）
+	if cfg.Tenant.Enabled {
+		api.Use(middleware.Tenant(cfg.Tenant))
+	}
 	{
 		// 测试路由
 		api.GET("/test", func(c *gin.Context) {
@@ -30,17 +27,7 @@ func SetupRoutes(cfg *config.Config, router *gin.Engine, db *gorm.DB) {
 				"message": "测试",
 			})
 		})
-		// demo 模块路由
-		demo := api.Group("/demo")
-		{
-			demo.GET("", demoController.ListDemo)
-			demo.GET("/page", demoController.ListDemoPage)
-			demo.GET("/:id", demoController.GetDemoByID)
-			demo.POST("", demoController.CreateDemo)
-			demo.POST("/batch", demoController.BatchCreateDemo)
-			demo.PUT("/:id", demoController.UpdateDemo)
-			demo.DELETE("/soft/:id", demoController.SoftDeleteDemo)
-			demo.DELETE("/hard/:id", demoController.DeleteDemo)
-		}
+		// 业务模块路由
+		modules.RegisterRoutes(api)
 	}
 }
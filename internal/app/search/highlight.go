@@ -0,0 +1,85 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 高亮标记
+const (
+	HighlightPre  = "<mark>"
+	HighlightPost = "</mark>"
+)
+
+// highlight 标记文本中的检索词（不区分大小写），较长的词优先；其余部分按 HTML 转义，结果可直接作为 HTML 片段展示
+// wholeWord 为 true 时只标记从词首开始的匹配，且非 prefix 时须在词尾结束，与全文索引的分词匹配保持一致；
+// 中日韩文字没有空格分词，不受词边界限制
+func highlight(text string, terms []string, wholeWord, prefix bool) string {
+	if len(terms) == 0 {
+		return html.EscapeString(text)
+	}
+	terms = append([]string(nil), terms...)
+	sort.SliceStable(terms, func(i, j int) bool {
+		return utf8.RuneCountInString(terms[i]) > utf8.RuneCountInString(terms[j])
+	})
+
+	var b strings.Builder
+	start := 0 // 尚未写入的普通文本起始位置
+	for i := 0; i < len(text); {
+		n := 0
+		if !wholeWord || isBoundary(text, i) {
+			for _, term := range terms {
+				if n = hasPrefixFold(text[i:], term); n > 0 && wholeWord && !prefix && !isBoundary(text, i+n) {
+					n = 0
+				}
+				if n > 0 {
+					break
+				}
+			}
+		}
+		if n == 0 {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			i += size
+			continue
+		}
+		b.WriteString(html.EscapeString(text[start:i]))
+		b.WriteString(HighlightPre)
+		b.WriteString(html.EscapeString(text[i : i+n]))
+		b.WriteString(HighlightPost)
+		i += n
+		start = i
+	}
+	b.WriteString(html.EscapeString(text[start:]))
+	return b.String()
+}
+
+// hasPrefixFold 判断 s 是否以 prefix 开头（不区分大小写），返回 s 中匹配部分的字节长度，不匹配时返回 0
+func hasPrefixFold(s, prefix string) int {
+	n := 0
+	for _, want := range prefix {
+		got, size := utf8.DecodeRuneInString(s[n:])
+		if size == 0 || !strings.EqualFold(string(got), string(want)) {
+			return 0
+		}
+		n += size
+	}
+	return n
+}
+
+// isBoundary 判断位置 i 是否为词边界：两侧不都是字母数字，或任一侧为中日韩文字
+func isBoundary(text string, i int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:i])
+	after, _ := utf8.DecodeRuneInString(text[i:])
+	return !isWordRune(before) || !isWordRune(after) || isCJK(before) || isCJK(after)
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,44 @@
+package search
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		terms     []string
+		wholeWord bool
+		prefix    bool
+		want      string
+	}{
+		{name: "无检索词时只转义", text: "a<b", want: "a&lt;b"},
+		{name: "不区分大小写", text: "Hello world", terms: []string{"hello"}, want: "<mark>Hello</mark> world"},
+		{name: "非 ASCII 字符不区分大小写", text: "ÄBC", terms: []string{"äb"}, want: "<mark>ÄB</mark>C"},
+		{name: "较长的词优先", text: "foobar", terms: []string{"foo", "foobar"}, want: "<mark>foobar</mark>"},
+		{name: "多处匹配", text: "a b a", terms: []string{"a"}, want: "<mark>a</mark> b <mark>a</mark>"},
+		{name: "匹配内外均转义", text: "<x>&y", terms: []string{"&y"}, want: "&lt;x&gt;<mark>&amp;y</mark>"},
+		{name: "文本短于检索词", text: "ab", terms: []string{"abc"}, want: "ab"},
+		{name: "子串匹配", text: "database", terms: []string{"base"}, want: "data<mark>base</mark>"},
+		{name: "整词匹配忽略词中的子串", text: "database base", terms: []string{"base"}, wholeWord: true, want: "database <mark>base</mark>"},
+		{name: "整词匹配要求在词尾结束", text: "basement base", terms: []string{"base"}, wholeWord: true, want: "basement <mark>base</mark>"},
+		{name: "前缀匹配", text: "basement", terms: []string{"base"}, wholeWord: true, prefix: true, want: "<mark>base</mark>ment"},
+		{name: "中文不受词边界限制", text: "数据库管理", terms: []string{"库"}, wholeWord: true, want: "数据<mark>库</mark>管理"},
+		{name: "中文与字母相邻", text: "abc数据", terms: []string{"数据"}, wholeWord: true, want: "abc<mark>数据</mark>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight(tt.text, tt.terms, tt.wholeWord, tt.prefix); got != tt.want {
+				t.Errorf("highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightKeepsTermOrder(t *testing.T) {
+	terms := []string{"a", "abc"}
+	highlight("abc", terms, false, false)
+	if terms[0] != "a" || terms[1] != "abc" {
+		t.Errorf("highlight() 修改了调用方的 terms: %v", terms)
+	}
+}
@@ -0,0 +1,65 @@
+package search
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// likeSearcher 未建立全文索引时的回退实现：每个词都须出现在任一检索列中（不区分大小写的包含匹配），无法使用索引，只适合数据量较小的表
+// 相关度：检索列与关键词完全相同为 3，以关键词开头为 2，其他为 1（多列时取各列之和）
+type likeSearcher struct {
+	index Index
+}
+
+// Name 检索方式
+func (s likeSearcher) Name() string {
+	return "like"
+}
+
+// Match 每个词一个条件，条件之间为 AND，同一个词在各列之间为 OR
+func (s likeSearcher) Match(q Query) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		columns := s.lowerColumns(db)
+		for _, term := range q.Terms() {
+			// 词中只有字母和数字，不含 LIKE 通配符，无需转义
+			pattern := "%" + strings.ToLower(term) + "%"
+			conditions := make([]string, len(columns))
+			vars := make([]any, len(columns))
+			for i, column := range columns {
+				conditions[i], vars[i] = column+" LIKE ?", pattern
+			}
+			db = db.Where("("+strings.Join(conditions, " OR ")+")", vars...)
+		}
+		return db
+	}
+}
+
+// Rank 按与关键词的接近程度计算相关度
+func (s likeSearcher) Rank(q Query) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		keyword := strings.ToLower(strings.Join(q.Terms(), " "))
+		columns := s.lowerColumns(db)
+		cases := make([]string, len(columns))
+		vars := make([]any, 0, 2*len(columns))
+		for i, column := range columns {
+			cases[i] = "CASE WHEN " + column + " = ? THEN 3 WHEN " + column + " LIKE ? THEN 2 ELSE 1 END"
+			vars = append(vars, keyword, keyword+"%")
+		}
+		return s.index.orderByScore(s.index.selectAll(db, strings.Join(cases, " + "), vars...))
+	}
+}
+
+// Highlight 标记检索词，与匹配条件一致按包含关系标记
+func (s likeSearcher) Highlight(text string, q Query) string {
+	return highlight(text, q.Terms(), false, false)
+}
+
+// lowerColumns 返回转为小写的带表名检索列
+func (s likeSearcher) lowerColumns(db *gorm.DB) []string {
+	columns := make([]string, len(s.index.Columns))
+	for i, column := range s.index.Columns {
+		columns[i] = "LOWER(" + db.Statement.Quote(s.index.column(column)) + ")"
+	}
+	return columns
+}
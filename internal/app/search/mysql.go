@@ -0,0 +1,71 @@
+package search
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// mysqlSearcher MySQL FULLTEXT 检索，使用布尔模式：每个词都必须出现（+词），前缀匹配时为 +词*
+// 默认分词器按空格和标点分词，中文等无空格的文本需使用 ngram 分词器建立索引（WITH PARSER ngram）
+type mysqlSearcher struct {
+	index Index
+}
+
+// detectMySQL 检测是否存在恰好包含检索列的 FULLTEXT 索引，不存在时返回 nil
+func detectMySQL(db *gorm.DB, idx Index) (Searcher, error) {
+	var names []string
+	err := db.Raw(`SELECT INDEX_NAME FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_TYPE = 'FULLTEXT'
+		GROUP BY INDEX_NAME HAVING COUNT(*) = ? AND SUM(COLUMN_NAME IN ?) = ?`,
+		idx.Table, len(idx.Columns), idx.Columns, len(idx.Columns)).Scan(&names).Error
+	if err != nil || len(names) == 0 {
+		return nil, err
+	}
+	return mysqlSearcher{index: idx}, nil
+}
+
+// Name 检索方式
+func (s mysqlSearcher) Name() string {
+	return "fulltext"
+}
+
+// Match 匹配条件
+func (s mysqlSearcher) Match(q Query) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(s.against(db), s.booleanQuery(q))
+	}
+}
+
+// Rank 相关度为 MATCH ... AGAINST 的返回值
+func (s mysqlSearcher) Rank(q Query) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return s.index.orderByScore(s.index.selectAll(db, s.against(db), s.booleanQuery(q)))
+	}
+}
+
+// Highlight 标记检索词
+func (s mysqlSearcher) Highlight(text string, q Query) string {
+	return highlight(text, q.Terms(), true, q.Prefix)
+}
+
+// against 构建 MATCH (列...) AGAINST (? IN BOOLEAN MODE) 表达式
+func (s mysqlSearcher) against(db *gorm.DB) string {
+	columns := make([]string, len(s.index.Columns))
+	for i, column := range s.index.Columns {
+		columns[i] = db.Statement.Quote(s.index.column(column))
+	}
+	return "MATCH (" + strings.Join(columns, ", ") + ") AGAINST (? IN BOOLEAN MODE)"
+}
+
+// booleanQuery 构建布尔模式查询串，词中只有字母和数字，不会与布尔模式的运算符冲突
+func (s mysqlSearcher) booleanQuery(q Query) string {
+	terms := q.Terms()
+	for i, term := range terms {
+		terms[i] = "+" + term
+		if q.Prefix {
+			terms[i] += "*"
+		}
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,123 @@
+// Package search 全文检索：使用 MySQL FULLTEXT 索引，未建立索引时回退为 LIKE 匹配。
+// 各实现提供相同的匹配条件和相关度排序，高亮在应用层完成，结果一致。
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScoreColumn 相关度在查询结果中的列名，数值越大越相关
+const ScoreColumn = "search_score"
+
+// Scope 查询作用域，与 GORM 的 Scopes 参数类型一致
+type Scope = func(*gorm.DB) *gorm.DB
+
+// Query 检索条件
+type Query struct {
+	Keyword string // 关键词，按空白和标点切分为多个词，须全部匹配
+	Prefix  bool   // 前缀匹配：每个词匹配以其开头的词（如 "data" 匹配 "database"）
+}
+
+// Terms 从关键词中切分出的词（去重，保持顺序），只保留字母和数字，各实现的查询语法字符不会出现在词中
+func (q Query) Terms() []string {
+	words := strings.FieldsFunc(q.Keyword, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		key := strings.ToLower(word)
+		if !seen[key] {
+			seen[key] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// Searcher 全文检索实现接口
+type Searcher interface {
+	// Name 检索方式：fulltext（MySQL FULLTEXT 索引）、like（回退）
+	Name() string
+	// Match 匹配条件，用于查询和统计总数；q.Terms() 不能为空
+	Match(q Query) Scope
+	// Rank 选出数据表的全部列和相关度列 ScoreColumn，并按相关度降序排序
+	Rank(q Query) Scope
+	// Highlight 将文本中与检索词匹配的部分用 <mark> 标记，其余部分按 HTML 转义
+	Highlight(text string, q Query) string
+}
+
+// Index 全文检索的索引定义
+type Index struct {
+	Table   string   // 数据表
+	Key     string   // 主键列，相关度相同时按主键排序
+	Columns []string // 检索的文本列，MySQL FULLTEXT 索引须恰好包含这些列
+}
+
+// Engine 按索引是否存在选择检索实现
+// 首次检索时检测索引（服务启动时数据库可能尚未就绪），检测成功后缓存结果；新建索引后需重启服务生效
+type Engine struct {
+	db    *gorm.DB
+	index Index
+
+	mu       sync.Mutex
+	searcher Searcher
+}
+
+// New 创建检索引擎
+func New(db *gorm.DB, index Index) *Engine {
+	return &Engine{db: db, index: index}
+}
+
+// Searcher 返回当前数据库可用的检索实现，检测失败时返回错误且不缓存，下次检索时重新检测
+func (e *Engine) Searcher(ctx context.Context) (Searcher, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.searcher != nil {
+		return e.searcher, nil
+	}
+
+	db := e.db.WithContext(ctx)
+	var (
+		searcher Searcher
+		err      error
+	)
+	// 仅支持 MySQL（见 database.openDialector），其他驱动（如单元测试中的 SQLite）直接回退
+	if db.Dialector.Name() == "mysql" {
+		searcher, err = detectMySQL(db, e.index)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("检测全文索引失败: %w", err)
+	}
+	if searcher == nil {
+		searcher = likeSearcher{index: e.index}
+	}
+	e.searcher = searcher
+	return searcher, nil
+}
+
+// column 返回带表名的列
+func (idx Index) column(name string) clause.Column {
+	return clause.Column{Table: idx.Table, Name: name}
+}
+
+// selectAll 选出数据表的全部列和相关度列，score 为相关度表达式
+func (idx Index) selectAll(db *gorm.DB, score string, vars ...any) *gorm.DB {
+	all := db.Statement.Quote(clause.Table{Name: idx.Table}) + ".*"
+	return db.Select(all+", "+score+" AS "+db.Statement.Quote(ScoreColumn), vars...)
+}
+
+// orderByScore 按相关度降序排序，相关度相同时按主键降序，保证分页稳定
+func (idx Index) orderByScore(db *gorm.DB) *gorm.DB {
+	return db.Order(clause.OrderBy{Columns: []clause.OrderByColumn{
+		{Column: clause.Column{Name: ScoreColumn}, Desc: true},
+		{Column: idx.column(idx.Key), Desc: true},
+	}})
+}
@@ -0,0 +1,100 @@
+package search
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryRunDB 创建只生成 SQL 不连接数据库的 GORM 实例
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "user:pass@tcp(127.0.0.1:3306)/test", SkipInitializeWithVersion: true}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestQueryTerms(t *testing.T) {
+	tests := map[string][]string{
+		"":                  {},
+		"data base":         {"data", "base"},
+		"Data, data; DATA":  {"Data"},
+		`+a -b* "c" (d) ~e`: {"a", "b", "c", "d", "e"},
+		"数据库 管理":            {"数据库", "管理"},
+		"x@y.com":           {"x", "y", "com"},
+		"  \t\n":            {},
+		"a'b OR 1=1 --":     {"a", "b", "OR", "1"},
+		"a:*&!b":            {"a", "b"},
+	}
+	for keyword, want := range tests {
+		if got := (Query{Keyword: keyword}).Terms(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Terms(%q) = %q, want %q", keyword, got, want)
+		}
+	}
+}
+
+func TestSearcherSQL(t *testing.T) {
+	index := Index{Table: "demo", Key: "id", Columns: []string{"field1", "field2"}}
+	tests := []struct {
+		name      string
+		searcher  Searcher
+		query     Query
+		wantMatch string
+		wantRank  string
+		wantVars  []any
+	}{
+		{
+			name:      "FULLTEXT 布尔模式",
+			searcher:  mysqlSearcher{index: index},
+			query:     Query{Keyword: "data base"},
+			wantMatch: "MATCH (`demo`.`field1`, `demo`.`field2`) AGAINST (? IN BOOLEAN MODE)",
+			wantRank:  "MATCH (`demo`.`field1`, `demo`.`field2`) AGAINST (? IN BOOLEAN MODE) AS `search_score`",
+			wantVars:  []any{"+data +base", "+data +base"},
+		},
+		{
+			name:      "FULLTEXT 前缀匹配",
+			searcher:  mysqlSearcher{index: index},
+			query:     Query{Keyword: "data base", Prefix: true},
+			wantMatch: "MATCH (`demo`.`field1`, `demo`.`field2`) AGAINST (? IN BOOLEAN MODE)",
+			wantRank:  "MATCH (`demo`.`field1`, `demo`.`field2`) AGAINST (? IN BOOLEAN MODE) AS `search_score`",
+			wantVars:  []any{"+data* +base*", "+data* +base*"},
+		},
+		{
+			name:      "LIKE 回退",
+			searcher:  likeSearcher{index: index},
+			query:     Query{Keyword: "Data base"},
+			wantMatch: "((LOWER(`demo`.`field1`) LIKE ? OR LOWER(`demo`.`field2`) LIKE ?)) AND ((LOWER(`demo`.`field1`) LIKE ? OR LOWER(`demo`.`field2`) LIKE ?))",
+			wantRank:  "CASE WHEN LOWER(`demo`.`field1`) = ? THEN 3 WHEN LOWER(`demo`.`field1`) LIKE ? THEN 2 ELSE 1 END + CASE WHEN LOWER(`demo`.`field2`) = ? THEN 3 WHEN LOWER(`demo`.`field2`) LIKE ? THEN 2 ELSE 1 END AS `search_score`",
+			wantVars:  []any{"data base", "data base%", "data base", "data base%", "%data%", "%data%", "%base%", "%base%"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := dryRunDB(t).Table("demo").Scopes(tt.searcher.Match(tt.query), tt.searcher.Rank(tt.query)).Find(&[]map[string]any{}).Statement
+			want := "SELECT `demo`.*, " + tt.wantRank + " FROM `demo` WHERE " + tt.wantMatch + " ORDER BY `search_score` DESC,`demo`.`id` DESC"
+			if got := stmt.SQL.String(); got != want {
+				t.Errorf("SQL = %s\nwant %s", got, want)
+			}
+			if !reflect.DeepEqual(stmt.Vars, tt.wantVars) {
+				t.Errorf("Vars = %v, want %v", stmt.Vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestEngineDetectError(t *testing.T) {
+	// DryRun 模式下检测 FULLTEXT 索引的查询返回错误，检测失败不缓存，下次检索时重新检测
+	engine := New(dryRunDB(t), Index{Table: "demo", Key: "id", Columns: []string{"field2"}})
+	for i := 0; i < 2; i++ {
+		if searcher, err := engine.Searcher(context.Background()); err == nil {
+			t.Fatalf("第 %d 次 Searcher() = %s, want error", i+1, searcher.Name())
+		}
+	}
+}
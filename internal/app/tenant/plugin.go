@@ -0,0 +1,172 @@
+package tenant
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// 租户隔离已应用在 GORM Statement 中的设置键
+const tenantAppliedKey = "tenant:applied"
+
+// 隔离模式
+const (
+	ModeColumn   = "column"
+	ModeSchema   = "schema"
+	ModeDatabase = "database"
+)
+
+// Plugin 租户隔离插件
+//   - 查询、更新、删除：模型包含 tenant_id 字段时追加 tenant_id = 当前租户 的条件
+//   - 创建：模型包含 tenant_id 字段时填充当前租户，传入的 tenant_id 与当前租户不一致时拒绝（ErrCrossTenant）
+//   - schema 模式：表名加上当前租户的 schema 前缀
+//
+// 上下文中没有租户且未调用 Elevate 时，访问包含 tenant_id 字段的模型（schema 模式下为任意模型）会返回 ErrTenantRequired
+type Plugin struct {
+	mode         string
+	schemaFormat string
+}
+
+// NewPlugin 创建租户隔离插件
+func NewPlugin(mode, schemaFormat string) *Plugin {
+	return &Plugin{mode: mode, schemaFormat: schemaFormat}
+}
+
+// Name 插件名称
+func (p *Plugin) Name() string {
+	return "tenant"
+}
+
+// Initialize 注册租户隔离回调，在 GORM 执行 SQL 之前追加条件或填充字段
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("tenant:create", p.assign); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("tenant:query", p.scope); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("tenant:row", p.scope); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("tenant:update", p.scope); err != nil {
+		return err
+	}
+	return db.Callback().Delete().Before("gorm:delete").Register("tenant:delete", p.scope)
+}
+
+// scope 为查询、更新、删除语句追加租户条件
+func (p *Plugin) scope(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	// 同一个 Statement 可能被执行多次（如先 Count 再 Find），避免重复追加条件
+	if _, applied := db.InstanceGet(tenantAppliedKey); applied {
+		return
+	}
+
+	id, ok := p.prepare(db)
+	if !ok {
+		return
+	}
+
+	field := db.Statement.Schema.LookUpField(Column)
+	if field != nil && id != "" {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: id},
+		}})
+	}
+	db.InstanceSet(tenantAppliedKey, true)
+}
+
+// assign 为创建语句填充租户标识
+func (p *Plugin) assign(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	id, ok := p.prepare(db)
+	if !ok {
+		return
+	}
+	// 已提升为跨租户访问时 prepare 返回空标识，此时仍使用上下文中的租户填充未指定租户的记录
+	if id == "" {
+		id, _ = FromContext(db.Statement.Context)
+	}
+	field := db.Statement.Schema.LookUpField(Column)
+	if field == nil || id == "" {
+		return
+	}
+
+	elevated := IsElevated(db.Statement.Context)
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			p.assignOne(db, field, reflect.Indirect(rv.Index(i)), id, elevated)
+		}
+	case reflect.Struct:
+		p.assignOne(db, field, rv, id, elevated)
+	default:
+		// 使用 map 创建时直接写入租户字段
+		db.Statement.SetColumn(field.DBName, id)
+	}
+}
+
+// assignOne 为单条记录填充租户标识：未指定时填充当前租户，指定了其他租户时视为越权写入（跨租户访问除外）
+func (p *Plugin) assignOne(db *gorm.DB, field *schema.Field, rv reflect.Value, id string, elevated bool) {
+	value, zero := field.ValueOf(db.Statement.Context, rv)
+	if zero {
+		_ = db.AddError(field.Set(db.Statement.Context, rv, id))
+		return
+	}
+	if fmt.Sprint(value) != id && !elevated {
+		_ = db.AddError(fmt.Errorf("%w: %v", ErrCrossTenant, value))
+	}
+}
+
+// prepare 解析当前租户并按模式调整语句，返回租户标识（已提升为跨租户访问时可能为空）
+// 无权访问时向 db 添加错误并返回 false
+func (p *Plugin) prepare(db *gorm.DB) (string, bool) {
+	ctx := db.Statement.Context
+	id, hasTenant := FromContext(ctx)
+	elevated := IsElevated(ctx)
+
+	// schema 模式下所有表都属于租户；其他模式只有包含 tenant_id 字段的模型需要租户
+	needsTenant := p.mode == ModeSchema || db.Statement.Schema.LookUpField(Column) != nil
+	if !hasTenant {
+		if needsTenant && !elevated {
+			_ = db.AddError(ErrTenantRequired)
+			return "", false
+		}
+		return "", true
+	}
+	if !ValidID(id) {
+		_ = db.AddError(ErrInvalidTenant)
+		return "", false
+	}
+
+	if p.mode == ModeSchema {
+		p.qualifyTable(db, id)
+	}
+	if elevated {
+		// 跨租户访问不追加租户条件
+		return "", true
+	}
+	return id, true
+}
+
+// qualifyTable schema 模式下为表名加上租户 schema 前缀，已显式指定 schema 的表名保持不变
+func (p *Plugin) qualifyTable(db *gorm.DB, id string) {
+	table := db.Statement.Table
+	if table == "" {
+		table = db.Statement.Schema.Table
+	}
+	if strings.Contains(table, ".") {
+		return
+	}
+	db.Statement.Table = fmt.Sprintf(p.schemaFormat, id) + "." + table
+}
@@ -0,0 +1,296 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// tenantRecord 包含租户字段的测试模型
+type tenantRecord struct {
+	ID       int
+	Name     string
+	TenantID string
+}
+
+// sharedRecord 不包含租户字段的测试模型
+type sharedRecord struct {
+	ID   int
+	Name string
+}
+
+// openTestDB 打开测试用的 SQLite 数据库（临时文件）并迁移测试模型
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&tenantRecord{}, &sharedRecord{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// openColumnDB 打开注册了 column 模式租户插件的数据库，并写入 t1、t2 两个租户的数据
+func openColumnDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := openTestDB(t)
+	if err := db.Use(NewPlugin(ModeColumn, "")); err != nil {
+		t.Fatal(err)
+	}
+	records := []tenantRecord{{Name: "a", TenantID: "t1"}, {Name: "b", TenantID: "t1"}, {Name: "c", TenantID: "t2"}}
+	if err := db.WithContext(Elevate(context.Background())).Create(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// recordNames 返回全部租户的记录名称（按字母顺序），可选按租户过滤
+func recordNames(t *testing.T, db *gorm.DB, tenantID string) []string {
+	t.Helper()
+	var records []tenantRecord
+	query := db.WithContext(Elevate(context.Background()))
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+	if err := query.Find(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(records))
+	for _, record := range records {
+		names = append(names, record.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPluginFailClosed(t *testing.T) {
+	db := openColumnDB(t)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{"缺少租户", context.Background(), ErrTenantRequired},
+		{"租户标识格式无效", WithTenant(context.Background(), "t1; DROP"), ErrInvalidTenant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var records []tenantRecord
+			var count int64
+			operations := map[string]error{
+				"查询": db.WithContext(tt.ctx).Find(&records).Error,
+				"计数": db.WithContext(tt.ctx).Model(&tenantRecord{}).Count(&count).Error,
+				"更新": db.WithContext(tt.ctx).Model(&tenantRecord{}).Where("1 = 1").Update("name", "x").Error,
+				"删除": db.WithContext(tt.ctx).Where("1 = 1").Delete(&tenantRecord{}).Error,
+				"创建": db.WithContext(tt.ctx).Create(&tenantRecord{Name: "x"}).Error,
+			}
+			_, operations["逐行读取"] = db.WithContext(tt.ctx).Model(&tenantRecord{}).Select("name").Rows()
+			for operation, err := range operations {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("%s error = %v, want %v", operation, err, tt.wantErr)
+				}
+			}
+			// 被拒绝的写操作没有生效
+			if got := recordNames(t, db, ""); !slices.Equal(got, []string{"a", "b", "c"}) {
+				t.Errorf("数据 = %v, want [a b c]", got)
+			}
+		})
+	}
+
+	t.Run("不包含租户字段的模型不需要租户", func(t *testing.T) {
+		if err := db.Create(&sharedRecord{Name: "s"}).Error; err != nil {
+			t.Fatal(err)
+		}
+		var records []sharedRecord
+		if err := db.Find(&records).Error; err != nil || len(records) != 1 {
+			t.Errorf("Find() = %d 条, error = %v", len(records), err)
+		}
+	})
+}
+
+func TestPluginColumnScope(t *testing.T) {
+	t1 := WithTenant(context.Background(), "t1")
+
+	t.Run("查询只返回当前租户的数据", func(t *testing.T) {
+		db := openColumnDB(t)
+		var records []tenantRecord
+		if err := db.WithContext(t1).Find(&records).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 2 || records[0].TenantID != "t1" || records[1].TenantID != "t1" {
+			t.Errorf("Find() = %+v", records)
+		}
+		var other tenantRecord
+		if err := db.WithContext(t1).Where("name = ?", "c").First(&other).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("查询其他租户数据 error = %v, want ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("先 Count 再 Find 不重复追加条件", func(t *testing.T) {
+		db := openColumnDB(t)
+		var count int64
+		var records []tenantRecord
+		query := db.WithContext(t1).Model(&tenantRecord{})
+		if err := query.Count(&count).Find(&records).Error; err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 || len(records) != 2 {
+			t.Errorf("Count() = %d, Find() = %d 条", count, len(records))
+		}
+		stmt := db.Session(&gorm.Session{DryRun: true}).WithContext(t1).Model(&tenantRecord{}).Count(&count).Find(&records).Statement
+		if n := strings.Count(stmt.SQL.String(), "tenant_id"); n != 1 {
+			t.Errorf("SQL 中租户条件出现 %d 次: %s", n, stmt.SQL.String())
+		}
+	})
+
+	t.Run("更新只作用于当前租户", func(t *testing.T) {
+		db := openColumnDB(t)
+		result := db.WithContext(t1).Model(&tenantRecord{}).Where("1 = 1").Update("name", "x")
+		if result.Error != nil || result.RowsAffected != 2 {
+			t.Fatalf("Update() RowsAffected = %d, error = %v", result.RowsAffected, result.Error)
+		}
+		if got := recordNames(t, db, "t2"); !slices.Equal(got, []string{"c"}) {
+			t.Errorf("t2 数据 = %v, want [c]", got)
+		}
+	})
+
+	t.Run("删除只作用于当前租户", func(t *testing.T) {
+		db := openColumnDB(t)
+		result := db.WithContext(t1).Where("1 = 1").Delete(&tenantRecord{})
+		if result.Error != nil || result.RowsAffected != 2 {
+			t.Fatalf("Delete() RowsAffected = %d, error = %v", result.RowsAffected, result.Error)
+		}
+		if got := recordNames(t, db, ""); !slices.Equal(got, []string{"c"}) {
+			t.Errorf("剩余数据 = %v, want [c]", got)
+		}
+	})
+
+	t.Run("创建时填充当前租户", func(t *testing.T) {
+		db := openColumnDB(t)
+		records := []tenantRecord{{Name: "d"}, {Name: "e", TenantID: "t1"}}
+		if err := db.WithContext(t1).Create(&records).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := db.WithContext(t1).Model(&tenantRecord{}).Create(map[string]any{"name": "f"}).Error; err != nil {
+			t.Fatal(err)
+		}
+		if got := recordNames(t, db, "t1"); !slices.Equal(got, []string{"a", "b", "d", "e", "f"}) {
+			t.Errorf("t1 数据 = %v", got)
+		}
+	})
+
+	t.Run("拒绝写入其他租户的数据", func(t *testing.T) {
+		db := openColumnDB(t)
+		err := db.WithContext(t1).Create(&tenantRecord{Name: "d", TenantID: "t2"}).Error
+		if !errors.Is(err, ErrCrossTenant) {
+			t.Errorf("Create() error = %v, want ErrCrossTenant", err)
+		}
+		if got := recordNames(t, db, "t2"); !slices.Equal(got, []string{"c"}) {
+			t.Errorf("t2 数据 = %v, want [c]", got)
+		}
+	})
+
+	t.Run("跨租户访问不追加条件", func(t *testing.T) {
+		db := openColumnDB(t)
+		var count int64
+		if err := db.WithContext(Elevate(t1)).Model(&tenantRecord{}).Count(&count).Error; err != nil || count != 3 {
+			t.Errorf("Count() = %d, error = %v", count, err)
+		}
+		// 认证中间件写入的 ElevatedKey 同样生效
+		ctx := context.WithValue(context.Background(), ElevatedKey, true)
+		if err := db.WithContext(ctx).Model(&tenantRecord{}).Count(&count).Error; err != nil || count != 3 {
+			t.Errorf("ElevatedKey Count() = %d, error = %v", count, err)
+		}
+		if err := db.WithContext(Elevate(t1)).Create(&tenantRecord{Name: "d", TenantID: "t2"}).Error; err != nil {
+			t.Errorf("跨租户创建 error = %v", err)
+		}
+	})
+}
+
+func TestPluginSchemaMode(t *testing.T) {
+	db := openTestDB(t)
+	if err := db.Use(NewPlugin(ModeSchema, "tenant_%s")); err != nil {
+		t.Fatal(err)
+	}
+	dryRun := db.Session(&gorm.Session{DryRun: true})
+
+	var records []sharedRecord
+	stmt := dryRun.WithContext(WithTenant(context.Background(), "t1")).Find(&records).Statement
+	if sql := stmt.SQL.String(); !strings.Contains(sql, "`tenant_t1`.`shared_records`") {
+		t.Errorf("SQL = %s, 缺少租户 schema", sql)
+	}
+	// schema 模式下任意模型都需要租户
+	if err := dryRun.Find(&records).Error; !errors.Is(err, ErrTenantRequired) {
+		t.Errorf("缺少租户 error = %v, want ErrTenantRequired", err)
+	}
+	// 已显式指定 schema 的表名保持不变
+	stmt = dryRun.WithContext(WithTenant(context.Background(), "t1")).Table("shared.shared_records").Find(&records).Statement
+	if sql := stmt.SQL.String(); strings.Contains(sql, "tenant_t1") {
+		t.Errorf("SQL = %s, 不应追加租户 schema", sql)
+	}
+}
+
+func TestRoutingPool(t *testing.T) {
+	fallback, t1DB := openTestDB(t), openTestDB(t)
+	for db, name := range map[*gorm.DB]string{fallback: "fallback", t1DB: "t1"} {
+		if err := db.Create(&sharedRecord{Name: name}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	fallbackPool, _ := fallback.DB()
+	t1Pool, _ := t1DB.DB()
+	routing := NewRoutingPool(fallbackPool, map[string]*sql.DB{"t1": t1Pool})
+	db := fallback.Session(&gorm.Session{})
+	db.Statement.ConnPool = routing
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		want    string
+		wantErr error
+	}{
+		{"路由到租户连接", WithTenant(context.Background(), "t1"), "t1", nil},
+		{"跨租户访问使用默认连接", Elevate(context.Background()), "fallback", nil},
+		{"缺少租户", context.Background(), "", ErrTenantRequired},
+		{"租户未配置连接", WithTenant(context.Background(), "t2"), "", ErrUnknownTenant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var record sharedRecord
+			err := db.WithContext(tt.ctx).First(&record).Error
+			if !errors.Is(err, tt.wantErr) || record.Name != tt.want {
+				t.Errorf("First() = %q, error = %v, want %q, %v", record.Name, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("事务在租户连接上开启", func(t *testing.T) {
+		err := db.WithContext(WithTenant(context.Background(), "t1")).Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&sharedRecord{Name: "tx"}).Error
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var count int64
+		t1DB.Model(&sharedRecord{}).Where("name = ?", "tx").Count(&count)
+		if count != 1 {
+			t.Errorf("租户连接中的记录数 = %d, want 1", count)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+)
+
+// RoutingPool 按租户路由的连接池（database 模式）
+// 作为默认连接的 GORM ConnPool 使用，每次执行 SQL 或开启事务时根据上下文中的租户选择对应租户的连接，
+// 因此 Repository 和 TxManager 无需感知租户：事务也会在租户自己的连接上开启。
+type RoutingPool struct {
+	fallback *sql.DB            // 默认连接，用于跨租户访问以及 Ping、关闭等连接级操作
+	pools    map[string]*sql.DB // 租户标识 -> 租户连接
+	required *sql.DB            // 缺少租户时使用的连接，任何操作都返回 ErrTenantRequired
+	unknown  *sql.DB            // 租户未配置连接时使用的连接，任何操作都返回 ErrUnknownTenant
+}
+
+// NewRoutingPool 创建按租户路由的连接池
+func NewRoutingPool(fallback *sql.DB, pools map[string]*sql.DB) *RoutingPool {
+	return &RoutingPool{
+		fallback: fallback,
+		pools:    pools,
+		required: sql.OpenDB(failingConnector{err: ErrTenantRequired}),
+		unknown:  sql.OpenDB(failingConnector{err: ErrUnknownTenant}),
+	}
+}
+
+// target 根据上下文选择连接：租户连接；跨租户访问且没有租户时使用默认连接
+func (p *RoutingPool) target(ctx context.Context) *sql.DB {
+	id, ok := FromContext(ctx)
+	if !ok {
+		if IsElevated(ctx) {
+			return p.fallback
+		}
+		return p.required
+	}
+	if pool, ok := p.pools[id]; ok {
+		return pool
+	}
+	return p.unknown
+}
+
+// PrepareContext 实现 gorm.ConnPool
+func (p *RoutingPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.target(ctx).PrepareContext(ctx, query)
+}
+
+// ExecContext 实现 gorm.ConnPool
+func (p *RoutingPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.target(ctx).ExecContext(ctx, query, args...)
+}
+
+// QueryContext 实现 gorm.ConnPool
+func (p *RoutingPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.target(ctx).QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext 实现 gorm.ConnPool
+func (p *RoutingPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.target(ctx).QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx 实现 gorm.TxBeginner，在租户连接上开启事务
+func (p *RoutingPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return p.target(ctx).BeginTx(ctx, opts)
+}
+
+// GetDBConn 实现 gorm.GetDBConnector，db.DB() 返回默认连接，连接池配置、Ping 和关闭均作用于默认连接
+// 租户连接由 database.Manager 统一管理和关闭
+func (p *RoutingPool) GetDBConn() (*sql.DB, error) {
+	return p.fallback, nil
+}
+
+// failingConnector 建立连接总是失败的连接器，使无权访问的操作以普通数据库错误的形式返回
+type failingConnector struct {
+	err error
+}
+
+// Connect 实现 driver.Connector
+func (c failingConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, c.err
+}
+
+// Driver 实现 driver.Connector
+func (c failingConnector) Driver() driver.Driver {
+	return failingDriver(c)
+}
+
+// failingDriver 与 failingConnector 配套的驱动
+type failingDriver struct {
+	err error
+}
+
+// Open 实现 driver.Driver
+func (d failingDriver) Open(string) (driver.Conn, error) {
+	return nil, d.err
+}
@@ -0,0 +1,51 @@
+package tenant
+
+import (
+	"database/sql"
+	"fmt"
+	"gin-template/internal/app/config"
+	"gin-template/internal/app/database"
+)
+
+// Install 按配置为数据库连接启用租户隔离
+//   - 为所有连接注册租户隔离插件
+//   - database 模式下将默认连接的连接池替换为按租户路由的 RoutingPool
+func Install(dbManager database.Manager, cfg config.TenantConfig) error {
+	plugin := NewPlugin(cfg.Mode, cfg.SchemaFormat)
+	for _, name := range dbManager.Names() {
+		db, err := dbManager.DB(name)
+		if err != nil {
+			return err
+		}
+		if err := db.Use(plugin); err != nil {
+			return fmt.Errorf("数据库连接 '%s' 注册租户隔离插件失败: %w", name, err)
+		}
+	}
+
+	if cfg.Mode != ModeDatabase {
+		return nil
+	}
+
+	pools := make(map[string]*sql.DB, len(cfg.Databases))
+	for tenantID, name := range cfg.Databases {
+		db, err := dbManager.DB(name)
+		if err != nil {
+			return err
+		}
+		pool, err := db.DB()
+		if err != nil {
+			return fmt.Errorf("获取租户 '%s' 的底层sql.DB失败: %w", tenantID, err)
+		}
+		pools[tenantID] = pool
+	}
+
+	db := dbManager.Default()
+	fallback, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("获取默认连接的底层sql.DB失败: %w", err)
+	}
+	routing := NewRoutingPool(fallback, pools)
+	db.ConnPool = routing
+	db.Statement.ConnPool = routing
+	return nil
+}
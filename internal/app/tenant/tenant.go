@@ -0,0 +1,85 @@
+// Package tenant 多租户支持：租户上下文、GORM 租户隔离插件以及按租户路由的数据库连接池
+//
+// 支持三种隔离模式：
+//   - column：所有租户共享表，模型包含 tenant_id 字段时自动追加租户过滤条件并在创建时填充
+//   - schema：每个租户使用独立的 schema（MySQL 中即独立的库），表名自动加上租户 schema 前缀
+//   - database：每个租户使用独立的数据库连接，默认连接的连接池按上下文中的租户路由到对应连接
+//
+// 上下文中没有租户时访问租户数据会被拒绝，需要跨租户访问（如后台任务、运营统计）时使用 Elevate 显式提升权限。
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// ContextKey 租户标识在上下文中的键，由租户中间件通过 ctx.Set(tenant.ContextKey, ...) 写入
+const ContextKey = "tenant"
+
+// ElevatedKey 跨租户访问标记在上下文中的键，上游认证中间件确认调用方为管理员后通过 ctx.Set(tenant.ElevatedKey, true) 写入
+const ElevatedKey = "tenant_elevated"
+
+// Column 租户标识字段名
+const Column = "tenant_id"
+
+var (
+	// ErrTenantRequired 上下文中没有租户标识，且未提升为跨租户访问
+	ErrTenantRequired = errors.New("缺少租户标识，禁止访问租户数据")
+	// ErrUnknownTenant 租户未配置对应的数据库连接（database 模式）
+	ErrUnknownTenant = errors.New("租户未配置数据库连接")
+	// ErrCrossTenant 写入的数据属于其他租户
+	ErrCrossTenant = errors.New("禁止跨租户写入数据")
+	// ErrInvalidTenant 租户标识格式无效
+	ErrInvalidTenant = errors.New("租户标识格式无效")
+)
+
+// 租户标识格式：字母、数字、下划线和连字符，schema 模式下会拼接到表名中，必须严格限制
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// tenantContextKey 通过 WithTenant 写入的租户标识在上下文中的键
+type tenantContextKey struct{}
+
+// elevatedContextKey 跨租户访问标记在上下文中的键
+type elevatedContextKey struct{}
+
+// ValidID 判断租户标识格式是否合法
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+// WithTenant 返回携带租户标识的上下文，用于后台任务等非 HTTP 请求场景
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, id)
+}
+
+// FromContext 获取上下文中的租户标识
+// 控制器直接传入 *gin.Context 作为 context.Context，其 Value 方法可以读取中间件 ctx.Set 写入的值
+func FromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	if id, ok := ctx.Value(tenantContextKey{}).(string); ok && id != "" {
+		return id, true
+	}
+	id, ok := ctx.Value(ContextKey).(string)
+	return id, ok && id != ""
+}
+
+// Elevate 返回允许跨租户访问的上下文：不追加租户过滤条件，上下文中没有租户时使用默认 schema / 默认连接
+// 仅用于确实需要访问全部租户数据的场景，调用方负责权限校验
+func Elevate(ctx context.Context) context.Context {
+	return context.WithValue(ctx, elevatedContextKey{}, true)
+}
+
+// IsElevated 判断上下文是否已提升为跨租户访问（Elevate 或认证中间件写入 ElevatedKey）
+func IsElevated(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	if elevated, _ := ctx.Value(elevatedContextKey{}).(bool); elevated {
+		return true
+	}
+	elevated, _ := ctx.Value(ElevatedKey).(bool)
+	return elevated
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"fmt"
+	"gin-template/internal/app/query"
+	"gin-template/internal/app/tenant"
+	"gin-template/internal/audit/dto"
+	"gin-template/internal/audit/model"
+	"gin-template/internal/audit/service"
+	"gin-template/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditController 审计日志控制器，持有服务层接口实例
+type AuditController struct {
+	service         service.AuditService
+	requireElevated bool // 查询全部审计日志是否要求跨租户访问权限，启用多租户时为 true
+}
+
+// NewAuditController 创建审计日志控制器实例，tenantEnabled 为是否启用多租户
+func NewAuditController(auditService service.AuditService, tenantEnabled bool) *AuditController {
+	return &AuditController{
+		// 注入服务层实例
+		service:         auditService,
+		requireElevated: tenantEnabled,
+	}
+}
+
+// ListAuditLogs 分页查询审计日志，支持 filter / sort 查询规格，字段范围见 dto.AuditLogQueryFields
+// 启用多租户时审计日志包含所有租户的完整快照，只允许已提升为跨租户访问的管理员查询；未启用时不做限制，与其他接口一致
+func (ctr *AuditController) ListAuditLogs(ctx *gin.Context) {
+	if ctr.requireElevated && !tenant.IsElevated(ctx) {
+		utils.HandlerFunc(ctx, utils.NewBusinessError(utils.ErrCodePermissionDenied, "error.permission_denied"))
+		return
+	}
+	page, pageSize, ok := bindPage(ctx)
+	if !ok {
+		return
+	}
+	// 解析 filter / sort 查询规格，字段须在白名单内
+	spec, err := query.Parse(ctx.Request.URL.Query(), dto.AuditLogQueryFields)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+	// 调用服务层
+	list, total, err := ctr.service.ListAuditLogPage(ctx, page, pageSize, spec)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+	// 返回数据
+	utils.SuccessPage(ctx, "common.fetch_success", total, page, pageSize, list)
+}
+
+// History 返回查询指定实体记录变更历史的处理函数，路径参数 id 为记录主键
+// 用法：demo.GET("/:id/history", auditController.History(new(model.Demo)))
+func (ctr *AuditController) History(auditable model.Auditable) gin.HandlerFunc {
+	entity := auditable.AuditEntity()
+	return func(ctx *gin.Context) {
+		// 从 URL 参数中提取 ID
+		var idReq dto.EntityIDRequest
+		if err := ctx.ShouldBindUri(&idReq); err != nil {
+			utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("ID 绑定失败: %w", err)))
+			return
+		}
+		page, pageSize, ok := bindPage(ctx)
+		if !ok {
+			return
+		}
+		// 调用服务层，启用多租户时租户插件按审计日志的 tenant_id 过滤，只能查询本租户记录的历史；记录被物理删除后历史仍然可以查询
+		list, total, err := ctr.service.ListEntityHistory(ctx, entity, idReq.ID, page, pageSize)
+		if err != nil {
+			utils.HandlerFunc(ctx, err)
+			return
+		}
+		// 返回数据
+		utils.SuccessPage(ctx, "common.fetch_success", total, page, pageSize, list)
+	}
+}
+
+// bindPage 绑定分页参数并处理默认值，绑定失败时已写入错误响应
+func bindPage(ctx *gin.Context) (int, int, bool) {
+	var pageQuery dto.PageQueryRequest
+	if err := ctx.ShouldBindQuery(&pageQuery); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("分页参数绑定失败: %w", err)))
+		return 0, 0, false
+	}
+	page, pageSize := pageQuery.Page, pageQuery.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	return page, pageSize, true
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"encoding/json"
+	"gin-template/internal/app/query"
+	"time"
+)
+
+// AuditLogQueryFields 审计日志查询字段白名单，限定 filter / sort 参数可使用的字段
+var AuditLogQueryFields = query.Allowlist{
+	"id":          {Column: "id", Type: query.Int, Filterable: true, Sortable: true},
+	"entity":      {Column: "entity", Type: query.String, Filterable: true},
+	"entity_id":   {Column: "entity_id", Type: query.String, Filterable: true},
+	"action":      {Column: "action", Type: query.String, Filterable: true},
+	"actor":       {Column: "actor", Type: query.String, Filterable: true},
+	"request_id":  {Column: "request_id", Type: query.String, Filterable: true},
+	"client_ip":   {Column: "client_ip", Type: query.String, Filterable: true},
+	"create_time": {Column: "create_time", Type: query.Time, Filterable: true, Sortable: true},
+}
+
+// PageQueryRequest 分页查询参数
+type PageQueryRequest struct {
+	Page     int `form:"page"`
+	PageSize int `form:"pageSize"`
+}
+
+// EntityIDRequest 实体ID路径参数
+type EntityIDRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+// AuditFieldChange 字段变更前后的取值
+type AuditFieldChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// AuditLogResponse 审计日志响应结构体
+type AuditLogResponse struct {
+	ID         int64                       `json:"id"`
+	Entity     string                      `json:"entity"`
+	EntityID   string                      `json:"entity_id"`
+	Action     string                      `json:"action"`            // create / update / delete / soft_delete / restore
+	Before     json.RawMessage             `json:"before"`            // 变更前快照，创建时为 null
+	After      json.RawMessage             `json:"after"`             // 变更后快照，物理删除时为 null
+	Changes    map[string]AuditFieldChange `json:"changes,omitempty"` // 发生变化的字段，仅更新类操作返回
+	Actor      string                      `json:"actor"`
+	RequestID  string                      `json:"request_id"`
+	ClientIP   string                      `json:"client_ip"`
+	CreateTime *time.Time                  `json:"create_time"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// 审计操作类型
+const (
+	ActionCreate     = "create"      // 创建
+	ActionUpdate     = "update"      // 更新
+	ActionDelete     = "delete"      // 物理删除
+	ActionSoftDelete = "soft_delete" // 软删除
+	ActionRestore    = "restore"     // 恢复软删除
+)
+
+// Auditable 需要记录审计日志的数据模型实现该接口，返回审计日志中的实体名称
+type Auditable interface {
+	AuditEntity() string
+}
+
+// AuditLog 审计日志数据模型，记录数据变更前后的快照及所属租户、操作人、请求标识、客户端 IP
+type AuditLog struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement;column:id"`
+	TenantID   string     `json:"tenant_id" gorm:"type:varchar(64);column:tenant_id;not null;default:'';index"` // 记录所属租户，多租户 column 模式下查询按租户过滤
+	Entity     string     `json:"entity" gorm:"type:varchar(64);column:entity;not null;index:idx_audit_log_entity,priority:1"`
+	EntityID   string     `json:"entity_id" gorm:"type:varchar(64);column:entity_id;not null;index:idx_audit_log_entity,priority:2"`
+	Action     string     `json:"action" gorm:"type:varchar(16);column:action;not null"`
+	BeforeData *string    `json:"before_data" gorm:"type:text;column:before_data"` // 变更前快照（JSON），创建时为空
+	AfterData  *string    `json:"after_data" gorm:"type:text;column:after_data"`   // 变更后快照（JSON），物理删除时为空
+	Actor      string     `json:"actor" gorm:"type:varchar(64);column:actor"`
+	RequestID  string     `json:"request_id" gorm:"type:varchar(64);column:request_id;index:idx_audit_log_request"`
+	ClientIP   string     `json:"client_ip" gorm:"type:varchar(64);column:client_ip"`
+	CreateTime *time.Time `json:"create_time" gorm:"column:create_time;autoCreateTime;index:idx_audit_log_create_time"`
+}
+
+// TableName 指定表名
+func (*AuditLog) TableName() string {
+	return "audit_log"
+}
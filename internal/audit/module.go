@@ -0,0 +1,124 @@
+// Package audit 审计日志模块：注册数据变更记录插件，提供审计日志和单条记录变更历史的查询接口
+package audit
+
+import (
+	"context"
+	"errors"
+	"gin-template/internal/app/config"
+	"gin-template/internal/app/database"
+	"gin-template/internal/app/module"
+	"gin-template/internal/audit/controller"
+	"gin-template/internal/audit/model"
+	"gin-template/internal/audit/recorder"
+	"gin-template/internal/audit/repository"
+	"gin-template/internal/audit/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Name 模块名称
+const Name = "audit"
+
+// Module 审计日志模块
+type Module struct {
+	cfg        config.AuditConfig
+	dbManager  database.Manager
+	db         *gorm.DB
+	recorder   *recorder.Recorder
+	installed  bool // 数据变更记录插件是否已注册
+	controller *controller.AuditController
+}
+
+// NewModule 创建审计日志模块
+func NewModule() *Module {
+	return &Module{}
+}
+
+// Name 模块名称
+func (m *Module) Name() string {
+	return Name
+}
+
+// DependsOn 不依赖其他模块
+func (m *Module) DependsOn() []string {
+	return nil
+}
+
+// Init 初始化审计日志查询接口；启用审计日志时注册数据变更记录插件
+func (m *Module) Init(deps *module.Deps) error {
+	m.cfg = deps.Config.Audit
+	m.dbManager = deps.DBManager
+	m.db = deps.DBManager.Default()
+	m.controller = controller.NewAuditController(service.NewAuditService(repository.NewAuditLogRepository(m.db)), deps.Config.Tenant.Enabled)
+	m.recorder = recorder.New(recorder.Options{AuditLog: m.cfg.Enabled})
+	if m.cfg.Enabled {
+		return m.install()
+	}
+	return nil
+}
+
+// Enabled 是否启用审计日志（记录数据变更并提供查询接口）
+func (m *Module) Enabled() bool {
+	return m.cfg.Enabled
+}
+
+// AddChangeHandler 注册数据变更处理函数（如保存历史版本），未启用审计日志时同样注册数据变更记录插件
+// 须在依赖本模块的模块初始化时调用
+func (m *Module) AddChangeHandler(handler recorder.ChangeHandler) error {
+	m.recorder.AddHandler(handler)
+	return m.install()
+}
+
+// History 返回查询指定实体记录变更历史的处理函数，供其他模块注册到自己的路由中，须在启用审计日志时注册
+func (m *Module) History(auditable model.Auditable) gin.HandlerFunc {
+	return m.controller.History(auditable)
+}
+
+// install 为所有数据库连接注册数据变更记录插件，只注册一次
+func (m *Module) install() error {
+	if m.installed {
+		return nil
+	}
+	if err := m.recorder.Install(m.dbManager); err != nil {
+		return err
+	}
+	m.installed = true
+	return nil
+}
+
+// RegisterRoutes 启用审计日志时注册审计日志查询路由，未启用时 audit_log 表不存在
+func (m *Module) RegisterRoutes(group *gin.RouterGroup) {
+	if !m.cfg.Enabled {
+		return
+	}
+	group.GET("/audit/logs", m.controller.ListAuditLogs)
+}
+
+// Migrations 启用审计日志时迁移 audit_log 表
+func (m *Module) Migrations() []any {
+	if !m.cfg.Enabled {
+		return nil
+	}
+	return []any{&model.AuditLog{}}
+}
+
+// HealthChecks 启用审计日志时检查 audit_log 表是否存在，表不存在时所有数据变更都会失败
+func (m *Module) HealthChecks() map[string]module.HealthCheck {
+	if !m.cfg.Enabled {
+		return nil
+	}
+	return map[string]module.HealthCheck{
+		"audit_log_table": func(ctx context.Context) error {
+			if !m.db.WithContext(ctx).Migrator().HasTable(&model.AuditLog{}) {
+				return errors.New("audit_log 表不存在或无法访问")
+			}
+			return nil
+		},
+	}
+}
+
+// Shutdown 无需释放的资源
+func (m *Module) Shutdown(context.Context) error {
+	return nil
+}
@@ -0,0 +1,506 @@
+// Package recorder 数据变更记录 GORM 插件：在创建、更新、删除时捕获数据变更前后的快照，写入审计日志并通知变更处理函数。
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gin-template/internal/app/database"
+	"gin-template/internal/app/tenant"
+	"gin-template/internal/audit/model"
+	"gin-template/internal/utils"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// 写入前读取的快照在 GORM Statement 中的设置键
+const snapshotsKey = "audit:snapshots"
+
+// GORM 默认事务的提交 / 回滚回调名称
+const commitCallback = "gorm:commit_or_rollback_transaction"
+
+// snapshot 单条记录的快照
+type snapshot struct {
+	id         any           // 主键取值
+	value      reflect.Value // 记录（模型结构体）
+	data       []byte        // 记录序列化后的 JSON
+	tenant     string        // 记录所属租户，模型不包含 tenant_id 字段时为空
+	softDelete bool          // 模型是否支持软删除
+	deleted    bool          // 是否已软删除
+}
+
+// Change 单条记录的变更
+type Change struct {
+	Entity string // 实体名称
+	Action string // 操作类型，取值见 model.Action*
+	ID     any    // 主键取值
+	Before any    // 变更前的记录（模型指针），创建时为 nil
+	After  any    // 变更后的记录（模型指针），物理删除时为 nil
+}
+
+// ChangeHandler 变更处理函数，在数据变更所在的事务中调用（tx 已绑定该事务和请求上下文），返回错误时数据变更随之回滚
+type ChangeHandler func(tx *gorm.DB, changes []Change) error
+
+// Options 插件选项
+type Options struct {
+	AuditLog bool            // 是否写入审计日志
+	Handlers []ChangeHandler // 变更处理函数，如保存历史版本
+}
+
+// Recorder 数据变更记录插件，只处理实现 model.Auditable 的数据模型
+//   - 创建：记录新建记录的快照；带 ON CONFLICT 的创建按冲突列读取写入前后的数据，区分新建和更新，未变化的记录不记录
+//   - 更新、删除：执行前按相同条件读取受影响记录的快照，执行后与最新数据比较，只记录发生变化的记录；
+//     软删除标记的变化记录为 soft_delete / restore
+//   - 审计日志和变更处理函数与数据变更在同一事务中执行（未显式开启事务时为 GORM 默认事务），失败时数据变更随之回滚
+type Recorder struct {
+	opts Options
+}
+
+// New 创建数据变更记录插件
+func New(opts Options) *Recorder {
+	return &Recorder{opts: opts}
+}
+
+// Install 为所有数据库连接注册数据变更记录插件
+func Install(dbManager database.Manager, opts Options) error {
+	return New(opts).Install(dbManager)
+}
+
+// AddHandler 追加变更处理函数，须在开始处理请求前调用（如各业务模块初始化时），之后不能再修改
+func (r *Recorder) AddHandler(handler ChangeHandler) {
+	r.opts.Handlers = append(r.opts.Handlers, handler)
+}
+
+// Install 为所有数据库连接注册本插件
+func (r *Recorder) Install(dbManager database.Manager) error {
+	for _, name := range dbManager.Names() {
+		db, err := dbManager.DB(name)
+		if err != nil {
+			return err
+		}
+		if err := db.Use(r); err != nil {
+			return fmt.Errorf("数据库连接 '%s' 注册数据变更记录插件失败: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Name 插件名称
+func (r *Recorder) Name() string {
+	return "audit"
+}
+
+// Initialize 注册回调：写入前读取快照，写入后记录变更
+// 记录变更的回调须排在 GORM 默认事务提交之前，否则未显式开启事务时审计日志或处理函数失败无法回滚数据变更
+func (r *Recorder) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("audit:before_create", beforeCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:create").Before(commitCallback).Register("audit:create", r.afterCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("audit:before_update", beforeWrite); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Before(commitCallback).Register("audit:update", r.afterUpdate); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("audit:before_delete", beforeWrite); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Before(commitCallback).Register("audit:delete", r.afterDelete)
+}
+
+// auditEntity 返回语句对应模型的审计实体名称，模型未实现 Auditable 时返回 false
+func auditEntity(db *gorm.DB) (string, bool) {
+	if db.Error != nil || db.DryRun || db.Statement.Schema == nil {
+		return "", false
+	}
+	auditable, ok := reflect.New(db.Statement.Schema.ModelType).Interface().(model.Auditable)
+	if !ok {
+		return "", false
+	}
+	return auditable.AuditEntity(), true
+}
+
+// beforeCreate 带 ON CONFLICT 的创建：按冲突列读取已存在的记录
+func beforeCreate(db *gorm.DB) {
+	if _, ok := auditEntity(db); !ok {
+		return
+	}
+	condition, ok := conflictCondition(db)
+	if !ok {
+		return
+	}
+	snapshots, err := findSnapshots(db, func(tx *gorm.DB) *gorm.DB {
+		return tx.Scopes(database.WithDeleted).Where(condition)
+	})
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("读取审计快照失败: %w", err))
+		return
+	}
+	db.InstanceSet(snapshotsKey, snapshots)
+}
+
+// afterCreate 记录创建的变更
+func (r *Recorder) afterCreate(db *gorm.DB) {
+	entity, ok := auditEntity(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+
+	// 带 ON CONFLICT 的创建：重新读取，与写入前的快照比较
+	if condition, ok := conflictCondition(db); ok {
+		after, err := findSnapshots(db, func(tx *gorm.DB) *gorm.DB {
+			return tx.Scopes(database.WithDeleted).Where(condition)
+		})
+		if err != nil {
+			_ = db.AddError(fmt.Errorf("读取审计快照失败: %w", err))
+			return
+		}
+		r.record(db, entity, compareSnapshots(instanceSnapshots(db), after, model.ActionCreate))
+		return
+	}
+
+	var changes []change
+	var err error
+	eachRecord(db.Statement.ReflectValue, func(value reflect.Value) {
+		if err != nil {
+			return
+		}
+		var current snapshot
+		if current, err = newSnapshot(db, value); err == nil {
+			changes = append(changes, change{action: model.ActionCreate, after: &current})
+		}
+	})
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("生成审计快照失败: %w", err))
+		return
+	}
+	r.record(db, entity, changes)
+}
+
+// beforeWrite 更新、删除前按相同条件读取受影响记录的快照
+func beforeWrite(db *gorm.DB) {
+	if _, ok := auditEntity(db); !ok {
+		return
+	}
+	where, hasWhere := db.Statement.Clauses["WHERE"]
+	primaryConditions := modelPrimaryConditions(db)
+	// 没有条件的更新 / 删除会被 GORM 拒绝（ErrMissingWhereClause），不读取快照
+	if !hasWhere && len(primaryConditions) == 0 && !db.AllowGlobalUpdate {
+		return
+	}
+
+	snapshots, err := findSnapshots(db, func(tx *gorm.DB) *gorm.DB {
+		// 沿用语句的设置（如软删除查询范围）和条件
+		db.Statement.Settings.Range(func(key, value any) bool {
+			tx.Statement.Settings.Store(key, value)
+			return true
+		})
+		if hasWhere {
+			tx.Statement.Clauses["WHERE"] = where
+		}
+		for _, condition := range primaryConditions {
+			tx = tx.Where(condition)
+		}
+		return tx
+	})
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("读取审计快照失败: %w", err))
+		return
+	}
+	db.InstanceSet(snapshotsKey, snapshots)
+}
+
+// afterUpdate 按主键重新读取更新前快照中的记录，记录发生变化的记录
+func (r *Recorder) afterUpdate(db *gorm.DB) {
+	entity, ok := auditEntity(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+	before := instanceSnapshots(db)
+	if len(before) == 0 {
+		return
+	}
+
+	primary := db.Statement.Schema.PrioritizedPrimaryField
+	ids := make([]any, 0, len(before))
+	for _, item := range before {
+		ids = append(ids, item.id)
+	}
+	after, err := findSnapshots(db, func(tx *gorm.DB) *gorm.DB {
+		return tx.Scopes(database.WithDeleted).Where(clause.IN{
+			Column: clause.Column{Table: clause.CurrentTable, Name: primary.DBName},
+			Values: ids,
+		})
+	})
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("读取审计快照失败: %w", err))
+		return
+	}
+	r.record(db, entity, compareSnapshots(before, after, ""))
+}
+
+// afterDelete 记录物理删除的变更
+func (r *Recorder) afterDelete(db *gorm.DB) {
+	entity, ok := auditEntity(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+	before := instanceSnapshots(db)
+	changes := make([]change, 0, len(before))
+	for i := range before {
+		changes = append(changes, change{action: model.ActionDelete, before: &before[i]})
+	}
+	r.record(db, entity, changes)
+}
+
+// change 单条记录的变更（内部表示）
+type change struct {
+	action        string
+	before, after *snapshot
+}
+
+// compareSnapshots 比较写入前后的快照：前后都存在且内容变化的记录为更新（或软删除 / 恢复），
+// 只在写入后存在的记录按 created 记录（为空时忽略），内容未变化的记录不记录
+func compareSnapshots(before, after []snapshot, created string) []change {
+	beforeByID := make(map[string]*snapshot, len(before))
+	for i := range before {
+		beforeByID[fmt.Sprint(before[i].id)] = &before[i]
+	}
+
+	changes := make([]change, 0, len(after))
+	for i := range after {
+		current := &after[i]
+		previous, existed := beforeByID[fmt.Sprint(current.id)]
+		switch {
+		case !existed:
+			if created != "" {
+				changes = append(changes, change{action: created, after: current})
+			}
+		case !bytes.Equal(previous.data, current.data):
+			changes = append(changes, change{action: updateAction(*previous, *current), before: previous, after: current})
+		}
+	}
+	return changes
+}
+
+// record 在语句所在的连接（或事务）中写入审计日志并调用变更处理函数，失败时将错误加入语句，使数据变更随事务回滚
+func (r *Recorder) record(db *gorm.DB, entity string, changes []change) {
+	if len(changes) == 0 {
+		return
+	}
+	if r.opts.AuditLog {
+		logs := make([]*model.AuditLog, 0, len(changes))
+		for _, item := range changes {
+			logs = append(logs, newLog(db, entity, item))
+		}
+		// 审计日志的租户已取自记录本身（见 newLog），以跨租户访问写入，租户插件不再按上下文填充或拒绝
+		tx := db.Session(&gorm.Session{NewDB: true, Context: tenant.Elevate(db.Statement.Context)})
+		if err := tx.Create(&logs).Error; err != nil {
+			_ = db.AddError(fmt.Errorf("写入审计日志失败: %w", err))
+			return
+		}
+	}
+
+	if len(r.opts.Handlers) == 0 {
+		return
+	}
+	published := make([]Change, 0, len(changes))
+	for _, item := range changes {
+		published = append(published, item.publish(entity))
+	}
+	for _, handler := range r.opts.Handlers {
+		// 每个处理函数使用独立的新会话，沿用语句的连接（事务）和上下文，不带语句的条件
+		tx := db.Session(&gorm.Session{NewDB: true, Initialized: true})
+		if err := handler(tx, published); err != nil {
+			_ = db.AddError(fmt.Errorf("处理数据变更失败: %w", err))
+			return
+		}
+	}
+}
+
+// publish 转换为对外的变更结构
+func (c change) publish(entity string) Change {
+	published := Change{Entity: entity, Action: c.action}
+	if c.before != nil {
+		published.ID, published.Before = c.before.id, c.before.record()
+	}
+	if c.after != nil {
+		published.ID, published.After = c.after.id, c.after.record()
+	}
+	return published
+}
+
+// record 返回快照记录的副本（模型指针），避免处理函数修改语句中的数据
+func (s *snapshot) record() any {
+	copied := reflect.New(s.value.Type())
+	copied.Elem().Set(s.value)
+	return copied.Interface()
+}
+
+// updateAction 根据软删除标记的变化区分更新、软删除和恢复
+func updateAction(before, after snapshot) string {
+	switch {
+	case before.softDelete && !before.deleted && after.deleted:
+		return model.ActionSoftDelete
+	case before.softDelete && before.deleted && !after.deleted:
+		return model.ActionRestore
+	default:
+		return model.ActionUpdate
+	}
+}
+
+// findSnapshots 在语句所在的连接（或事务）中从主库读取记录快照，scope 设置查询条件
+func findSnapshots(db *gorm.DB, scope func(tx *gorm.DB) *gorm.DB) ([]snapshot, error) {
+	sch := db.Statement.Schema
+	rows := reflect.New(reflect.SliceOf(reflect.PointerTo(sch.ModelType)))
+	tx := db.Session(&gorm.Session{NewDB: true}).Model(reflect.New(sch.ModelType).Interface())
+	if err := scope(tx).Scopes(database.UsePrimary).Find(rows.Interface()).Error; err != nil {
+		return nil, err
+	}
+
+	list := rows.Elem()
+	snapshots := make([]snapshot, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		item, err := newSnapshot(db, reflect.Indirect(list.Index(i)))
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, item)
+	}
+	return snapshots, nil
+}
+
+// newSnapshot 生成单条记录的快照
+func newSnapshot(db *gorm.DB, value reflect.Value) (snapshot, error) {
+	sch := db.Statement.Schema
+	primary := sch.PrioritizedPrimaryField
+	if primary == nil {
+		return snapshot{}, fmt.Errorf("模型 %s 没有主键，不支持审计", sch.Name)
+	}
+
+	id, _ := primary.ValueOf(db.Statement.Context, value)
+	data, err := json.Marshal(value.Interface())
+	if err != nil {
+		return snapshot{}, err
+	}
+	item := snapshot{id: id, value: value, data: data}
+	if field := sch.LookUpField(tenant.Column); field != nil {
+		tenantID, _ := field.ValueOf(db.Statement.Context, value)
+		item.tenant = fmt.Sprint(tenantID)
+	}
+	if field := database.SoftDeleteField(sch); field != nil {
+		flag, _ := field.ValueOf(db.Statement.Context, value)
+		item.softDelete, item.deleted = true, database.IsSoftDeleted(flag)
+	}
+	return item, nil
+}
+
+// instanceSnapshots 读取写入前保存的快照
+func instanceSnapshots(db *gorm.DB) []snapshot {
+	value, ok := db.InstanceGet(snapshotsKey)
+	if !ok {
+		return nil
+	}
+	snapshots, _ := value.([]snapshot)
+	return snapshots
+}
+
+// modelPrimaryConditions 以 Model(&entity) 方式更新或删除时，GORM 在执行阶段才按模型主键追加条件，读取快照时需要同样追加
+func modelPrimaryConditions(db *gorm.DB) []clause.Expression {
+	sch := db.Statement.Schema
+	value := reflect.Indirect(reflect.ValueOf(db.Statement.Model))
+	if value.Kind() != reflect.Struct || value.Type() != sch.ModelType {
+		return nil
+	}
+
+	var conditions []clause.Expression
+	for _, field := range sch.PrimaryFields {
+		if id, zero := field.ValueOf(db.Statement.Context, value); !zero {
+			conditions = append(conditions, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: id})
+		}
+	}
+	return conditions
+}
+
+// conflictCondition 带 ON CONFLICT 且指定了冲突列的创建：按待写入记录的冲突列取值构建查询条件
+func conflictCondition(db *gorm.DB) (clause.Expression, bool) {
+	c, ok := db.Statement.Clauses["ON CONFLICT"]
+	if !ok {
+		return nil, false
+	}
+	onConflict, ok := c.Expression.(clause.OnConflict)
+	if !ok || len(onConflict.Columns) == 0 {
+		return nil, false
+	}
+
+	fields := make([]*schema.Field, 0, len(onConflict.Columns))
+	for _, column := range onConflict.Columns {
+		field := db.Statement.Schema.LookUpField(column.Name)
+		if field == nil {
+			return nil, false
+		}
+		fields = append(fields, field)
+	}
+
+	var ors []clause.Expression
+	eachRecord(db.Statement.ReflectValue, func(value reflect.Value) {
+		ands := make([]clause.Expression, 0, len(fields))
+		for _, field := range fields {
+			key, _ := field.ValueOf(db.Statement.Context, value)
+			ands = append(ands, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: key})
+		}
+		ors = append(ors, clause.And(ands...))
+	})
+	if len(ors) == 0 {
+		return nil, false
+	}
+	return clause.Or(ors...), true
+}
+
+// eachRecord 遍历语句中的记录（单条或切片）
+func eachRecord(value reflect.Value, fn func(value reflect.Value)) {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			fn(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		fn(value)
+	}
+}
+
+// newLog 创建审计日志，操作人、请求标识和客户端 IP 取自语句的上下文
+// 租户取自记录本身（跨租户访问修改的记录同样归属记录所在的租户），模型不包含 tenant_id 字段时取自上下文
+func newLog(db *gorm.DB, entity string, item change) *model.AuditLog {
+	ctx := db.Statement.Context
+	log := &model.AuditLog{
+		Entity:    entity,
+		Action:    item.action,
+		Actor:     utils.GetOperator(ctx),
+		RequestID: utils.GetRequestId(ctx),
+		ClientIP:  utils.GetClientIP(ctx),
+	}
+	if item.before != nil {
+		data := string(item.before.data)
+		log.EntityID, log.BeforeData = fmt.Sprint(item.before.id), &data
+	}
+	if item.after != nil {
+		data := string(item.after.data)
+		log.EntityID, log.AfterData = fmt.Sprint(item.after.id), &data
+	}
+	switch {
+	case item.after != nil && item.after.tenant != "":
+		log.TenantID = item.after.tenant
+	case item.before != nil && item.before.tenant != "":
+		log.TenantID = item.before.tenant
+	default:
+		log.TenantID, _ = tenant.FromContext(ctx)
+	}
+	return log
+}
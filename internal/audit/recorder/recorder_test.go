@@ -0,0 +1,204 @@
+package recorder
+
+import (
+	"context"
+	"errors"
+	"gin-template/internal/app/database"
+	"gin-template/internal/app/tenant"
+	"gin-template/internal/audit/model"
+	"gin-template/internal/audit/repository"
+	"gin-template/internal/utils"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// record 需要审计的测试模型
+type record struct {
+	ID        int        `json:"id"`
+	TenantID  string     `json:"tenant_id"`
+	Code      string     `json:"code" gorm:"uniqueIndex"`
+	Name      string     `json:"name"`
+	IsDeleted string     `json:"is_deleted" gorm:"default:'N'"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	DeletedBy *string    `json:"deleted_by"`
+}
+
+// AuditEntity 审计日志中的实体名称
+func (record) AuditEntity() string {
+	return "record"
+}
+
+// openTestDB 打开测试用的 SQLite 数据库（临时文件），注册指定插件并迁移模型
+func openTestDB(t *testing.T, plugins ...gorm.Plugin) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&record{}, &model.AuditLog{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, plugin := range plugins {
+		if err := db.Use(plugin); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// auditLogs 按写入顺序返回全部审计日志
+func auditLogs(t *testing.T, db *gorm.DB) []model.AuditLog {
+	t.Helper()
+	var logs []model.AuditLog
+	if err := db.WithContext(tenant.Elevate(context.Background())).Order("id").Find(&logs).Error; err != nil {
+		t.Fatal(err)
+	}
+	return logs
+}
+
+// actions 返回审计日志的操作类型
+func actions(logs []model.AuditLog) []string {
+	result := make([]string, 0, len(logs))
+	for _, log := range logs {
+		result = append(result, log.Action)
+	}
+	return result
+}
+
+func TestRecorderActions(t *testing.T) {
+	db := openTestDB(t, database.SoftDeletePlugin{}, New(Options{AuditLog: true}))
+	ctx := utils.WithOperator(context.Background(), "bob")
+
+	item := record{Code: "a", Name: "x"}
+	if err := db.WithContext(ctx).Create(&item).Error; err != nil {
+		t.Fatal(err)
+	}
+	steps := []func(tx *gorm.DB) error{
+		func(tx *gorm.DB) error { return tx.Model(&record{}).Where("id = ?", item.ID).Update("name", "y").Error },
+		// 取值未变化的更新不记录
+		func(tx *gorm.DB) error { return tx.Model(&record{}).Where("id = ?", item.ID).Update("name", "y").Error },
+		func(tx *gorm.DB) error {
+			return tx.Model(&record{}).Where("id = ?", item.ID).Updates(database.SoftDeleteUpdates(ctx)).Error
+		},
+		func(tx *gorm.DB) error {
+			return tx.Model(&record{}).Scopes(database.OnlyDeleted).Where("id = ?", item.ID).Updates(database.RestoreUpdates()).Error
+		},
+		// ON CONFLICT 的创建命中已存在的记录时记录为更新
+		func(tx *gorm.DB) error {
+			return tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code"}}, DoUpdates: clause.AssignmentColumns([]string{"name"})}).
+				Create(&record{Code: "a", Name: "z"}).Error
+		},
+		func(tx *gorm.DB) error { return tx.Delete(&record{}, item.ID).Error },
+	}
+	for _, step := range steps {
+		if err := step(db.WithContext(ctx)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logs := auditLogs(t, db)
+	want := []string{model.ActionCreate, model.ActionUpdate, model.ActionSoftDelete, model.ActionRestore, model.ActionUpdate, model.ActionDelete}
+	if got := actions(logs); !slices.Equal(got, want) {
+		t.Fatalf("操作类型 = %v, want %v", got, want)
+	}
+	for _, log := range logs {
+		if log.Entity != "record" || log.Actor != "bob" || log.EntityID == "" {
+			t.Errorf("审计日志 = %+v", log)
+		}
+	}
+	if created := logs[0]; created.BeforeData != nil || created.AfterData == nil {
+		t.Errorf("创建日志快照 = %v, %v", created.BeforeData, created.AfterData)
+	}
+	if deleted := logs[len(logs)-1]; deleted.BeforeData == nil || deleted.AfterData != nil {
+		t.Errorf("删除日志快照 = %v, %v", deleted.BeforeData, deleted.AfterData)
+	}
+}
+
+func TestRecorderHandlerRollback(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	var received []Change
+	recorder := New(Options{AuditLog: true})
+	recorder.AddHandler(func(tx *gorm.DB, changes []Change) error {
+		received = append(received, changes...)
+		if changes[0].Action == model.ActionUpdate {
+			return errHandler
+		}
+		return nil
+	})
+	db := openTestDB(t, recorder)
+
+	item := record{Code: "a", Name: "x"}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&item).Update("name", "y").Error; !errors.Is(err, errHandler) {
+		t.Fatalf("Update() error = %v, want %v", err, errHandler)
+	}
+
+	// 处理函数失败时数据变更和审计日志一起回滚
+	var current record
+	db.First(&current, item.ID)
+	if current.Name != "x" {
+		t.Errorf("Name = %q, want x", current.Name)
+	}
+	if got := actions(auditLogs(t, db)); !slices.Equal(got, []string{model.ActionCreate}) {
+		t.Errorf("操作类型 = %v", got)
+	}
+	if len(received) != 2 || received[0].After.(*record).Name != "x" || received[1].Before.(*record).Name != "x" {
+		t.Errorf("处理函数收到的变更 = %+v", received)
+	}
+}
+
+func TestRecorderTenant(t *testing.T) {
+	db := openTestDB(t, tenant.NewPlugin(tenant.ModeColumn, ""), New(Options{AuditLog: true}))
+	t1 := tenant.WithTenant(context.Background(), "t1")
+	t2 := tenant.WithTenant(context.Background(), "t2")
+
+	item := record{Code: "a", Name: "x"}
+	if err := db.WithContext(t1).Create(&item).Error; err != nil {
+		t.Fatal(err)
+	}
+	// 管理员跨租户修改的记录，审计日志仍归属记录所在的租户
+	if err := db.WithContext(tenant.Elevate(context.Background())).Model(&record{}).Where("id = ?", item.ID).Update("name", "y").Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, log := range auditLogs(t, db) {
+		if log.TenantID != "t1" {
+			t.Errorf("审计日志 %s 的租户 = %q, want t1", log.Action, log.TenantID)
+		}
+	}
+
+	repo := repository.NewAuditLogRepository(db)
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want int64
+	}{
+		{"本租户可以查询变更历史", t1, 2},
+		{"其他租户查询不到变更历史", t2, 0},
+		{"跨租户访问可以查询", tenant.Elevate(context.Background()), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, total, err := repo.ListEntityHistory(tt.ctx, "record", "1", 1, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != tt.want {
+				t.Errorf("total = %d, want %d", total, tt.want)
+			}
+		})
+	}
+}
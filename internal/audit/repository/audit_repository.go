@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"gin-template/internal/app/query"
+	baserepo "gin-template/internal/app/repository"
+	"gin-template/internal/audit/model"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository 审计日志数据访问接口
+type AuditLogRepository interface {
+	// ListAuditLogPage 分页查询审计日志，spec 为过滤和排序规格，未指定排序时按ID倒序
+	ListAuditLogPage(ctx context.Context, page, pageSize int, spec *query.Spec) ([]*model.AuditLog, int64, error)
+	// ListEntityHistory 分页查询指定实体记录的审计日志，按ID倒序
+	ListEntityHistory(ctx context.Context, entity, entityID string, page, pageSize int) ([]*model.AuditLog, int64, error)
+}
+
+// AuditLogRepositoryImpl 审计日志数据访问实现，嵌入泛型 BaseRepository
+type AuditLogRepositoryImpl struct {
+	*baserepo.BaseRepository[model.AuditLog]
+}
+
+// NewAuditLogRepository 创建审计日志数据访问实例
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &AuditLogRepositoryImpl{
+		BaseRepository: baserepo.NewBaseRepository[model.AuditLog](db, "audit_log"),
+	}
+}
+
+// ListAuditLogPage 分页查询审计日志
+func (repo *AuditLogRepositoryImpl) ListAuditLogPage(ctx context.Context, page, pageSize int, spec *query.Spec) ([]*model.AuditLog, int64, error) {
+	scopes := spec.Scopes()
+	if !spec.HasSort() {
+		scopes = append(scopes, baserepo.OrderBy("id", true))
+	}
+	return repo.FindPage(ctx, page, pageSize, scopes...)
+}
+
+// ListEntityHistory 分页查询指定实体记录的审计日志
+func (repo *AuditLogRepositoryImpl) ListEntityHistory(ctx context.Context, entity, entityID string, page, pageSize int) ([]*model.AuditLog, int64, error) {
+	return repo.FindPage(ctx, page, pageSize,
+		baserepo.Eq("entity", entity),
+		baserepo.Eq("entity_id", entityID),
+		baserepo.OrderBy("id", true),
+	)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"gin-template/internal/app/query"
+	"gin-template/internal/audit/dto"
+	"gin-template/internal/audit/model"
+	"gin-template/internal/audit/repository"
+	"slices"
+)
+
+// AuditService 审计日志服务接口
+type AuditService interface {
+	// ListAuditLogPage 分页查询审计日志，spec 为过滤和排序规格
+	ListAuditLogPage(ctx context.Context, page, pageSize int, spec *query.Spec) ([]*dto.AuditLogResponse, int64, error)
+	// ListEntityHistory 分页查询指定实体记录的变更历史，最新的变更在前
+	ListEntityHistory(ctx context.Context, entity, entityID string, page, pageSize int) ([]*dto.AuditLogResponse, int64, error)
+}
+
+// AuditServiceImpl 审计日志服务实现，持有审计日志数据访问层接口
+type AuditServiceImpl struct {
+	auditRepo repository.AuditLogRepository
+}
+
+// NewAuditService 创建审计日志服务实例
+func NewAuditService(auditRepo repository.AuditLogRepository) AuditService {
+	return &AuditServiceImpl{auditRepo: auditRepo}
+}
+
+// ListAuditLogPage 分页查询审计日志
+func (svc *AuditServiceImpl) ListAuditLogPage(ctx context.Context, page, pageSize int, spec *query.Spec) ([]*dto.AuditLogResponse, int64, error) {
+	logs, total, err := svc.auditRepo.ListAuditLogPage(ctx, page, pageSize, spec)
+	if err != nil {
+		return nil, 0, err
+	}
+	return toAuditLogResponses(logs), total, nil
+}
+
+// ListEntityHistory 分页查询指定实体记录的变更历史
+func (svc *AuditServiceImpl) ListEntityHistory(ctx context.Context, entity, entityID string, page, pageSize int) ([]*dto.AuditLogResponse, int64, error) {
+	logs, total, err := svc.auditRepo.ListEntityHistory(ctx, entity, entityID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return toAuditLogResponses(logs), total, nil
+}
+
+// toAuditLogResponses 转换为响应结构体（数据模型 -> 数据传输对象），更新类操作附带字段变更明细
+func toAuditLogResponses(logs []*model.AuditLog) []*dto.AuditLogResponse {
+	list := make([]*dto.AuditLogResponse, 0, len(logs))
+	for _, log := range logs {
+		resp := &dto.AuditLogResponse{
+			ID:         log.ID,
+			Entity:     log.Entity,
+			EntityID:   log.EntityID,
+			Action:     log.Action,
+			Before:     rawJSON(log.BeforeData),
+			After:      rawJSON(log.AfterData),
+			Actor:      log.Actor,
+			RequestID:  log.RequestID,
+			ClientIP:   log.ClientIP,
+			CreateTime: log.CreateTime,
+		}
+		if log.BeforeData != nil && log.AfterData != nil {
+			resp.Changes = fieldChanges(resp.Before, resp.After)
+		}
+		list = append(list, resp)
+	}
+	return list
+}
+
+// rawJSON 快照为空时返回 null
+func rawJSON(data *string) json.RawMessage {
+	if data == nil {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(*data)
+}
+
+// fieldChanges 比较前后快照，返回取值发生变化的字段；快照无法解析时返回 nil
+func fieldChanges(before, after json.RawMessage) map[string]dto.AuditFieldChange {
+	var from, to map[string]json.RawMessage
+	if json.Unmarshal(before, &from) != nil || json.Unmarshal(after, &to) != nil {
+		return nil
+	}
+
+	changes := make(map[string]dto.AuditFieldChange)
+	for field, value := range from {
+		if !slices.Equal(value, to[field]) {
+			changes[field] = dto.AuditFieldChange{From: value, To: nullIfMissing(to[field])}
+		}
+	}
+	for field, value := range to {
+		if _, ok := from[field]; !ok {
+			changes[field] = dto.AuditFieldChange{From: json.RawMessage("null"), To: value}
+		}
+	}
+	return changes
+}
+
+// nullIfMissing 字段不存在时返回 null
+func nullIfMissing(value json.RawMessage) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
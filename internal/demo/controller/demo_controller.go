@@ -1,12 +1,22 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
+	"gin-template/internal/app/export"
+	"gin-template/internal/app/importer"
+	"gin-template/internal/app/query"
+	baserepo "gin-template/internal/app/repository"
+	"gin-template/internal/app/search"
 	"gin-template/internal/demo/dto"
 	"gin-template/internal/demo/service"
 	"gin-template/internal/utils"
+	"io"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
 // DemoController 控制器，持有服务层接口实例
@@ -30,8 +40,14 @@ func (ctr *DemoController) ListDemo(ctx *gin.Context) {
 		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("参数绑定失败: %w", err)))
 		return
 	}
+	// 解析 filter / sort / fields 查询规格，字段须在白名单内
+	spec, err := query.Parse(ctx.Request.URL.Query(), dto.DemoQueryFields)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
 	// 调用服务层
-	demo, err := ctr.service.ListDemo(ctx, &req)
+	demo, err := ctr.service.ListDemo(ctx, &req, spec)
 
 	// 处理服务层返回的错误
 	if err != nil {
@@ -39,8 +55,15 @@ func (ctr *DemoController) ListDemo(ctx *gin.Context) {
 		return
 	}
 
+	// 按 fields 裁剪返回字段
+	data, err := query.Project(demo, spec.Fields)
+	if err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(err))
+		return
+	}
+
 	// 返回数据
-	utils.Success(ctx, "获取成功", demo)
+	utils.Success(ctx, "common.fetch_success", data)
 }
 
 // ListDemoPage 分页查询demo数据
@@ -64,8 +87,15 @@ func (ctr *DemoController) ListDemoPage(ctx *gin.Context) {
 		pageSize = 10
 	}
 
+	// 解析 filter / sort / fields 查询规格，字段须在白名单内
+	spec, err := query.Parse(ctx.Request.URL.Query(), dto.DemoQueryFields)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+
 	// 调用服务层
-	demo, total, err := ctr.service.ListDemoPage(ctx, page, pageSize)
+	demo, total, err := ctr.service.ListDemoPage(ctx, page, pageSize, spec)
 
 	// 处理服务层返回的错误
 	if err != nil {
@@ -73,8 +103,195 @@ func (ctr *DemoController) ListDemoPage(ctx *gin.Context) {
 		return
 	}
 
+	// 按 fields 裁剪返回字段
+	list, err := query.Project(demo, spec.Fields)
+	if err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(err))
+		return
+	}
+
 	// 返回数据
-	utils.SuccessPage(ctx, "获取成功", total, page, pageSize, demo)
+	utils.SuccessPage(ctx, "common.fetch_success", total, page, pageSize, list)
+}
+
+// SearchDemo 按关键词全文检索demo数据（field2），按相关度降序分页返回，prefix=true 时按前缀匹配
+func (ctr *DemoController) SearchDemo(ctx *gin.Context) {
+	// 初始化参数结构体并绑定查询参数
+	var req dto.DemoSearchRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("参数绑定失败: %w", err)))
+		return
+	}
+
+	// 处理分页参数默认值
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	// 调用服务层
+	list, total, err := ctr.service.SearchDemo(ctx, search.Query{Keyword: req.Q, Prefix: req.Prefix}, page, pageSize)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+
+	// 返回数据
+	utils.SuccessPage(ctx, "common.fetch_success", total, page, pageSize, list)
+}
+
+// StatsDemo 统计demo数据：group_by 指定分组字段（时间字段需指定粒度，如 create_time:month），metrics 指定聚合指标，支持与列表接口相同的 filter 参数
+func (ctr *DemoController) StatsDemo(ctx *gin.Context) {
+	// 解析统计规格，分组和聚合字段须在白名单内
+	agg, err := query.ParseAggregation(ctx.Request.URL.Query(), dto.DemoQueryFields)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+	// 解析 filter 过滤条件
+	spec, err := query.Parse(ctx.Request.URL.Query(), dto.DemoQueryFields)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+
+	// 调用服务层
+	data, err := ctr.service.StatsDemo(ctx, agg, spec)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+
+	// 返回数据
+	utils.Success(ctx, "common.fetch_success", data)
+}
+
+// ExportDemo 流式导出demo数据，format 指定格式（csv / xlsx / ndjson），支持与列表接口相同的过滤和排序参数，fields 选择导出的列
+func (ctr *DemoController) ExportDemo(ctx *gin.Context) {
+	// 初始化参数结构体并绑定查询参数
+	var req dto.DemoExportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("参数绑定失败: %w", err)))
+		return
+	}
+	// 解析 filter / sort / fields 查询规格，字段须在导出白名单内
+	spec, err := query.Parse(ctx.Request.URL.Query(), dto.DemoExportFields)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+	format := export.Format(req.Format)
+	if format == "" {
+		format = export.FormatCSV
+	}
+	columns := exportColumns(ctx, spec.Fields)
+
+	// 设置下载响应头，数据逐行写出，不在内存中保留全部结果
+	ctx.Header("Content-Type", export.ContentType(format))
+	ctx.Header("Content-Disposition", export.ContentDisposition("demo", format, time.Now()))
+	writer, err := export.NewWriter(format, ctx.Writer, columns)
+	if err == nil {
+		values := make([]any, len(columns))
+		// 查询使用随请求取消的上下文，客户端断开连接时查询随之取消
+		err = ctr.service.ExportDemo(utils.RequestContext(ctx), &req.DemoListRequest, spec, func(row *dto.DemoExportRow) error {
+			for i, column := range columns {
+				values[i] = row.Value(column.Key)
+			}
+			return writer.WriteRow(values)
+		})
+		if err == nil {
+			err = writer.Close()
+		}
+	}
+	if err != nil {
+		abortExport(ctx, err)
+	}
+}
+
+// exportColumns 按 fields 选择导出的列（未指定时导出全部默认列），表头按请求语言翻译
+func exportColumns(ctx *gin.Context, fields []string) []export.Column {
+	selected := dto.DemoExportColumns
+	if len(fields) > 0 {
+		selected = make([]export.Column, 0, len(fields))
+		for _, field := range fields {
+			for _, column := range dto.DemoExportColumns {
+				if column.Key == field {
+					selected = append(selected, column)
+				}
+			}
+		}
+	}
+	columns := make([]export.Column, len(selected))
+	for i, column := range selected {
+		columns[i] = export.Column{Key: column.Key, Header: utils.Translate(ctx, column.Header, nil)}
+	}
+	return columns
+}
+
+// abortExport 处理导出失败：尚未输出内容时按普通接口返回错误响应；
+// 已开始输出时无法再返回错误，记录日志后中断连接，避免客户端把不完整的文件当作完整结果
+func abortExport(ctx *gin.Context, err error) {
+	if !ctx.Writer.Written() {
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+
+	entry := logrus.WithField("requestId", utils.GetRequestId(ctx)).WithError(err)
+	if ctx.Request.Context().Err() != nil {
+		entry.Info("客户端已断开连接，导出已取消")
+	} else {
+		entry.Error("导出中途失败，已中断连接")
+	}
+	panic(http.ErrAbortHandler)
+}
+
+// ListDemoCursor 游标分页查询demo数据，支持与列表接口相同的 filter / sort / fields 参数
+func (ctr *DemoController) ListDemoCursor(ctx *gin.Context) {
+	// 绑定游标分页参数
+	var req dto.CursorQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("游标分页参数绑定失败: %w", err)))
+		return
+	}
+	// 解析 filter / sort / fields 查询规格，字段须在白名单内
+	spec, err := query.Parse(ctx.Request.URL.Query(), dto.DemoQueryFields)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+
+	// 调用服务层
+	page, err := ctr.service.ListDemoCursor(ctx, baserepo.CursorQuery{
+		Cursor: req.Cursor,
+		Limit:  req.Limit,
+		Sorts:  spec.Sorts,
+		Count:  baserepo.CountMode(req.Count),
+	}, spec)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+
+	// 按 fields 裁剪返回字段
+	list, err := query.Project(page.List, spec.Fields)
+	if err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(err))
+		return
+	}
+
+	// 返回数据
+	utils.SuccessCursorPage(ctx, "common.fetch_success", utils.CursorPageResponse{
+		List:      list,
+		Next:      page.Next,
+		Prev:      page.Prev,
+		Total:     page.Total,
+		Estimated: page.Estimated,
+	})
 }
 
 // GetDemoByID 根据ID获取demo数据
@@ -95,8 +312,11 @@ func (ctr *DemoController) GetDemoByID(ctx *gin.Context) {
 		return
 	}
 
+	// 版本号作为 ETag 返回，客户端更新、删除时通过 If-Match 携带
+	utils.SetVersionETag(ctx, demo.Version)
+
 	// 返回数据
-	utils.Success(ctx, "获取成功", demo)
+	utils.Success(ctx, "common.fetch_success", demo)
 }
 
 // CreateDemo 创建demo数据
@@ -114,7 +334,7 @@ func (ctr *DemoController) CreateDemo(ctx *gin.Context) {
 		return
 	}
 	// 返回数据
-	utils.Success(ctx, "创建成功", resp)
+	utils.Success(ctx, "common.create_success", resp)
 }
 
 // BatchCreateDemo 批量创建demo数据
@@ -125,14 +345,212 @@ func (ctr *DemoController) BatchCreateDemo(ctx *gin.Context) {
 		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("参数绑定失败: %w", err)))
 		return
 	}
+	// 绑定冲突策略和事务模式
+	var query dto.DemoBatchCreateQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("参数绑定失败: %w", err)))
+		return
+	}
+	// 调用服务层
+	resp, err := ctr.service.BatchCreateDemo(ctx, req, query)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+	// 返回数据，存在失败记录时通过消息区分部分成功和全部回滚
+	message := "common.batch_create_success"
+	switch {
+	case !resp.Committed:
+		message = "common.batch_rolled_back"
+	case len(resp.Failures) > 0:
+		message = "common.batch_partial_success"
+	}
+	utils.Success(ctx, message, resp)
+}
+
+// BatchUpdateDemo 批量更新demo数据
+func (ctr *DemoController) BatchUpdateDemo(ctx *gin.Context) {
+	// 初始化参数结构体并绑定请求体
+	var req dto.DemoBatchUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("参数绑定失败: %w", err)))
+		return
+	}
+	// 调用服务层
+	resp, err := ctr.service.BatchUpdateDemo(ctx, req)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+	// 返回数据，存在失败记录时通过消息区分部分成功和全部回滚
+	utils.Success(ctx, batchMessage(resp, "common.update_success"), resp)
+}
+
+// BatchDeleteDemo 批量删除demo数据
+func (ctr *DemoController) BatchDeleteDemo(ctx *gin.Context) {
+	// 初始化参数结构体并绑定请求体
+	var req dto.DemoBatchDeleteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("参数绑定失败: %w", err)))
+		return
+	}
 	// 调用服务层
-	err := ctr.service.BatchCreateDemo(ctx, req)
+	resp, err := ctr.service.BatchDeleteDemo(ctx, req)
 	if err != nil {
 		utils.HandlerFunc(ctx, err)
 		return
 	}
 	// 返回数据
-	utils.Success(ctx, "批量创建成功", nil)
+	utils.Success(ctx, batchMessage(resp, "common.delete_success"), resp)
+}
+
+// batchMessage 批量操作结果的提示信息
+func batchMessage(resp *dto.DemoBatchResponse, success string) string {
+	switch {
+	case !resp.Committed:
+		return "common.batch_rolled_back"
+	case resp.Failed > 0:
+		return "common.batch_partial_success"
+	default:
+		return success
+	}
+}
+
+// ImportDemo 导入 CSV / NDJSON 文件（multipart 表单字段 file）中的demo数据，每行按创建接口的规则校验
+// 同步执行时返回导入结果；转为异步任务时返回 202 和任务信息，Location 响应头为任务查询地址
+func (ctr *DemoController) ImportDemo(ctx *gin.Context) {
+	// 绑定导入选项
+	var req dto.DemoImportQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("参数绑定失败: %w", err)))
+		return
+	}
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.HandlerFunc(ctx, utils.NewBusinessErrorWithParams(utils.ErrCodePayloadTooLarge, "import.file_too_large", map[string]any{"max": maxBytesErr.Limit}))
+			return
+		}
+		utils.HandlerFunc(ctx, utils.WrapBusinessError(utils.ErrCodeParamInvalid, "import.file_required", err))
+		return
+	}
+	// 未指定格式时按文件扩展名判断
+	if req.Format == "" {
+		format, ok := importer.FormatOf(header.Filename)
+		if !ok {
+			utils.HandlerFunc(ctx, utils.NewBusinessError(utils.ErrCodeParamInvalid, "import.unsupported_format"))
+			return
+		}
+		req.Format = string(format)
+	}
+	file, err := header.Open()
+	if err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("打开上传文件失败: %w", err)))
+		return
+	}
+	defer file.Close()
+
+	// 调用服务层
+	resp, err := ctr.service.ImportDemo(ctx, file, header.Size, req)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+	if resp.Job != nil {
+		ctx.Header("Location", ctx.FullPath()+"/jobs/"+resp.Job.ID)
+		utils.SuccessAccepted(ctx, "import.accepted", dto.DemoImportResponse{Job: translateImportJob(ctx, resp.Job)})
+		return
+	}
+	result := translateImportResult(ctx, resp.Result)
+	utils.Success(ctx, importMessage(result), dto.DemoImportResponse{Result: result})
+}
+
+// GetDemoImportJob 查询异步导入任务的状态、进度和结果
+func (ctr *DemoController) GetDemoImportJob(ctx *gin.Context) {
+	// 从 URL 参数中提取任务ID
+	var req dto.DemoImportJobRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("任务ID绑定失败: %w", err)))
+		return
+	}
+	// 调用服务层
+	job, err := ctr.service.GetDemoImportJob(ctx, req.ID)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+	// 返回数据
+	utils.Success(ctx, "common.fetch_success", translateImportJob(ctx, job))
+}
+
+// importMessage 导入结果的提示信息
+func importMessage(result *dto.DemoImportResult) string {
+	switch {
+	case result.DryRun && result.Failed > 0:
+		return "import.check_failed"
+	case result.DryRun:
+		return "import.check_passed"
+	case result.Failed == 0:
+		return "import.success"
+	case result.Created+result.Updated+result.Skipped == 0:
+		return "common.batch_rolled_back"
+	default:
+		return "common.batch_partial_success"
+	}
+}
+
+// translateImportJob 按请求语言翻译导入任务中的错误描述，返回副本，任务中保存的结果保持不变
+func translateImportJob(ctx *gin.Context, job *dto.DemoImportJobResponse) *dto.DemoImportJobResponse {
+	translated := *job
+	translated.Result = translateImportResult(ctx, job.Result)
+	if job.Error != nil {
+		translated.Error = &dto.DemoImportJobError{Code: job.Error.Code, Message: utils.Translate(ctx, job.Error.Message, job.Error.Params)}
+	}
+	return &translated
+}
+
+// translateImportResult 按请求语言翻译导入结果中的错误描述，返回副本
+func translateImportResult(ctx *gin.Context, result *dto.DemoImportResult) *dto.DemoImportResult {
+	if result == nil {
+		return nil
+	}
+	translated := *result
+	translated.Errors = make([]dto.DemoImportError, len(result.Errors))
+	for i, e := range result.Errors {
+		e.Message = utils.Translate(ctx, e.Message, e.Params)
+		translated.Errors[i] = e
+	}
+	return &translated
+}
+
+// UpsertDemo 按 field1 创建或更新demo数据，新建返回 201，更新返回 200
+func (ctr *DemoController) UpsertDemo(ctx *gin.Context) {
+	// 从 URL 参数中提取字段一
+	var field1Req dto.DemoField1Request
+	if err := ctx.ShouldBindUri(&field1Req); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("字段一绑定失败: %w", err)))
+		return
+	}
+	// 初始化参数结构体并绑定请求体
+	var req dto.DemoUpsertRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("参数绑定失败: %w", err)))
+		return
+	}
+	// 调用服务层
+	resp, err := ctr.service.UpsertDemo(ctx, field1Req.Field1, req)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+	// 返回数据
+	utils.SetVersionETag(ctx, resp.Version)
+	if resp.Created {
+		utils.SuccessCreated(ctx, "common.create_success", resp)
+		return
+	}
+	utils.Success(ctx, "common.update_success", resp)
 }
 
 // UpdateDemo 更新demo数据
@@ -149,18 +567,80 @@ func (ctr *DemoController) UpdateDemo(ctx *gin.Context) {
 		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("参数绑定失败: %w", err)))
 		return
 	}
+	// 期望的版本号：请求头 If-Match 优先，其次为请求体中的 version
+	versions, ifMatch, ok := expectedVersions(ctx)
+	if !ok {
+		return
+	}
+	if !ifMatch && req.Version != nil {
+		versions = []int{*req.Version}
+	}
 	// 调用服务层
-	err := ctr.service.UpdateDemo(ctx, idReq.ID, req)
+	resp, err := ctr.service.UpdateDemo(ctx, idReq.ID, req, versions)
 	if err != nil {
-		utils.HandlerFunc(ctx, err)
+		utils.HandlerFunc(ctx, preconditionError(err, ifMatch))
+		return
+	}
+	// 返回数据
+	utils.SetVersionETag(ctx, resp.Version)
+	utils.Success(ctx, "common.update_success", resp)
+}
+
+// PatchDemo 局部更新demo数据，请求体为 JSON Merge Patch（application/merge-patch+json）或 JSON Patch（application/json-patch+json）
+func (ctr *DemoController) PatchDemo(ctx *gin.Context) {
+	// 从 URL 参数中提取 ID
+	var idReq dto.DemoIDRequest
+	if err := ctx.ShouldBindUri(&idReq); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("ID 绑定失败: %w", err)))
+		return
+	}
+	// 读取补丁，按 Content-Type 区分补丁格式
+	patch, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("读取请求体失败: %w", err)))
+		return
+	}
+	// 期望的版本号（If-Match）
+	versions, ifMatch, ok := expectedVersions(ctx)
+	if !ok {
+		return
+	}
+	// 调用服务层
+	resp, err := ctr.service.PatchDemo(ctx, idReq.ID, ctx.ContentType(), patch, versions)
+	if err != nil {
+		utils.HandlerFunc(ctx, preconditionError(err, ifMatch))
 		return
 	}
 	// 返回数据
-	utils.Success(ctx, "更新成功", nil)
+	utils.SetVersionETag(ctx, resp.Version)
+	utils.Success(ctx, "common.update_success", resp)
 }
 
 // SoftDeleteDemo 软删除demo数据
 func (ctr *DemoController) SoftDeleteDemo(ctx *gin.Context) {
+	// 从 URL 参数中提取 ID
+	var idReq dto.DemoIDRequest
+	if err := ctx.ShouldBindUri(&idReq); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("ID 绑定失败: %w", err)))
+		return
+	}
+	// 期望的版本号（If-Match）
+	versions, ifMatch, ok := expectedVersions(ctx)
+	if !ok {
+		return
+	}
+	// 调用服务层
+	err := ctr.service.SoftDeleteDemo(ctx, idReq.ID, versions)
+	if err != nil {
+		utils.HandlerFunc(ctx, preconditionError(err, ifMatch))
+		return
+	}
+	// 返回数据
+	utils.Success(ctx, "common.delete_success", nil)
+}
+
+// RestoreDemo 恢复已软删除的demo数据
+func (ctr *DemoController) RestoreDemo(ctx *gin.Context) {
 	// 从 URL 参数中提取 ID
 	var idReq dto.DemoIDRequest
 	if err := ctx.ShouldBindUri(&idReq); err != nil {
@@ -168,13 +648,13 @@ func (ctr *DemoController) SoftDeleteDemo(ctx *gin.Context) {
 		return
 	}
 	// 调用服务层
-	err := ctr.service.SoftDeleteDemo(ctx, idReq.ID)
+	err := ctr.service.RestoreDemo(ctx, idReq.ID)
 	if err != nil {
 		utils.HandlerFunc(ctx, err)
 		return
 	}
 	// 返回数据
-	utils.Success(ctx, "删除成功", nil)
+	utils.Success(ctx, "common.restore_success", nil)
 }
 
 // DeleteDemo 删除demo数据
@@ -185,12 +665,136 @@ func (ctr *DemoController) DeleteDemo(ctx *gin.Context) {
 		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("ID 绑定失败: %w", err)))
 		return
 	}
+	// 期望的版本号（If-Match）
+	versions, ifMatch, ok := expectedVersions(ctx)
+	if !ok {
+		return
+	}
+	// 调用服务层
+	err := ctr.service.DeleteDemo(ctx, idReq.ID, versions)
+	if err != nil {
+		utils.HandlerFunc(ctx, preconditionError(err, ifMatch))
+		return
+	}
+	// 返回数据
+	utils.Success(ctx, "common.delete_success", nil)
+}
+
+// ListDemoVersions 分页查询demo的历史版本
+func (ctr *DemoController) ListDemoVersions(ctx *gin.Context) {
+	// 从 URL 参数中提取 ID
+	var idReq dto.DemoIDRequest
+	if err := ctx.ShouldBindUri(&idReq); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("ID 绑定失败: %w", err)))
+		return
+	}
+	// 分页参数及默认值
+	var pageQuery dto.PageQueryRequest
+	if err := ctx.ShouldBindQuery(&pageQuery); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("分页参数绑定失败: %w", err)))
+		return
+	}
+	page, pageSize := pageQuery.Page, pageQuery.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	// 调用服务层
+	list, total, err := ctr.service.ListDemoVersions(ctx, idReq.ID, page, pageSize)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+	// 返回数据
+	utils.SuccessPage(ctx, "common.fetch_success", total, page, pageSize, list)
+}
+
+// GetDemoVersion 获取demo的指定版本
+func (ctr *DemoController) GetDemoVersion(ctx *gin.Context) {
+	// 从 URL 参数中提取 ID 和版本号
+	var versionReq dto.DemoVersionRequest
+	if err := ctx.ShouldBindUri(&versionReq); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("版本号绑定失败: %w", err)))
+		return
+	}
+	// 调用服务层
+	resp, err := ctr.service.GetDemoVersion(ctx, versionReq.ID, versionReq.Version)
+	if err != nil {
+		utils.HandlerFunc(ctx, err)
+		return
+	}
+	// 返回数据
+	utils.Success(ctx, "common.fetch_success", resp)
+}
+
+// DiffDemoVersions 比较demo两个版本的字段差异，版本号由查询参数 from / to 指定
+func (ctr *DemoController) DiffDemoVersions(ctx *gin.Context) {
+	// 从 URL 参数中提取 ID
+	var idReq dto.DemoIDRequest
+	if err := ctx.ShouldBindUri(&idReq); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("ID 绑定失败: %w", err)))
+		return
+	}
+	// 绑定比较的版本号
+	var req dto.DemoVersionDiffRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("参数绑定失败: %w", err)))
+		return
+	}
 	// 调用服务层
-	err := ctr.service.DeleteDemo(ctx, idReq.ID)
+	resp, err := ctr.service.DiffDemoVersions(ctx, idReq.ID, req)
 	if err != nil {
 		utils.HandlerFunc(ctx, err)
 		return
 	}
 	// 返回数据
-	utils.Success(ctx, "删除成功", nil)
+	utils.Success(ctx, "common.fetch_success", resp)
+}
+
+// RestoreDemoVersion 将demo数据回滚到指定版本，支持 If-Match 校验当前版本号
+func (ctr *DemoController) RestoreDemoVersion(ctx *gin.Context) {
+	// 从 URL 参数中提取 ID 和版本号
+	var versionReq dto.DemoVersionRequest
+	if err := ctx.ShouldBindUri(&versionReq); err != nil {
+		utils.HandlerFunc(ctx, utils.NewSystemError(fmt.Errorf("版本号绑定失败: %w", err)))
+		return
+	}
+	// 期望的版本号（If-Match）
+	versions, ifMatch, ok := expectedVersions(ctx)
+	if !ok {
+		return
+	}
+	// 调用服务层
+	resp, err := ctr.service.RestoreDemoVersion(ctx, versionReq.ID, versionReq.Version, versions)
+	if err != nil {
+		utils.HandlerFunc(ctx, preconditionError(err, ifMatch))
+		return
+	}
+	// 返回数据
+	utils.SetVersionETag(ctx, resp.Version)
+	utils.Success(ctx, "common.restore_success", resp)
+}
+
+// expectedVersions 解析 If-Match 请求头中的期望版本号
+// 返回值：(期望版本号, 是否携带 If-Match, 是否继续处理)；If-Match 不可能匹配任何版本时直接响应 412
+func expectedVersions(ctx *gin.Context) ([]int, bool, bool) {
+	versions, anyVersion, present := utils.IfMatchVersions(ctx)
+	if !present || anyVersion {
+		return nil, present, true
+	}
+	if len(versions) == 0 {
+		utils.HandlerFunc(ctx, utils.NewBusinessError(utils.ErrCodePreconditionFailed, "error.precondition_failed"))
+		return nil, true, false
+	}
+	return versions, true, true
+}
+
+// preconditionError 携带 If-Match 时，版本冲突按 HTTP 条件请求语义返回 412，否则保持 409
+func preconditionError(err error, ifMatch bool) error {
+	if bizErr, ok := utils.GetBusinessError(err); ok && ifMatch && bizErr.Code == utils.ErrCodeVersionConflict {
+		return utils.WrapBusinessError(utils.ErrCodePreconditionFailed, "error.precondition_failed", err)
+	}
+	return err
 }
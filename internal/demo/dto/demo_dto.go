@@ -1,9 +1,26 @@
 package dto
 
+import (
+	"gin-template/internal/app/export"
+	"gin-template/internal/app/query"
+	"time"
+)
+
+// DemoQueryFields demo列表查询字段白名单，限定 filter / sort / fields 参数可使用的字段
+var DemoQueryFields = query.Allowlist{
+	"id":          {Column: "id", Type: query.Int, Filterable: true, Sortable: true, Selectable: true},
+	"field1":      {Column: "field1", Type: query.Int, Filterable: true, Sortable: true, Selectable: true, Groupable: true, Aggregable: true},
+	"field2":      {Column: "field2", Type: query.String, Filterable: true, Sortable: true, Selectable: true, Groupable: true},
+	"create_time": {Column: "create_time", Type: query.Time, Filterable: true, Sortable: true, Groupable: true},
+	"update_time": {Column: "update_time", Type: query.Time, Filterable: true, Sortable: true, Groupable: true},
+}
+
 // DemoListRequest demo请求查询参数结构体
+// 除以下等值参数外，还支持 filter / sort / fields 查询规格参数，字段范围见 DemoQueryFields
 type DemoListRequest struct {
-	Field1 int    `form:"field1"`
-	Field2 string `form:"field2"`
+	Field1  int    `form:"field1"`
+	Field2  string `form:"field2"`
+	Deleted string `form:"deleted" binding:"omitempty,oneof=with only"` // 软删除数据查询范围：空=仅未删除，with=包含已删除，only=仅已删除
 }
 
 // DemoListResponse demo响应结构体
@@ -19,6 +36,45 @@ type PageQueryRequest struct {
 	PageSize int `form:"pageSize"`
 }
 
+// CursorQueryRequest 游标分页查询参数
+type CursorQueryRequest struct {
+	Cursor string `form:"cursor"`                                              // 上一次响应返回的 next / prev 游标，为空查询第一页
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`             // 每页条数，默认 10
+	Count  string `form:"count" binding:"omitempty,oneof=none exact estimate"` // 总条数统计方式：none（默认，不统计）、exact（精确）、estimate（估算）
+}
+
+// DemoSearchRequest 全文检索查询参数
+type DemoSearchRequest struct {
+	Q        string `form:"q" binding:"required,max=100"` // 关键词，按空白和标点切分为多个词，须全部匹配
+	Prefix   bool   `form:"prefix"`                       // 前缀匹配：每个词匹配以其开头的词
+	Page     int    `form:"page"`
+	PageSize int    `form:"pageSize" binding:"omitempty,max=100"`
+}
+
+// DemoSearchResponse 全文检索结果
+type DemoSearchResponse struct {
+	ID        int               `json:"id"`
+	Field1    int               `json:"field1"`
+	Field2    string            `json:"field2"`
+	Score     float64           `json:"score"`     // 相关度，数值越大越相关，仅用于同一次检索结果间比较
+	Highlight map[string]string `json:"highlight"` // 字段名 -> 用 <mark> 标记检索词的 HTML 片段（已转义）
+}
+
+// DemoStatsResponse 统计结果
+type DemoStatsResponse struct {
+	GroupBy     []string       `json:"group_by"`     // 分组字段，时间字段带粒度（如 create_time:month）
+	Metrics     []string       `json:"metrics"`      // 指标名（count、sum_field1 等）
+	Rows        []DemoStatsRow `json:"rows"`         // 按分组值升序排列，未分组时只有一行
+	Truncated   bool           `json:"truncated"`    // 分组数超过上限，只返回了部分分组
+	GeneratedAt time.Time      `json:"generated_at"` // 统计时间，命中缓存时为缓存结果的统计时间
+}
+
+// DemoStatsRow 统计结果中的一行
+type DemoStatsRow struct {
+	Keys   map[string]any `json:"keys"`   // 分组字段 -> 分组值，时间分组为区间起始日期
+	Values map[string]any `json:"values"` // 指标名 -> 值，没有可聚合的值时为 null
+}
+
 // DemoPageResponse 分页查询响应
 type DemoPageListResponse struct {
 	ID     int    `json:"id"`
@@ -31,17 +87,60 @@ type DemoIDRequest struct {
 	ID int `uri:"id"`
 }
 
-// DemoDetailResponse 详情查询响应
-type DemoDetailResponse struct {
-	ID     int    `json:"id"`
-	Field1 int    `json:"field1"`
+// DemoField1Request 按字段一定位数据的路径参数
+type DemoField1Request struct {
+	Field1 int `uri:"field1"`
+}
+
+// DemoUpsertRequest demo创建或更新请求参数结构体（field1 取自路径）
+type DemoUpsertRequest struct {
 	Field2 string `json:"field2"`
 }
 
-// DemoCreateRequest demo创建请求参数结构体
+// DemoUpsertResponse demo创建或更新响应结构体
+type DemoUpsertResponse struct {
+	ID      int  `json:"id"`
+	Created bool `json:"created"` // true 为新建，false 为更新已有数据
+	Version int  `json:"version"` // 当前版本号，同时通过 ETag 响应头返回
+}
+
+// DemoDetailResponse 详情查询响应，时间按 RFC 3339 输出（带时区偏移，如 2024-01-01T08:00:00Z）
+type DemoDetailResponse struct {
+	ID         int        `json:"id"`
+	Field1     int        `json:"field1"`
+	Field2     string     `json:"field2"`
+	Version    int        `json:"version"` // 版本号，同时通过 ETag 响应头返回
+	CreateTime *time.Time `json:"create_time"`
+	UpdateTime *time.Time `json:"update_time"`
+	CreatedBy  *string    `json:"created_by"` // 创建人
+	UpdatedBy  *string    `json:"updated_by"` // 最后更新人
+}
+
+// DemoCreateRequest demo创建请求参数结构体，导入文件中的每行数据按同一规则校验
 type DemoCreateRequest struct {
 	Field1 int    `json:"field1"`
-	Field2 string `json:"field2"`
+	Field2 string `json:"field2" binding:"max=255"`
+}
+
+// DemoBatchCreateQuery demo批量创建查询参数
+type DemoBatchCreateQuery struct {
+	OnConflict    string `form:"on_conflict" binding:"omitempty,oneof=fail skip update"` // field1 已存在时的处理策略：fail（默认，记为失败）、skip（跳过）、update（更新 field2）
+	Transactional bool   `form:"transactional"`                                          // true 时全部成功或全部回滚，默认各批次独立提交
+}
+
+// DemoBatchCreateFailure 批量创建中失败的记录
+type DemoBatchCreateFailure struct {
+	Index int `json:"index"` // 在请求数组中的下标
+	Code  int `json:"code"`  // 业务错误码
+}
+
+// DemoBatchCreateResponse demo批量创建响应结构体
+type DemoBatchCreateResponse struct {
+	Committed bool                     `json:"committed"`         // 是否已提交；事务模式下存在失败记录时为 false，全部回滚
+	Created   []int                    `json:"created"`           // 新建记录的ID，顺序与请求一致
+	Updated   []int                    `json:"updated,omitempty"` // update 策略下已存在并被更新的记录ID
+	Skipped   []int                    `json:"skipped,omitempty"` // skip 策略下跳过的记录在请求数组中的下标
+	Failures  []DemoBatchCreateFailure `json:"failures"`          // 失败的记录
 }
 
 // DemoCreateResponse demo创建响应结构体
@@ -51,6 +150,238 @@ type DemoCreateResponse struct {
 
 // DemoUpdateRequest demo更新请求参数结构体
 type DemoUpdateRequest struct {
+	Field1  *int    `json:"field1"`
+	Field2  *string `json:"field2"`
+	Version *int    `json:"version"` // 期望的当前版本号（可选），不一致时返回版本冲突；请求头 If-Match 优先
+}
+
+// DemoPatchFields demo局部更新允许修改的字段，JSON 字段名 -> 数据库列
+var DemoPatchFields = map[string]string{
+	"field1": "field1",
+	"field2": "field2",
+}
+
+// DemoPatchDocument demo局部更新的目标文档：补丁作用于由当前数据生成的该文档，结果按字段规则校验
+// field2 为 null 或被移除表示将该列置为 NULL；field1 为唯一键，不能为 null 或被移除
+type DemoPatchDocument struct {
+	Field1 *int    `json:"field1"`
+	Field2 *string `json:"field2" binding:"omitempty,max=255"`
+}
+
+// DemoUpdateResponse demo更新响应结构体
+type DemoUpdateResponse struct {
+	Version int `json:"version"` // 更新后的版本号
+}
+
+// DemoBatchItem 批量操作的目标记录
+type DemoBatchItem struct {
+	ID      int  `json:"id" binding:"required"`
+	Version *int `json:"version"` // 期望的当前版本号（可选），不一致时该条记录返回 conflict
+}
+
+// DemoBatchSelector 批量操作的目标选择，ids / items 与 filter 必须且只能指定一种
+type DemoBatchSelector struct {
+	IDs    []int                     `json:"ids"`                                               // 按ID指定
+	Items  []DemoBatchItem           `json:"items" binding:"dive"`                              // 按ID指定并携带期望版本号
+	Filter map[string]map[string]any `json:"filter"`                                            // 按过滤条件选择，如 {"field1": {"gte": 3}}，字段范围见 DemoQueryFields
+	Mode   string                    `json:"mode" binding:"omitempty,oneof=atomic best_effort"` // atomic（默认）：任一记录失败则全部回滚；best_effort：失败的记录单独回滚，其余提交
+}
+
+// DemoBatchUpdateRequest demo批量更新请求参数结构体
+type DemoBatchUpdateRequest struct {
+	DemoBatchSelector
 	Field1 *int    `json:"field1"`
 	Field2 *string `json:"field2"`
 }
+
+// DemoBatchDeleteRequest demo批量删除请求参数结构体
+type DemoBatchDeleteRequest struct {
+	DemoBatchSelector
+	Hard bool `json:"hard"` // true 为物理删除，默认软删除
+}
+
+// DemoBatchItemResult 批量操作中单条记录的处理结果
+type DemoBatchItemResult struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`         // updated / deleted / not_found / conflict / failed / rolled_back
+	Code   int    `json:"code,omitempty"` // 失败时的业务错误码
+}
+
+// DemoBatchResponse demo批量更新 / 删除响应结构体
+type DemoBatchResponse struct {
+	Committed bool                  `json:"committed"` // 是否已提交；atomic 模式下存在失败记录时为 false，全部回滚
+	Succeeded int                   `json:"succeeded"` // 成功（已提交）的记录数
+	Failed    int                   `json:"failed"`    // 失败的记录数
+	Results   []DemoBatchItemResult `json:"results"`   // 各记录的处理结果，顺序与请求一致
+}
+
+// DemoVersionRequest 历史版本路径参数
+type DemoVersionRequest struct {
+	ID      int `uri:"id"`
+	Version int `uri:"v"`
+}
+
+// DemoVersionDiffRequest 版本比较查询参数
+type DemoVersionDiffRequest struct {
+	From int `form:"from" binding:"required,min=1"` // 比较的起始版本号
+	To   int `form:"to" binding:"required,min=1"`   // 比较的目标版本号
+}
+
+// DemoVersionResponse 历史版本响应
+type DemoVersionResponse struct {
+	Version    int        `json:"version"`
+	Field1     int        `json:"field1"`
+	Field2     string     `json:"field2"`
+	Deleted    bool       `json:"deleted"`  // 该版本是否为已软删除状态
+	Operator   string     `json:"operator"` // 产生该版本的操作人
+	CreateTime *time.Time `json:"create_time"`
+}
+
+// DemoFieldChange 字段在两个版本间的变化
+type DemoFieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// DemoVersionDiffResponse 版本比较响应，changes 只包含取值不同的字段
+type DemoVersionDiffResponse struct {
+	From    int                        `json:"from"`
+	To      int                        `json:"to"`
+	Changes map[string]DemoFieldChange `json:"changes"`
+}
+
+// DemoExportFields demo导出字段白名单：filter / sort 与列表接口相同，fields 选择导出的列（顺序即列顺序）
+var DemoExportFields = query.Allowlist{
+	"id":          {Column: "id", Type: query.Int, Filterable: true, Sortable: true, Selectable: true},
+	"field1":      {Column: "field1", Type: query.Int, Filterable: true, Sortable: true, Selectable: true},
+	"field2":      {Column: "field2", Type: query.String, Filterable: true, Sortable: true, Selectable: true},
+	"version":     {Column: "version", Type: query.Int, Selectable: true},
+	"create_time": {Column: "create_time", Type: query.Time, Filterable: true, Sortable: true, Selectable: true},
+	"update_time": {Column: "update_time", Type: query.Time, Filterable: true, Sortable: true, Selectable: true},
+	"created_by":  {Column: "created_by", Type: query.String, Filterable: true, Selectable: true},
+	"updated_by":  {Column: "updated_by", Type: query.String, Filterable: true, Selectable: true},
+}
+
+// DemoExportColumns demo导出的默认列及顺序，Header 为表头的消息键，按请求语言翻译
+var DemoExportColumns = []export.Column{
+	{Key: "id", Header: "demo.export.id"},
+	{Key: "field1", Header: "demo.export.field1"},
+	{Key: "field2", Header: "demo.export.field2"},
+	{Key: "version", Header: "demo.export.version"},
+	{Key: "create_time", Header: "demo.export.create_time"},
+	{Key: "update_time", Header: "demo.export.update_time"},
+	{Key: "created_by", Header: "demo.export.created_by"},
+	{Key: "updated_by", Header: "demo.export.updated_by"},
+}
+
+// DemoExportRequest demo导出查询参数，过滤参数与列表接口相同
+type DemoExportRequest struct {
+	DemoListRequest
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx ndjson"` // 导出格式：csv（默认）、xlsx、ndjson
+}
+
+// DemoExportRow demo导出的单行数据
+type DemoExportRow struct {
+	ID         int
+	Field1     int
+	Field2     string
+	Version    int
+	CreateTime *time.Time
+	UpdateTime *time.Time
+	CreatedBy  *string
+	UpdatedBy  *string
+}
+
+// Value 返回导出列的取值，key 为 DemoExportColumns 中的列标识
+func (r *DemoExportRow) Value(key string) any {
+	switch key {
+	case "id":
+		return r.ID
+	case "field1":
+		return r.Field1
+	case "field2":
+		return r.Field2
+	case "version":
+		return r.Version
+	case "create_time":
+		return r.CreateTime
+	case "update_time":
+		return r.UpdateTime
+	case "created_by":
+		return r.CreatedBy
+	case "updated_by":
+		return r.UpdatedBy
+	default:
+		return nil
+	}
+}
+
+// DemoImportQuery demo导入查询参数
+type DemoImportQuery struct {
+	Format      string `form:"format" binding:"omitempty,oneof=csv ndjson"`            // 文件格式，为空时按文件扩展名判断（.csv / .ndjson / .jsonl / .json）
+	DryRun      bool   `form:"dry_run"`                                                // true 时只校验，不写入数据
+	OnConflict  string `form:"on_conflict" binding:"omitempty,oneof=fail skip update"` // field1 已存在时的处理策略，与批量创建相同
+	Transaction string `form:"transaction" binding:"omitempty,oneof=chunk file"`       // 事务边界：chunk（默认，每 import.commit_size 行一个事务）、file（整个文件一个事务）
+	Async       bool   `form:"async"`                                                  // true 时总是作为异步任务执行，否则文件超过 import.async_threshold 时转为异步
+}
+
+// DemoImportError 导入失败的行，同一行有多个字段校验失败时每个字段一条
+type DemoImportError struct {
+	Line    int            `json:"line"`            // 行号，CSV 含表头行
+	Field   string         `json:"field,omitempty"` // 出错的字段，无法定位到字段时为空
+	Code    int            `json:"code"`            // 业务错误码
+	Message string         `json:"message"`         // 错误描述（消息键，响应时按请求语言翻译）
+	Params  map[string]any `json:"-"`               // 消息模板参数
+}
+
+// DemoImportResult demo导入结果
+type DemoImportResult struct {
+	DryRun          bool              `json:"dry_run"`
+	Total           int               `json:"total"`            // 数据行数（不含表头和空行）
+	Valid           int               `json:"valid"`            // 格式和校验规则检查通过的行数
+	Failed          int               `json:"failed"`           // 检查或写入失败的行数
+	Created         int               `json:"created"`          // 已提交的新建行数
+	Updated         int               `json:"updated"`          // update 策略下已提交的更新行数
+	Skipped         int               `json:"skipped"`          // skip 策略下跳过的行数
+	RolledBack      int               `json:"rolled_back"`      // 检查通过，但所在事务存在失败的行而未写入或已回滚的行数
+	Errors          []DemoImportError `json:"errors"`           // 失败的行，最多返回 import.max_errors 条
+	ErrorsTruncated bool              `json:"errors_truncated"` // 失败的行超过上限，errors 未包含全部失败的行
+}
+
+// DemoImportProgress 异步导入进度
+type DemoImportProgress struct {
+	Rows       int     `json:"rows"`        // 已处理的数据行数
+	BytesRead  int64   `json:"bytes_read"`  // 已读取的字节数
+	TotalBytes int64   `json:"total_bytes"` // 文件大小
+	Percent    float64 `json:"percent"`     // 完成百分比，按已读取的字节数计算
+}
+
+// DemoImportJobError 异步导入任务失败的原因
+type DemoImportJobError struct {
+	Code    int            `json:"code"`    // 业务错误码
+	Message string         `json:"message"` // 错误描述（消息键，响应时按请求语言翻译）
+	Params  map[string]any `json:"-"`       // 消息模板参数
+}
+
+// DemoImportJobResponse 异步导入任务
+type DemoImportJobResponse struct {
+	ID         string              `json:"id"`
+	Status     string              `json:"status"` // pending（排队中）、running（执行中）、succeeded（已完成）、failed（失败）
+	Progress   DemoImportProgress  `json:"progress"`
+	Result     *DemoImportResult   `json:"result,omitempty"` // 执行结束后返回；failed 时为失败前已处理的部分
+	Error      *DemoImportJobError `json:"error,omitempty"`  // 任务失败的原因（如文件格式无效）
+	CreateTime time.Time           `json:"create_time"`
+	StartTime  *time.Time          `json:"start_time"`
+	FinishTime *time.Time          `json:"finish_time"`
+}
+
+// DemoImportResponse demo导入响应，同步执行时返回 result，转为异步任务时返回 job
+type DemoImportResponse struct {
+	Result *DemoImportResult      `json:"result,omitempty"`
+	Job    *DemoImportJobResponse `json:"job,omitempty"`
+}
+
+// DemoImportJobRequest 导入任务路径参数
+type DemoImportJobRequest struct {
+	ID string `uri:"id"`
+}
@@ -1,20 +1,39 @@
 package model
 
 import (
+	basemodel "gin-template/internal/app/model"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Demo 数据模型
 type Demo struct {
-	ID         int        `json:"id" gorm:"primaryKey;autoIncrement;column:id"`
-	Field1     int        `json:"field1" gorm:"column:field1"`
-	Field2     string     `json:"field2" gorm:"type:varchar(255);column:field2"`
-	IsDeleted  string     `json:"is_deleted" gorm:"column:is_deleted;default:'N'"`
-	CreateTime *time.Time `json:"create_time" gorm:"column:create_time;autoCreateTime"`
-	UpdateTime *time.Time `json:"update_time" gorm:"column:update_time;autoUpdateTime"`
+	ID        int        `json:"id" gorm:"primaryKey;autoIncrement;column:id"`
+	TenantID  string     `json:"tenant_id" gorm:"type:varchar(64);column:tenant_id;not null;default:'';index;uniqueIndex:uk_demo_field1,priority:2"` // 所属租户，多租户 column 模式下自动填充和过滤，未启用时为空
+	Field1    int        `json:"field1" gorm:"column:field1;uniqueIndex:uk_demo_field1,priority:1"`                                                  // 唯一键（租户内唯一），按唯一键创建或更新、批量创建的冲突判断依赖该唯一索引
+	Field2    string     `json:"field2" gorm:"type:varchar(255);column:field2;index:ft_demo_field2,class:FULLTEXT,option:WITH PARSER ngram"`         // 全文检索列，ngram 分词器支持中文
+	IsDeleted string     `json:"is_deleted" gorm:"column:is_deleted;default:'N'"`
+	DeletedAt *time.Time `json:"deleted_at" gorm:"column:deleted_at;index"`        // 软删除时间，恢复时清空
+	Version   int        `json:"version" gorm:"column:version;not null;default:1"` // 乐观锁版本号，每次更新自增
+
+	basemodel.BaseModel // 创建 / 更新时间及创建人、更新人、删除人
 }
 
 // TableName 指定表名
 func (*Demo) TableName() string {
 	return "demo"
 }
+
+// BeforeCreate 新建数据的版本号从 1 开始
+func (d *Demo) BeforeCreate(*gorm.DB) error {
+	if d.Version == 0 {
+		d.Version = 1
+	}
+	return nil
+}
+
+// AuditEntity 审计日志中的实体名称，启用审计日志时记录demo数据的变更
+func (*Demo) AuditEntity() string {
+	return "demo"
+}
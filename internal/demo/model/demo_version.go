@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// DemoVersion demo历史版本数据模型，每个版本保存一次变更后的完整数据
+// 版本号与 Demo.Version 一致，(demo_id, version) 唯一
+type DemoVersion struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement;column:id"`
+	DemoID     int        `json:"demo_id" gorm:"column:demo_id;not null;uniqueIndex:uk_demo_version,priority:1"`
+	Version    int        `json:"version" gorm:"column:version;not null;uniqueIndex:uk_demo_version,priority:2"`
+	Field1     int        `json:"field1" gorm:"column:field1"`
+	Field2     string     `json:"field2" gorm:"type:varchar(255);column:field2"`
+	Deleted    string     `json:"deleted" gorm:"type:char(1);column:deleted;not null;default:'N'"` // 该版本是否为已软删除状态（Y/N），列名避开软删除标记 is_deleted
+	Operator   string     `json:"operator" gorm:"type:varchar(64);column:operator"`                // 产生该版本的操作人
+	CreateTime *time.Time `json:"create_time" gorm:"column:create_time;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (*DemoVersion) TableName() string {
+	return "demo_versions"
+}
@@ -0,0 +1,165 @@
+// Package demo 示例模块：demo 数据的增删改查、批量操作、导入导出、全文检索、统计及历史版本
+package demo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-template/internal/app/config"
+	"gin-template/internal/app/database"
+	"gin-template/internal/app/jobs"
+	"gin-template/internal/app/middleware"
+	"gin-template/internal/app/module"
+	"gin-template/internal/app/query"
+	"gin-template/internal/audit"
+	"gin-template/internal/demo/controller"
+	"gin-template/internal/demo/model"
+	"gin-template/internal/demo/repository"
+	"gin-template/internal/demo/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Module demo 模块，可选依赖审计日志模块：由其提供变更历史接口和历史版本使用的数据变更记录插件
+type Module struct {
+	cfg        *config.Config
+	db         *gorm.DB
+	audit      *audit.Module // 审计日志模块，未启用时为 nil
+	versions   bool          // 是否启用历史版本，未启用审计日志模块时停用
+	importJobs jobs.Manager  // 异步导入任务
+	controller *controller.DemoController
+}
+
+// NewModule 创建 demo 模块
+func NewModule() *Module {
+	return &Module{}
+}
+
+// Name 模块名称
+func (m *Module) Name() string {
+	return "demo"
+}
+
+// DependsOn 不依赖其他模块
+func (m *Module) DependsOn() []string {
+	return nil
+}
+
+// OptionalDependsOn 可选依赖审计日志模块，未启用时不提供变更历史接口和历史版本
+func (m *Module) OptionalDependsOn() []string {
+	return []string{audit.Name}
+}
+
+// Init 初始化依赖：使用默认数据库连接；启用历史版本时向数据变更记录插件注册保存历史版本的处理函数
+func (m *Module) Init(deps *module.Deps) error {
+	m.cfg = deps.Config
+	m.versions = m.cfg.Versions.Enabled
+	if auditModule, ok := deps.Module(audit.Name); ok {
+		m.audit = auditModule.(*audit.Module)
+	} else if m.versions {
+		logrus.Warnf("模块 '%s' 未启用，历史版本依赖其数据变更记录插件，已停用历史版本", audit.Name)
+		m.versions = false
+	}
+	m.db = deps.DBManager.Default()
+	dbConfig, _ := deps.DBManager.Config(config.DefaultDatabase)
+
+	// 初始化事务管理器
+	txManager := database.NewTxManager(m.db, dbConfig.Transaction)
+	// 初始化游标编解码器，未配置密钥时使用随机密钥
+	if m.cfg.Pagination.CursorSecret == "" {
+		logrus.Warn("未配置 pagination.cursor_secret，游标分页使用随机密钥，服务重启后已发放的游标将失效")
+	}
+	cursorCodec := query.NewCursorCodec(m.cfg.Pagination.CursorSecret)
+	// 初始化仓库层
+	demoRepo := repository.NewDemoRepository(m.db, cursorCodec)
+	versionRepo := repository.NewDemoVersionRepository(m.db, m.cfg.Versions)
+	// 启用历史版本时，在数据变更所在的事务中保存历史版本
+	if m.versions {
+		if err := m.audit.AddChangeHandler(versionRepo.RecordChanges); err != nil {
+			return fmt.Errorf("注册历史版本处理函数失败: %w", err)
+		}
+	}
+	// 初始化服务层
+	// 异步导入任务在后台工作协程中执行，任务信息只保存在本实例内存中
+	m.importJobs = jobs.NewManager(jobs.Options{
+		Workers:   m.cfg.Import.Workers,
+		QueueSize: m.cfg.Import.QueueSize,
+		Retention: m.cfg.Import.JobRetention,
+	})
+	demoSvc := service.NewDemoService(demoRepo, versionRepo, txManager, m.cfg.Batch, m.cfg.Import, m.importJobs, m.cfg.Stats)
+	// 初始化控制器层
+	m.controller = controller.NewDemoController(demoSvc)
+	return nil
+}
+
+// RegisterRoutes 注册 demo 模块路由
+func (m *Module) RegisterRoutes(group *gin.RouterGroup) {
+	demoController := m.controller
+	demo := group.Group("/demo")
+	{
+		demo.GET("", demoController.ListDemo)
+		demo.GET("/page", demoController.ListDemoPage)
+		demo.GET("/cursor", demoController.ListDemoCursor)
+		demo.GET("/search", demoController.SearchDemo)
+		demo.GET("/stats", demoController.StatsDemo)
+		demo.GET("/export", demoController.ExportDemo)
+		demo.POST("/import", middleware.BodyLimit(m.cfg.Import.MaxFileSize), demoController.ImportDemo)
+		demo.GET("/import/jobs/:id", demoController.GetDemoImportJob)
+		demo.GET("/:id", demoController.GetDemoByID)
+		demo.POST("", demoController.CreateDemo)
+		demo.POST("/batch", demoController.BatchCreateDemo)
+		demo.PATCH("/batch", demoController.BatchUpdateDemo)
+		demo.DELETE("/batch", demoController.BatchDeleteDemo)
+		demo.PUT("/by-field1/:field1", demoController.UpsertDemo)
+		demo.PUT("/:id", demoController.UpdateDemo)
+		demo.PATCH("/:id", demoController.PatchDemo)
+		demo.DELETE("/soft/:id", demoController.SoftDeleteDemo)
+		demo.PUT("/restore/:id", demoController.RestoreDemo)
+		demo.DELETE("/hard/:id", demoController.DeleteDemo)
+	}
+	// 变更历史接口只在启用审计日志时注册，未启用时 audit_log 表不存在
+	if m.audit != nil && m.audit.Enabled() {
+		demo.GET("/:id/history", m.audit.History(new(model.Demo)))
+	}
+	// 历史版本接口只在启用历史版本时注册，未启用时 demo_versions 表不存在
+	if m.versions {
+		demo.GET("/:id/versions", demoController.ListDemoVersions)
+		demo.GET("/:id/versions/diff", demoController.DiffDemoVersions)
+		demo.GET("/:id/versions/:v", demoController.GetDemoVersion)
+		demo.POST("/:id/versions/:v/restore", demoController.RestoreDemoVersion)
+	}
+}
+
+// Migrations demo 表，启用历史版本时包括 demo_versions 表
+func (m *Module) Migrations() []any {
+	models := []any{&model.Demo{}}
+	if m.versions {
+		models = append(models, &model.DemoVersion{})
+	}
+	return models
+}
+
+// HealthChecks 启用历史版本时检查 demo_versions 表是否存在，表不存在时 demo 数据的变更都会失败
+func (m *Module) HealthChecks() map[string]module.HealthCheck {
+	if !m.versions {
+		return nil
+	}
+	return map[string]module.HealthCheck{
+		"versions_table": func(ctx context.Context) error {
+			if !m.db.WithContext(ctx).Migrator().HasTable(&model.DemoVersion{}) {
+				return errors.New("demo_versions 表不存在或无法访问")
+			}
+			return nil
+		},
+	}
+}
+
+// Shutdown 停止异步导入任务：排队中的任务不再执行，等待执行中的任务结束
+func (m *Module) Shutdown(ctx context.Context) error {
+	if m.importJobs == nil {
+		return nil
+	}
+	return m.importJobs.Shutdown(ctx)
+}
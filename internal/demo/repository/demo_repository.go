@@ -2,192 +2,198 @@ package repository
 
 import (
 	"context"
-	"errors"
-	"fmt"
+	"gin-template/internal/app/database"
+	"gin-template/internal/app/query"
+	baserepo "gin-template/internal/app/repository"
+	"gin-template/internal/app/search"
 	"gin-template/internal/demo/dto"
 	"gin-template/internal/demo/model"
-	"gin-template/internal/utils"
 
 	"gorm.io/gorm"
 )
 
 // DemoRepository 数据访问接口，定义数据访问的方法集。规范 Demo 数据访问的方法。定义“做什么”
 type DemoRepository interface {
-	// ListDemo 获取demo数据
-	ListDemo(ctx context.Context, req *dto.DemoListRequest) ([]*model.Demo, error)
-	// ListDemoPage 分页查询demo数据
-	ListDemoPage(ctx context.Context, page, pageSize int) ([]*model.Demo, int64, error)
+	// ListDemo 获取demo数据，spec 为附加的过滤和排序规格
+	ListDemo(ctx context.Context, req *dto.DemoListRequest, spec *query.Spec) ([]*model.Demo, error)
+	// ListDemoPage 分页查询demo数据，spec 为附加的过滤和排序规格
+	ListDemoPage(ctx context.Context, page, pageSize int, spec *query.Spec) ([]*model.Demo, int64, error)
+	// ListDemoCursor 游标分页查询demo数据，spec 为附加的过滤条件
+	ListDemoCursor(ctx context.Context, q baserepo.CursorQuery, spec *query.Spec) (*baserepo.CursorPage[model.Demo], error)
+	// ExportDemo 按列表查询条件逐行读取demo数据交给 fn 处理，fn 返回错误或 ctx 取消时停止
+	ExportDemo(ctx context.Context, req *dto.DemoListRequest, spec *query.Spec, fn func(*model.Demo) error) error
+	// DemoSearcher 返回当前数据库可用的全文检索实现
+	DemoSearcher(ctx context.Context) (search.Searcher, error)
+	// SearchDemo 按关键词全文检索demo数据（field2），按相关度降序分页返回
+	SearchDemo(ctx context.Context, searcher search.Searcher, q search.Query, page, pageSize int) ([]baserepo.SearchHit[model.Demo], int64, error)
+	// StatsDemo 按统计规格分组聚合demo数据，spec 为过滤条件；最多返回 limit 个分组，超出时截断并返回 true
+	StatsDemo(ctx context.Context, agg *query.Aggregation, spec *query.Spec, limit int) ([]query.AggregateRow, bool, error)
+	// FindDemoIDs 按过滤条件查询demo的ID（按ID升序），最多返回 limit 条
+	FindDemoIDs(ctx context.Context, spec *query.Spec, limit int) ([]int, error)
 	// GetDemoByID 根据ID获取demo数据
 	GetDemoByID(ctx context.Context, id int) (*model.Demo, error)
+	// GetDemoForUpdate 根据ID获取demo数据并加行级排他锁，需在事务中调用
+	GetDemoForUpdate(ctx context.Context, id int) (*model.Demo, error)
 	// CreateDemo 创建demo数据
 	CreateDemo(ctx context.Context, demo *model.Demo) (int, error)
-	// BatchCreateDemo 批量创建demo数据
-	BatchCreateDemo(ctx context.Context, demos []*model.Demo) error
-	// UpdateDemo 更新demo数据
-	UpdateDemo(ctx context.Context, id int, updateFields map[string]interface{}) error
+	// BatchCreateDemo 分批创建demo数据，返回每条记录的处理结果，新建或命中的记录ID回填到 demos 中
+	BatchCreateDemo(ctx context.Context, demos []*model.Demo, onConflict baserepo.ConflictStrategy, chunkSize int) ([]baserepo.BatchCreateItem, error)
+	// UpsertDemo 按 field1 创建或更新demo数据，返回是否为新建，ID回填到 demo 中
+	UpsertDemo(ctx context.Context, demo *model.Demo) (bool, error)
+	// UpdateDemo 更新demo数据，versions 不为空时按版本号条件更新（乐观锁）
+	UpdateDemo(ctx context.Context, id int, versions []int, updateFields map[string]interface{}) error
+	// SoftDeleteDemo 软删除demo数据
+	SoftDeleteDemo(ctx context.Context, id int) error
+	// RestoreDemo 恢复已软删除的demo数据
+	RestoreDemo(ctx context.Context, id int) error
 	// DeleteDemo 删除demo数据
 	DeleteDemo(ctx context.Context, id int) error
 }
 
 // DemoRepositoryImpl 实现接口的具体结构体。定义“怎么做”
+// 嵌入泛型 BaseRepository 复用通用的 CRUD 与错误转换，只实现 demo 模块特有的查询条件
 type DemoRepositoryImpl struct {
-	db *gorm.DB
+	*baserepo.BaseRepository[model.Demo]
+	search *search.Engine // field2 全文检索
 }
 
 // NewDemoRepository 创建数据访问实例。用于创建DemoRepository接口的实例，接收一个*gorm.DB（数据库连接）参数，注入到DemoRepositoryImpl结构体中。
-func NewDemoRepository(db *gorm.DB) DemoRepository {
-	return &DemoRepositoryImpl{db: db}
+// cursorCodec 为游标分页的游标编解码器
+func NewDemoRepository(db *gorm.DB, cursorCodec *query.CursorCodec) DemoRepository {
+	return &DemoRepositoryImpl{
+		BaseRepository: baserepo.NewBaseRepository[model.Demo](db, "demo").
+			WithNotFoundMessage("demo.not_found").
+			WithUniqueMessage("field1", "demo.field1_duplicate").
+			WithUniqueMessage("uk_demo_field1", "demo.field1_duplicate").
+			WithVersion("version").
+			WithCursorCodec(cursorCodec),
+		search: search.New(db, search.Index{Table: "demo", Key: "id", Columns: []string{"field2"}}),
+	}
 }
 
 // ListDemo 获取demo数据
-func (repo *DemoRepositoryImpl) ListDemo(ctx context.Context, req *dto.DemoListRequest) ([]*model.Demo, error) {
-	// 声明一个Demo指针切片，用于存储查询结果
-	var demo []*model.Demo
-
-	// 使用GORM进行查询，WithContext将上下文与数据库操作关联，支持超时和取消
-	query := repo.db.WithContext(ctx)
-	// 拼接查询条件
-	if req.Field1 != 0 {
-		query = query.Where("field1 = ?", req.Field1)
-	}
-	if req.Field2 != "" {
-		query = query.Where("field2 = ?", req.Field2)
-	}
-	// Find方法将查询结果存储到demo指针切片中
-	result := query.Find(&demo)
-	err := result.Error
+func (repo *DemoRepositoryImpl) ListDemo(ctx context.Context, req *dto.DemoListRequest, spec *query.Spec) ([]*model.Demo, error) {
+	return repo.Find(ctx, listScopes(req, spec)...)
+}
 
-	// 异常处理
-	if err != nil {
-		return nil, utils.NewSystemError(fmt.Errorf("数据库查询失败: %v", err))
+// ExportDemo 按列表查询条件逐行读取demo数据，未指定排序时按ID升序，保证导出顺序稳定
+func (repo *DemoRepositoryImpl) ExportDemo(ctx context.Context, req *dto.DemoListRequest, spec *query.Spec, fn func(*model.Demo) error) error {
+	scopes := listScopes(req, spec)
+	if !spec.HasSort() {
+		scopes = append(scopes, baserepo.OrderBy("id", false))
 	}
+	return repo.Stream(ctx, fn, scopes...)
+}
 
-	return demo, nil
+// listScopes 列表查询条件：请求参数中的字段过滤、软删除查询范围和查询规格
+func listScopes(req *dto.DemoListRequest, spec *query.Spec) []baserepo.Scope {
+	// 拼接查询条件，零值参数不参与过滤
+	scopes := []baserepo.Scope{
+		baserepo.EqIfNotZero("field1", req.Field1),
+		baserepo.EqIfNotZero("field2", req.Field2),
+	}
+	// 软删除数据查询范围，默认仅查询未删除数据
+	switch req.Deleted {
+	case "with":
+		scopes = append(scopes, database.WithDeleted)
+	case "only":
+		scopes = append(scopes, database.OnlyDeleted)
+	}
+	// 查询规格中的过滤条件和排序，字段已在解析时按白名单校验
+	return append(scopes, spec.Scopes()...)
 }
 
 // ListDemoPage 分页查询demo数据
-func (repo *DemoRepositoryImpl) ListDemoPage(ctx context.Context, page, pageSize int) ([]*model.Demo, int64, error) {
+func (repo *DemoRepositoryImpl) ListDemoPage(ctx context.Context, page, pageSize int, spec *query.Spec) ([]*model.Demo, int64, error) {
+	return repo.FindPage(ctx, page, pageSize, spec.Scopes()...)
+}
 
-	offset := (page - 1) * pageSize // 计算偏移量
+// ListDemoCursor 游标分页查询demo数据，排序由 q.Sorts 指定，spec 只取过滤条件
+func (repo *DemoRepositoryImpl) ListDemoCursor(ctx context.Context, q baserepo.CursorQuery, spec *query.Spec) (*baserepo.CursorPage[model.Demo], error) {
+	return repo.FindCursor(ctx, q, spec.FilterScopes()...)
+}
 
-	// 声明一个Demo指针切片，用于存储查询结果
-	var demo []*model.Demo
+// DemoSearcher 返回当前数据库可用的全文检索实现，未建立全文索引时为 LIKE 回退实现
+func (repo *DemoRepositoryImpl) DemoSearcher(ctx context.Context) (search.Searcher, error) {
+	searcher, err := repo.search.Searcher(ctx)
+	if err != nil {
+		return nil, repo.TranslateError(err, "search_detect", nil)
+	}
+	return searcher, nil
+}
 
-	// 构建基础查询
-	query := repo.db.WithContext(ctx).Model(&model.Demo{})
+// SearchDemo 按关键词全文检索demo数据，仅检索未删除数据
+func (repo *DemoRepositoryImpl) SearchDemo(ctx context.Context, searcher search.Searcher, q search.Query, page, pageSize int) ([]baserepo.SearchHit[model.Demo], int64, error) {
+	return repo.SearchPage(ctx, searcher, q, page, pageSize)
+}
 
-	// 计算总数
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, utils.NewSystemError(fmt.Errorf("计算总数时数据库查询失败: %v", err))
-	}
+// StatsDemo 按统计规格分组聚合demo数据，仅统计未删除数据
+func (repo *DemoRepositoryImpl) StatsDemo(ctx context.Context, agg *query.Aggregation, spec *query.Spec, limit int) ([]query.AggregateRow, bool, error) {
+	return repo.Aggregate(ctx, agg, limit, spec.FilterScopes()...)
+}
 
-	// 查询数据
-	if err := query.Offset(offset).Limit(pageSize).Find(&demo).Error; err != nil {
-		return nil, 0, utils.NewSystemError(fmt.Errorf("数据库查询失败: %v", err))
+// FindDemoIDs 按过滤条件查询demo的ID（按ID升序），最多返回 limit 条
+func (repo *DemoRepositoryImpl) FindDemoIDs(ctx context.Context, spec *query.Spec, limit int) ([]int, error) {
+	var ids []int
+	scopes := append(spec.FilterScopes(), baserepo.OrderBy("id", false), func(db *gorm.DB) *gorm.DB {
+		return db.Limit(limit)
+	})
+	if err := repo.Pluck(ctx, "id", &ids, scopes...); err != nil {
+		return nil, err
 	}
-
-	return demo, total, nil
+	return ids, nil
 }
 
 // GetDemoByID 根据ID获取demo数据
 func (repo *DemoRepositoryImpl) GetDemoByID(ctx context.Context, id int) (*model.Demo, error) {
-	// 声明一个Demo指针，用于存储查询结果
-	var demo *model.Demo
-
-	// 查询数据
-	// First 方法：查询符合条件的第一条记录（默认按主键升序排序）
-	// 若要按其他字段查询第一条记录，写法：repo.db.First(&demo, "field1 = ?", value)
-	result := repo.db.WithContext(ctx).First(&demo, id)
-	err := result.Error
-
-	// 异常处理
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, utils.NewBusinessError(utils.ErrCodeResourceNotFound, "demo数据不存在")
-		}
-		return nil, utils.NewSystemError(fmt.Errorf("数据库查询失败: %w", err))
-	}
+	return repo.FindByID(ctx, id)
+}
 
-	return demo, nil
+// GetDemoForUpdate 根据ID获取demo数据并加行级排他锁，需在事务中调用，锁在事务结束时释放
+func (repo *DemoRepositoryImpl) GetDemoForUpdate(ctx context.Context, id int) (*model.Demo, error) {
+	return repo.FindByID(ctx, id, baserepo.ForUpdate)
 }
 
 // CreateDemo 创建demo数据
 func (repo *DemoRepositoryImpl) CreateDemo(ctx context.Context, demo *model.Demo) (int, error) {
-	// 插入数据
-	result := repo.db.WithContext(ctx).Create(demo)
-	err := result.Error
-
-	// 异常处理
-	if err != nil {
-		// 检查是否是重复键错误（需在数据库层配置唯一约束）
-		if exist, fieldName, value := utils.IsUniqueConstraintError(err); exist {
-			if fieldName == "field1" {
-				return 0, utils.NewBusinessError(utils.ErrCodeDuplicateKey, fmt.Sprintf("字段一('%s')已存在，不能重复创建", value))
-			}
-		}
-		return 0, utils.NewSystemError(fmt.Errorf("数据库插入失败: %w", err))
+	// 插入数据，字段一重复时返回 demo.field1_duplicate 业务异常（唯一索引 uk_demo_field1）
+	if err := repo.Create(ctx, demo); err != nil {
+		return 0, err
 	}
-
 	return demo.ID, nil
 }
 
-// BatchCreateDemo 批量创建demo数据
-func (repo *DemoRepositoryImpl) BatchCreateDemo(ctx context.Context, demos []*model.Demo) error {
-	// 批量插入数据
-	result := repo.db.WithContext(ctx).Create(demos)
-	err := result.Error
-
-	// 异常处理
-	if err != nil {
-		// 检查是否是重复键错误（需在数据库层配置唯一约束）
-		if exist, fieldName, value := utils.IsUniqueConstraintError(err); exist {
-			if fieldName == "field1" {
-				return utils.NewBusinessError(utils.ErrCodeDuplicateKey, fmt.Sprintf("字段一('%s')已存在，不能重复创建", value))
-			}
-		}
-		return utils.NewSystemError(fmt.Errorf("数据库批量插入失败: %w", err))
-	}
+// BatchCreateDemo 分批创建demo数据，以 field1 判断冲突，update 策略下更新 field2 并恢复已软删除的数据
+func (repo *DemoRepositoryImpl) BatchCreateDemo(ctx context.Context, demos []*model.Demo, onConflict baserepo.ConflictStrategy, chunkSize int) ([]baserepo.BatchCreateItem, error) {
+	return repo.CreateInChunks(ctx, demos, baserepo.BatchCreateOptions{
+		ChunkSize:       chunkSize,
+		OnConflict:      onConflict,
+		ConflictColumns: []string{"field1"},
+		UpdateColumns:   []string{"field2"},
+	})
+}
 
-	return nil
+// UpsertDemo 按 field1 创建或更新demo数据，已软删除的数据会被恢复
+func (repo *DemoRepositoryImpl) UpsertDemo(ctx context.Context, demo *model.Demo) (bool, error) {
+	return repo.Upsert(ctx, demo, []string{"field1"}, []string{"field2"})
 }
 
-// UpdateDemo 更新demo数据
-func (repo *DemoRepositoryImpl) UpdateDemo(ctx context.Context, id int, updateFields map[string]interface{}) error {
-	// 更新数据
-	result := repo.db.WithContext(ctx).
-		Model(&model.Demo{}).
-		Where("id = ?", id).
-		Updates(updateFields)
-	err := result.Error
+// UpdateDemo 更新demo数据，版本号自增；versions 不为空且与当前版本不一致时返回版本冲突
+func (repo *DemoRepositoryImpl) UpdateDemo(ctx context.Context, id int, versions []int, updateFields map[string]interface{}) error {
+	return repo.UpdateByIDWithVersion(ctx, id, versions, updateFields)
+}
 
-	// 异常处理
-	if err != nil {
-		return utils.NewSystemError(fmt.Errorf("更新数据失败: %w", err))
-	}
-	if result.RowsAffected == 0 {
-		return utils.NewBusinessError(utils.ErrCodeResourceNotFound, "数据不存在或已被删除，请刷新页面后重试")
-	}
+// SoftDeleteDemo 软删除demo数据，记录删除时间和删除人
+func (repo *DemoRepositoryImpl) SoftDeleteDemo(ctx context.Context, id int) error {
+	return repo.SoftDeleteByID(ctx, id)
+}
 
-	return nil
+// RestoreDemo 恢复已软删除的demo数据
+func (repo *DemoRepositoryImpl) RestoreDemo(ctx context.Context, id int) error {
+	return repo.RestoreByID(ctx, id)
 }
 
 // DeleteDemo 删除demo数据
 func (repo *DemoRepositoryImpl) DeleteDemo(ctx context.Context, id int) error {
-	// 删除数据
-	result := repo.db.WithContext(ctx).
-		Model(&model.Demo{}).
-		Where("id = ?", id).
-		Delete(&model.Demo{})
-	err := result.Error
-
-	// 异常处理
-	if err != nil {
-		return utils.NewSystemError(fmt.Errorf("删除数据失败: %w", err))
-	}
-	if result.RowsAffected == 0 {
-		return utils.NewBusinessError(utils.ErrCodeResourceNotFound, "数据不存在或已被删除，请刷新页面后重试")
-	}
-
-	return nil
+	return repo.DeleteByID(ctx, id)
 }
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"gin-template/internal/app/config"
+	"gin-template/internal/app/database"
+	baserepo "gin-template/internal/app/repository"
+	"gin-template/internal/audit/recorder"
+	"gin-template/internal/demo/model"
+	"gin-template/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DemoVersionRepository demo历史版本数据访问接口
+type DemoVersionRepository interface {
+	// ListDemoVersions 分页查询demo的历史版本，按版本号倒序
+	ListDemoVersions(ctx context.Context, demoID, page, pageSize int) ([]*model.DemoVersion, int64, error)
+	// GetDemoVersion 查询demo的指定版本，不存在时返回 demo.version_not_found 业务异常
+	GetDemoVersion(ctx context.Context, demoID, version int) (*model.DemoVersion, error)
+	// SaveDemoVersions 保存demo当前数据为新版本（已存在的版本忽略），并按保留策略清理旧版本
+	SaveDemoVersions(ctx context.Context, demos []*model.Demo) error
+	// RecordChanges 数据变更处理函数：在数据变更所在的事务中为变更后的demo保存版本，注册到数据变更记录插件
+	RecordChanges(tx *gorm.DB, changes []recorder.Change) error
+}
+
+// DemoVersionRepositoryImpl demo历史版本数据访问实现，嵌入泛型 BaseRepository
+type DemoVersionRepositoryImpl struct {
+	*baserepo.BaseRepository[model.DemoVersion]
+	retention config.VersionConfig
+}
+
+// NewDemoVersionRepository 创建demo历史版本数据访问实例，retention 为版本保留策略
+func NewDemoVersionRepository(db *gorm.DB, retention config.VersionConfig) DemoVersionRepository {
+	return &DemoVersionRepositoryImpl{
+		BaseRepository: baserepo.NewBaseRepository[model.DemoVersion](db, "demo_versions").
+			WithNotFoundMessage("demo.version_not_found"),
+		retention: retention,
+	}
+}
+
+// ListDemoVersions 分页查询demo的历史版本
+func (repo *DemoVersionRepositoryImpl) ListDemoVersions(ctx context.Context, demoID, page, pageSize int) ([]*model.DemoVersion, int64, error) {
+	return repo.FindPage(ctx, page, pageSize,
+		baserepo.Eq("demo_id", demoID),
+		baserepo.OrderBy("version", true),
+	)
+}
+
+// GetDemoVersion 查询demo的指定版本
+func (repo *DemoVersionRepositoryImpl) GetDemoVersion(ctx context.Context, demoID, version int) (*model.DemoVersion, error) {
+	return repo.First(ctx, baserepo.Eq("demo_id", demoID), baserepo.Eq("version", version))
+}
+
+// SaveDemoVersions 保存demo当前数据为新版本
+// 同一版本号只保存一次（未递增版本号的更新不产生新版本），保存后按 keep_last / max_age 清理该数据的旧版本，最新版本始终保留
+func (repo *DemoVersionRepositoryImpl) SaveDemoVersions(ctx context.Context, demos []*model.Demo) error {
+	if len(demos) == 0 {
+		return nil
+	}
+	operator := utils.GetOperator(ctx)
+	versions := make([]*model.DemoVersion, 0, len(demos))
+	for _, demo := range demos {
+		deleted := "N"
+		if database.IsSoftDeleted(demo.IsDeleted) {
+			deleted = "Y"
+		}
+		versions = append(versions, &model.DemoVersion{
+			DemoID:   demo.ID,
+			Version:  demo.Version,
+			Field1:   demo.Field1,
+			Field2:   demo.Field2,
+			Deleted:  deleted,
+			Operator: operator,
+		})
+	}
+	if err := repo.DB(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&versions).Error; err != nil {
+		return repo.TranslateError(err, "create", nil)
+	}
+
+	for _, demo := range demos {
+		if err := repo.pruneDemoVersions(ctx, demo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneDemoVersions 按保留策略清理demo的旧版本：版本号超出 keep_last 范围或创建时间早于 max_age 的版本，不含最新版本
+func (repo *DemoVersionRepositoryImpl) pruneDemoVersions(ctx context.Context, demo *model.Demo) error {
+	var conditions []clause.Expression
+	if keep := repo.retention.KeepLast; keep > 0 && demo.Version > keep {
+		conditions = append(conditions, clause.Lte{Column: "version", Value: demo.Version - keep})
+	}
+	if repo.retention.MaxAge > 0 {
+		conditions = append(conditions, clause.Lt{Column: "create_time", Value: time.Now().Add(-repo.retention.MaxAge)})
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+	_, err := repo.DeleteWhere(ctx,
+		baserepo.Eq("demo_id", demo.ID),
+		func(db *gorm.DB) *gorm.DB {
+			return db.Where(clause.Lt{Column: "version", Value: demo.Version}).Where(clause.Or(conditions...))
+		},
+	)
+	return err
+}
+
+// RecordChanges 为变更后的demo保存版本，物理删除不产生新版本（已有版本保留）
+func (repo *DemoVersionRepositoryImpl) RecordChanges(tx *gorm.DB, changes []recorder.Change) error {
+	var demos []*model.Demo
+	for _, change := range changes {
+		if demo, ok := change.After.(*model.Demo); ok {
+			demos = append(demos, demo)
+		}
+	}
+	return repo.SaveDemoVersions(database.ContextWithTx(tx.Statement.Context, tx), demos)
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gin-template/internal/app/query"
+	baserepo "gin-template/internal/app/repository"
+	"gin-template/internal/demo/dto"
+	"gin-template/internal/demo/model"
+	"gin-template/internal/utils"
+)
+
+// 批量操作模式
+const (
+	batchModeAtomic     = "atomic"      // 任一记录失败则全部回滚
+	batchModeBestEffort = "best_effort" // 失败的记录单独回滚，其余提交
+)
+
+// errBatchRolledBack atomic 模式下存在失败记录时用于回滚外层事务
+var errBatchRolledBack = errors.New("批量操作存在失败记录，已全部回滚")
+
+// batchTarget 批量操作的目标记录
+type batchTarget struct {
+	id       int
+	versions []int // 期望的当前版本号，为空不校验
+}
+
+// BatchCreateDemo 分批创建demo数据
+//   - 默认各批次独立提交，失败的记录不影响其他记录
+//   - opts.Transactional 为 true 时在同一事务中执行，存在失败记录时全部回滚
+func (svc *DemoServiceImpl) BatchCreateDemo(ctx context.Context, demos []*dto.DemoCreateRequest, opts dto.DemoBatchCreateQuery) (*dto.DemoBatchCreateResponse, error) {
+	var resp *dto.DemoBatchCreateResponse
+	create := func(ctx context.Context) error {
+		// 转换为数据模型（数据传输对象 -> 数据模型），重试时需要重新构建
+		demoModels := make([]*model.Demo, 0, len(demos))
+		for _, demo := range demos {
+			demoModels = append(demoModels, &model.Demo{
+				Field1: demo.Field1,
+				Field2: demo.Field2,
+			})
+		}
+
+		// 调用数据访问层方法分批创建数据
+		items, err := svc.demoRepo.BatchCreateDemo(ctx, demoModels, baserepo.ConflictStrategy(opts.OnConflict), svc.batchConfig.ChunkSize)
+		if err != nil {
+			return err
+		}
+		resp = &dto.DemoBatchCreateResponse{Created: []int{}, Failures: []dto.DemoBatchCreateFailure{}}
+		for i, item := range items {
+			switch item.Status {
+			case baserepo.BatchCreated:
+				resp.Created = append(resp.Created, demoModels[i].ID)
+			case baserepo.BatchUpdated:
+				resp.Updated = append(resp.Updated, demoModels[i].ID)
+			case baserepo.BatchSkipped:
+				resp.Skipped = append(resp.Skipped, i)
+			default:
+				code := utils.ErrCodeServerInternalError
+				if bizErr, ok := utils.GetBusinessError(item.Err); ok {
+					code = bizErr.Code
+				}
+				resp.Failures = append(resp.Failures, dto.DemoBatchCreateFailure{Index: i, Code: code})
+			}
+		}
+		if opts.Transactional && len(resp.Failures) > 0 {
+			return errBatchRolledBack
+		}
+		resp.Committed = true
+		return nil
+	}
+
+	var err error
+	if opts.Transactional {
+		err = svc.txManager.WithinTx(ctx, create)
+	} else {
+		err = create(ctx)
+	}
+	if errors.Is(err, errBatchRolledBack) {
+		// 已回滚，新建 / 更新 / 跳过的结果均不再有效，只返回失败的记录
+		resp.Created, resp.Updated, resp.Skipped = []int{}, nil, nil
+		return resp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BatchUpdateDemo 批量更新demo数据
+func (svc *DemoServiceImpl) BatchUpdateDemo(ctx context.Context, req dto.DemoBatchUpdateRequest) (*dto.DemoBatchResponse, error) {
+	// 构建更新字段
+	updateFields := make(map[string]interface{})
+	if req.Field1 != nil {
+		updateFields["field1"] = *req.Field1
+	}
+	if req.Field2 != nil {
+		updateFields["field2"] = *req.Field2
+	}
+	if len(updateFields) == 0 {
+		return nil, utils.NewBusinessError(utils.ErrCodeParamInvalid, "error.no_update_fields")
+	}
+
+	var resp *dto.DemoBatchResponse
+	err := svc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		targets, err := svc.resolveBatchTargets(ctx, req.DemoBatchSelector)
+		if err != nil {
+			return err
+		}
+		resp, err = svc.runBatch(ctx, req.Mode, targets, "updated", func(ctx context.Context, target batchTarget) error {
+			return svc.demoRepo.UpdateDemo(ctx, target.id, target.versions, updateFields)
+		})
+		return err
+	})
+	return batchResult(resp, err)
+}
+
+// BatchDeleteDemo 批量删除demo数据，hard 为 true 时物理删除，否则软删除
+func (svc *DemoServiceImpl) BatchDeleteDemo(ctx context.Context, req dto.DemoBatchDeleteRequest) (*dto.DemoBatchResponse, error) {
+	var resp *dto.DemoBatchResponse
+	err := svc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		targets, err := svc.resolveBatchTargets(ctx, req.DemoBatchSelector)
+		if err != nil {
+			return err
+		}
+		resp, err = svc.runBatch(ctx, req.Mode, targets, "deleted", func(ctx context.Context, target batchTarget) error {
+			// 携带版本号时先加锁校验，与单条删除一致
+			if len(target.versions) > 0 {
+				if err := svc.checkVersionForUpdate(ctx, target.id, target.versions); err != nil {
+					return err
+				}
+			}
+			if req.Hard {
+				return svc.demoRepo.DeleteDemo(ctx, target.id)
+			}
+			return svc.demoRepo.SoftDeleteDemo(ctx, target.id)
+		})
+		return err
+	})
+	return batchResult(resp, err)
+}
+
+// resolveBatchTargets 解析批量操作的目标记录，记录数超过配置的上限时拒绝
+func (svc *DemoServiceImpl) resolveBatchTargets(ctx context.Context, selector dto.DemoBatchSelector) ([]batchTarget, error) {
+	maxSize := svc.batchConfig.MaxSize
+	byID := len(selector.IDs)+len(selector.Items) > 0
+	if byID == (len(selector.Filter) > 0) {
+		return nil, utils.NewBusinessError(utils.ErrCodeParamInvalid, "batch.target_required")
+	}
+
+	if byID {
+		if len(selector.IDs)+len(selector.Items) > maxSize {
+			return nil, utils.NewBusinessErrorWithParams(utils.ErrCodeParamInvalid, "batch.too_large", map[string]any{"max": maxSize})
+		}
+		// 同一ID只处理一次，保留第一次出现的位置
+		seen := make(map[int]bool, len(selector.IDs)+len(selector.Items))
+		targets := make([]batchTarget, 0, len(selector.IDs)+len(selector.Items))
+		for _, id := range selector.IDs {
+			if !seen[id] {
+				seen[id] = true
+				targets = append(targets, batchTarget{id: id})
+			}
+		}
+		for _, item := range selector.Items {
+			if !seen[item.ID] {
+				seen[item.ID] = true
+				target := batchTarget{id: item.ID}
+				if item.Version != nil {
+					target.versions = []int{*item.Version}
+				}
+				targets = append(targets, target)
+			}
+		}
+		return targets, nil
+	}
+
+	// 按过滤条件选择：多查一条判断是否超过上限，避免误操作大量数据
+	spec, err := query.ParseFilterMap(selector.Filter, dto.DemoQueryFields)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := svc.demoRepo.FindDemoIDs(ctx, spec, maxSize+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > maxSize {
+		return nil, utils.NewBusinessErrorWithParams(utils.ErrCodeParamInvalid, "batch.too_large", map[string]any{"max": maxSize})
+	}
+	targets := make([]batchTarget, 0, len(ids))
+	for _, id := range ids {
+		targets = append(targets, batchTarget{id: id})
+	}
+	return targets, nil
+}
+
+// runBatch 逐条执行批量操作，需在事务中调用
+//   - 每条记录在保存点中执行，失败只回滚该条记录的修改，并记录失败状态和业务错误码
+//   - 系统错误（如数据库连接异常）中止整个批次
+//   - atomic 模式下存在失败记录时返回 errBatchRolledBack 回滚整个事务
+func (svc *DemoServiceImpl) runBatch(ctx context.Context, mode string, targets []batchTarget, successStatus string, fn func(ctx context.Context, target batchTarget) error) (*dto.DemoBatchResponse, error) {
+	resp := &dto.DemoBatchResponse{Results: make([]dto.DemoBatchItemResult, 0, len(targets))}
+	for _, target := range targets {
+		result := dto.DemoBatchItemResult{ID: target.id, Status: successStatus}
+		err := svc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			return fn(ctx, target)
+		})
+		if err != nil {
+			bizErr, ok := utils.GetBusinessError(err)
+			if !ok {
+				return nil, err
+			}
+			result.Status, result.Code = batchFailureStatus(bizErr.Code), bizErr.Code
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	if resp.Failed > 0 && mode != batchModeBestEffort {
+		return resp, errBatchRolledBack
+	}
+	resp.Committed = true
+	return resp, nil
+}
+
+// batchResult 整理批量操作结果：atomic 模式回滚时将成功的记录标记为 rolled_back
+func batchResult(resp *dto.DemoBatchResponse, err error) (*dto.DemoBatchResponse, error) {
+	if errors.Is(err, errBatchRolledBack) {
+		for i := range resp.Results {
+			if resp.Results[i].Code == 0 {
+				resp.Results[i].Status = "rolled_back"
+			}
+		}
+		resp.Succeeded = 0
+		return resp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// batchFailureStatus 业务错误码对应的记录状态
+func batchFailureStatus(code int) string {
+	switch code {
+	case utils.ErrCodeResourceNotFound:
+		return "not_found"
+	case utils.ErrCodeVersionConflict:
+		return "conflict"
+	default:
+		return "failed"
+	}
+}
@@ -0,0 +1,433 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-template/internal/app/database"
+	"gin-template/internal/app/i18n"
+	"gin-template/internal/app/importer"
+	"gin-template/internal/app/jobs"
+	baserepo "gin-template/internal/app/repository"
+	"gin-template/internal/app/tenant"
+	"gin-template/internal/demo/dto"
+	"gin-template/internal/demo/model"
+	"gin-template/internal/utils"
+	"io"
+	"math"
+	"os"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+// importTxFile 整个文件在同一事务中导入
+const importTxFile = "file"
+
+// errImportRolledBack 事务范围内存在失败的行时用于回滚事务
+var errImportRolledBack = errors.New("导入存在失败的行，已回滚")
+
+// ImportDemo 导入 CSV / NDJSON 文件中的demo数据，src 为文件内容，size 为文件大小
+//   - 要求异步或文件超过 import.async_threshold 时复制为临时文件并提交异步任务，返回任务信息
+//   - 否则同步执行，返回导入结果
+func (svc *DemoServiceImpl) ImportDemo(ctx context.Context, src io.Reader, size int64, opts dto.DemoImportQuery) (*dto.DemoImportResponse, error) {
+	if !opts.Async && size <= svc.importConfig.AsyncThreshold {
+		result, err := svc.runImport(ctx, src, size, opts, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.DemoImportResponse{Result: result}, nil
+	}
+
+	// 上传的文件在请求结束后删除，异步任务使用自己的临时文件
+	file, err := os.CreateTemp("", "demo-import-*")
+	if err != nil {
+		return nil, utils.NewSystemError(fmt.Errorf("创建导入临时文件失败: %w", err))
+	}
+	if _, err = io.Copy(file, src); err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		removeTempFile(file)
+		return nil, utils.NewSystemError(fmt.Errorf("写入导入临时文件失败: %w", err))
+	}
+
+	owner, _ := tenant.FromContext(ctx)
+	job, err := svc.importJobs.Submit(detachContext(ctx), owner, func(ctx context.Context, job *jobs.Job) (any, error) {
+		defer removeTempFile(file)
+		return svc.runImport(ctx, file, size, opts, func(progress dto.DemoImportProgress) {
+			job.SetProgress(progress)
+		})
+	})
+	if err != nil {
+		removeTempFile(file)
+		if errors.Is(err, jobs.ErrQueueFull) {
+			return nil, utils.WrapBusinessError(utils.ErrCodeTooManyRequests, "import.queue_full", err)
+		}
+		return nil, utils.NewSystemError(err)
+	}
+	return &dto.DemoImportResponse{Job: demoImportJob(job.Snapshot())}, nil
+}
+
+// GetDemoImportJob 查询异步导入任务，只能查询当前租户提交的任务
+func (svc *DemoServiceImpl) GetDemoImportJob(ctx context.Context, id string) (*dto.DemoImportJobResponse, error) {
+	owner, _ := tenant.FromContext(ctx)
+	job, ok := svc.importJobs.Get(id)
+	if !ok {
+		return nil, utils.NewBusinessError(utils.ErrCodeResourceNotFound, "import.job_not_found")
+	}
+	snapshot := job.Snapshot()
+	if snapshot.Owner != owner {
+		return nil, utils.NewBusinessError(utils.ErrCodeResourceNotFound, "import.job_not_found")
+	}
+	return demoImportJob(snapshot), nil
+}
+
+// demoImportJob 转换任务状态（任务快照 -> 数据传输对象）
+func demoImportJob(snapshot jobs.Snapshot) *dto.DemoImportJobResponse {
+	resp := &dto.DemoImportJobResponse{
+		ID:         snapshot.ID,
+		Status:     string(snapshot.Status),
+		CreateTime: snapshot.CreateTime,
+		StartTime:  snapshot.StartTime,
+		FinishTime: snapshot.FinishTime,
+	}
+	resp.Progress, _ = snapshot.Progress.(dto.DemoImportProgress)
+	resp.Result, _ = snapshot.Result.(*dto.DemoImportResult)
+	if snapshot.Err != nil {
+		resp.Error = &dto.DemoImportJobError{Code: utils.ErrCodeServerInternalError, Message: "error.internal"}
+		if bizErr, ok := utils.GetBusinessError(snapshot.Err); ok {
+			resp.Error = &dto.DemoImportJobError{Code: bizErr.Code, Message: bizErr.Message, Params: bizErr.Params}
+		}
+	}
+	return resp
+}
+
+// runImport 逐行读取、校验并写入，report 不为空时在每个事务范围结束后报告进度
+// 无法继续读取时返回错误，同时返回已处理部分的结果（chunk 模式下此前的事务已提交）
+func (svc *DemoServiceImpl) runImport(ctx context.Context, src io.Reader, size int64, opts dto.DemoImportQuery, report func(dto.DemoImportProgress)) (*dto.DemoImportResult, error) {
+	counter := &countingReader{r: src}
+	reader, err := importer.NewReader(importer.Format(opts.Format), counter)
+	if err != nil {
+		return nil, utils.WrapBusinessError(utils.ErrCodeParamInvalid, "import.unsupported_format", err)
+	}
+	imp := &demoImport{
+		svc:     svc,
+		opts:    opts,
+		reader:  reader,
+		counter: counter,
+		size:    size,
+		report:  report,
+		result:  &dto.DemoImportResult{DryRun: opts.DryRun, Errors: []dto.DemoImportError{}},
+	}
+	imp.progress()
+
+	if opts.DryRun || opts.Transaction != importTxFile {
+		err = imp.run(ctx)
+	} else {
+		// 整个文件在同一事务中导入，文件内容无法重新读取，因此不按事务配置重试
+		err = svc.txManager.WithinTxOptions(ctx, database.TxOptions{}, func(ctx context.Context) error {
+			if err := imp.run(ctx); err != nil {
+				return err
+			}
+			if imp.result.Failed > 0 {
+				return errImportRolledBack
+			}
+			return nil
+		})
+		if err == nil {
+			imp.result.Created, imp.result.Updated, imp.result.Skipped = imp.pending.created, imp.pending.updated, imp.pending.skipped
+		} else {
+			// 已写入的行随事务回滚
+			imp.result.RolledBack = imp.result.Valid - imp.writeFailed
+		}
+		if errors.Is(err, errImportRolledBack) {
+			err = nil
+		}
+	}
+	imp.progress()
+	return imp.result, err
+}
+
+// demoImportRow 检查通过、等待写入的行
+type demoImportRow struct {
+	line int
+	req  dto.DemoCreateRequest
+}
+
+// importCounts 写入结果计数
+type importCounts struct {
+	created, updated, skipped int
+}
+
+// demoImport 一次导入的执行状态
+type demoImport struct {
+	svc     *DemoServiceImpl
+	opts    dto.DemoImportQuery
+	reader  importer.Reader
+	counter *countingReader
+	size    int64
+	report  func(dto.DemoImportProgress)
+	result  *dto.DemoImportResult
+
+	rows        []demoImportRow // 当前事务范围内检查通过、尚未写入的行
+	failed      bool            // 当前事务范围内是否存在失败的行
+	pending     importCounts    // file 模式下已写入、等待事务提交的行数
+	writeFailed int             // 写入失败的行数
+}
+
+// run 逐行读取，每 import.commit_size 行结束一个事务范围
+func (imp *demoImport) run(ctx context.Context) error {
+	commitSize := imp.svc.importConfig.CommitSize
+	for {
+		var req dto.DemoCreateRequest
+		line, err := imp.reader.Next(&req)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr *importer.RowError
+		switch {
+		case errors.As(err, &rowErr):
+			imp.fail(rowImportError(rowErr))
+		case err != nil:
+			return readImportError(line, err)
+		default:
+			imp.check(line, &req)
+		}
+
+		imp.result.Total++
+		if imp.result.Total%commitSize == 0 {
+			if err := imp.commit(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return imp.commit(ctx)
+}
+
+// check 按创建请求的校验规则检查一行数据，通过的行等待写入
+func (imp *demoImport) check(line int, req *dto.DemoCreateRequest) {
+	err := binding.Validator.ValidateStruct(req)
+	if err == nil {
+		imp.rows = append(imp.rows, demoImportRow{line: line, req: *req})
+		imp.result.Valid++
+		return
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		imp.fail(dto.DemoImportError{Line: line, Code: utils.ErrCodeParamInvalid, Message: "error.param_invalid"})
+		return
+	}
+	errs := make([]dto.DemoImportError, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		field := importer.FieldName(req, fieldErr.StructField())
+		// 校验规则未登记消息时使用通用描述
+		message := "validation." + fieldErr.Tag()
+		if !i18n.Default().Has("", message) {
+			message = "import.invalid_value"
+		}
+		errs = append(errs, dto.DemoImportError{
+			Line:    line,
+			Field:   field,
+			Code:    utils.ErrCodeParamInvalid,
+			Message: message,
+			Params:  map[string]any{"field": field, "param": fieldErr.Param()},
+		})
+	}
+	imp.fail(errs...)
+}
+
+// fail 记录一个失败的行，errs 为该行的错误（至少一条），超过 import.max_errors 的错误只计数
+func (imp *demoImport) fail(errs ...dto.DemoImportError) {
+	imp.failed = true
+	imp.result.Failed++
+	for _, err := range errs {
+		if len(imp.result.Errors) >= imp.svc.importConfig.MaxErrors {
+			imp.result.ErrorsTruncated = true
+			return
+		}
+		imp.result.Errors = append(imp.result.Errors, err)
+	}
+}
+
+// commit 结束当前事务范围
+//   - dry run 只检查，不写入
+//   - chunk 模式：在独立事务中写入本范围的行，范围内存在失败的行时不写入或整体回滚，不影响其他范围
+//   - file 模式：写入外层事务，出现失败的行后不再写入，全部检查完成后整体回滚
+func (imp *demoImport) commit(ctx context.Context) error {
+	rows := imp.rows
+	imp.rows = nil
+	defer imp.progress()
+
+	fileMode := imp.opts.Transaction == importTxFile
+	if !fileMode {
+		defer func() { imp.failed = false }()
+	}
+	if imp.opts.DryRun || len(rows) == 0 {
+		return nil
+	}
+	if imp.failed {
+		if !fileMode {
+			imp.result.RolledBack += len(rows)
+		}
+		return nil
+	}
+
+	if fileMode {
+		counts, errs, err := imp.insert(ctx, rows)
+		if err != nil {
+			return err
+		}
+		imp.pending.created += counts.created
+		imp.pending.updated += counts.updated
+		imp.pending.skipped += counts.skipped
+		for _, e := range errs {
+			imp.fail(e)
+		}
+		return nil
+	}
+
+	var (
+		counts importCounts
+		errs   []dto.DemoImportError
+	)
+	err := imp.svc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		counts, errs, err = imp.insert(ctx, rows)
+		if err != nil {
+			return err
+		}
+		if len(errs) > 0 {
+			return errImportRolledBack
+		}
+		return nil
+	})
+	if errors.Is(err, errImportRolledBack) {
+		for _, e := range errs {
+			imp.fail(e)
+		}
+		imp.result.RolledBack += len(rows) - len(errs)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	imp.result.Created += counts.created
+	imp.result.Updated += counts.updated
+	imp.result.Skipped += counts.skipped
+	return nil
+}
+
+// insert 分批写入，返回写入结果计数和写入失败的行，重试时可重复调用
+func (imp *demoImport) insert(ctx context.Context, rows []demoImportRow) (importCounts, []dto.DemoImportError, error) {
+	demos := make([]*model.Demo, len(rows))
+	for i, row := range rows {
+		demos[i] = &model.Demo{Field1: row.req.Field1, Field2: row.req.Field2}
+	}
+	items, err := imp.svc.demoRepo.BatchCreateDemo(ctx, demos, baserepo.ConflictStrategy(imp.opts.OnConflict), imp.svc.batchConfig.ChunkSize)
+	if err != nil {
+		return importCounts{}, nil, err
+	}
+
+	var (
+		counts importCounts
+		errs   []dto.DemoImportError
+	)
+	for i, item := range items {
+		switch item.Status {
+		case baserepo.BatchCreated:
+			counts.created++
+		case baserepo.BatchUpdated:
+			counts.updated++
+		case baserepo.BatchSkipped:
+			counts.skipped++
+		default:
+			e := dto.DemoImportError{Line: rows[i].line, Code: utils.ErrCodeServerInternalError, Message: "error.internal"}
+			if bizErr, ok := utils.GetBusinessError(item.Err); ok {
+				e.Code, e.Message, e.Params = bizErr.Code, bizErr.Message, bizErr.Params
+			}
+			errs = append(errs, e)
+		}
+	}
+	imp.writeFailed += len(errs)
+	return counts, errs, nil
+}
+
+// progress 报告当前进度
+func (imp *demoImport) progress() {
+	if imp.report == nil {
+		return
+	}
+	progress := dto.DemoImportProgress{Rows: imp.result.Total, BytesRead: imp.counter.n, TotalBytes: imp.size}
+	if imp.size > 0 {
+		progress.Percent = math.Min(100, math.Round(float64(progress.BytesRead)*10000/float64(progress.TotalBytes))/100)
+	}
+	imp.report(progress)
+}
+
+// rowImportError 转换单行解码错误
+func rowImportError(err *importer.RowError) dto.DemoImportError {
+	e := dto.DemoImportError{Line: err.Line, Field: err.Field, Params: map[string]any{"field": err.Field}}
+	switch {
+	case errors.Is(err, importer.ErrInvalidValue):
+		e.Code, e.Message = utils.ErrCodeParamTypeError, "import.invalid_value"
+	case errors.Is(err, importer.ErrUnknownField):
+		e.Code, e.Message = utils.ErrCodeParamInvalid, "import.unknown_field"
+	default:
+		e.Code, e.Message = utils.ErrCodeDataFormatError, "import.malformed_row"
+	}
+	return e
+}
+
+// readImportError 转换无法继续读取的错误
+func readImportError(line int, err error) error {
+	var headerErr *importer.HeaderError
+	switch {
+	case errors.As(err, &headerErr) && errors.Is(err, importer.ErrDuplicateColumn):
+		return utils.NewBusinessErrorWithParams(utils.ErrCodeParamInvalid, "import.duplicate_column", map[string]any{"column": headerErr.Column})
+	case errors.As(err, &headerErr):
+		return utils.NewBusinessErrorWithParams(utils.ErrCodeParamInvalid, "import.unknown_column", map[string]any{"column": headerErr.Column})
+	case errors.Is(err, importer.ErrLineTooLong):
+		return utils.NewBusinessErrorWithParams(utils.ErrCodeDataFormatError, "import.line_too_long", map[string]any{"line": line})
+	default:
+		return utils.NewSystemError(fmt.Errorf("读取导入文件失败: %w", err))
+	}
+}
+
+// countingReader 统计已读取的字节数，用于计算导入进度
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// detachContext 返回不随请求结束而取消的上下文，保留租户、操作人以及请求标识和客户端 IP（审计日志中关联发起导入的请求），供异步任务使用
+// 不能直接沿用 *gin.Context：请求结束后它会被复用于其他请求
+func detachContext(ctx context.Context) context.Context {
+	detached := utils.WithRequest(context.Background(), utils.GetRequestId(ctx), utils.GetClientIP(ctx))
+	if id, ok := tenant.FromContext(ctx); ok {
+		detached = tenant.WithTenant(detached, id)
+	}
+	if tenant.IsElevated(ctx) {
+		detached = tenant.Elevate(detached)
+	}
+	if operator := utils.GetOperator(ctx); operator != "" {
+		detached = utils.WithOperator(detached, operator)
+	}
+	return detached
+}
+
+// removeTempFile 关闭并删除临时文件
+func removeTempFile(file *os.File) {
+	file.Close()
+	if err := os.Remove(file.Name()); err != nil {
+		logrus.WithError(err).WithField("file", file.Name()).Warn("删除导入临时文件失败")
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"gin-template/internal/app/config"
+	"gin-template/internal/app/database"
+	"gin-template/internal/demo/dto"
+	"gin-template/internal/demo/repository"
+	"gin-template/internal/utils"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newImportService 创建导入测试用的服务实例，dry run 不访问数据库
+func newImportService(t *testing.T, commitSize, maxErrors int) *DemoServiceImpl {
+	t.Helper()
+	db := openTestDB(t)
+	return &DemoServiceImpl{
+		demoRepo:     repository.NewDemoRepository(db, nil),
+		txManager:    database.NewTxManager(db, config.TransactionConfig{}),
+		batchConfig:  config.BatchConfig{ChunkSize: 100},
+		importConfig: config.ImportConfig{CommitSize: commitSize, MaxErrors: maxErrors},
+	}
+}
+
+func TestDemoImportValidation(t *testing.T) {
+	long := strings.Repeat("x", 256)
+	tests := []struct {
+		name      string
+		format    string
+		input     string
+		maxErrors int
+		want      *dto.DemoImportResult
+	}{
+		{
+			name:      "CSV 校验规则和取值类型",
+			format:    "csv",
+			input:     "field1,field2\n1,a\n2," + long + "\nx,b\n3,c,d\n",
+			maxErrors: 10,
+			want: &dto.DemoImportResult{DryRun: true, Total: 4, Valid: 1, Failed: 3, Errors: []dto.DemoImportError{
+				{Line: 3, Field: "field2", Code: utils.ErrCodeParamInvalid, Message: "validation.max", Params: map[string]any{"field": "field2", "param": "255"}},
+				{Line: 4, Field: "field1", Code: utils.ErrCodeParamTypeError, Message: "import.invalid_value", Params: map[string]any{"field": "field1"}},
+				{Line: 5, Code: utils.ErrCodeDataFormatError, Message: "import.malformed_row", Params: map[string]any{"field": ""}},
+			}},
+		},
+		{
+			name:      "NDJSON 未知字段",
+			format:    "ndjson",
+			input:     "{\"field1\":1}\n{\"field1\":2,\"field3\":1}\n",
+			maxErrors: 10,
+			want: &dto.DemoImportResult{DryRun: true, Total: 2, Valid: 1, Failed: 1, Errors: []dto.DemoImportError{
+				{Line: 2, Field: "field3", Code: utils.ErrCodeParamInvalid, Message: "import.unknown_field", Params: map[string]any{"field": "field3"}},
+			}},
+		},
+		{
+			name:      "超过 max_errors 的错误只计数",
+			format:    "ndjson",
+			input:     "{\"field1\":\"a\"}\n{\"field1\":\"b\"}\n{\"field1\":\"c\"}\n",
+			maxErrors: 2,
+			want: &dto.DemoImportResult{DryRun: true, Total: 3, Failed: 3, ErrorsTruncated: true, Errors: []dto.DemoImportError{
+				{Line: 1, Field: "field1", Code: utils.ErrCodeParamTypeError, Message: "import.invalid_value", Params: map[string]any{"field": "field1"}},
+				{Line: 2, Field: "field1", Code: utils.ErrCodeParamTypeError, Message: "import.invalid_value", Params: map[string]any{"field": "field1"}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newImportService(t, 100, tt.maxErrors)
+			opts := dto.DemoImportQuery{Format: tt.format, DryRun: true, OnConflict: "fail"}
+			got, err := svc.runImport(context.Background(), strings.NewReader(tt.input), int64(len(tt.input)), opts, nil)
+			if err != nil {
+				t.Fatalf("runImport() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("runImport() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDemoImportReadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		input   string
+		wantErr string
+	}{
+		{"不支持的格式", "xlsx", "", "import.unsupported_format"},
+		{"未知列", "csv", "field1,field3\n1,a\n", "import.unknown_column"},
+		{"重复列", "csv", "field1,FIELD1\n1,2\n", "import.duplicate_column"},
+		{"单行过长", "ndjson", strings.Repeat(" ", 1<<20+1) + "\n", "import.line_too_long"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newImportService(t, 100, 10)
+			opts := dto.DemoImportQuery{Format: tt.format, DryRun: true}
+			_, err := svc.runImport(context.Background(), strings.NewReader(tt.input), int64(len(tt.input)), opts, nil)
+			if bizErr, ok := utils.GetBusinessError(err); !ok || bizErr.Message != tt.wantErr {
+				t.Errorf("runImport() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDemoImportTransaction(t *testing.T) {
+	// 每 2 行一个事务范围，第二个范围中第 4 行校验失败
+	input := "field1,field2\n1,a\n2,b\n3,c\n4," + strings.Repeat("x", 256) + "\n5,e\n"
+	tests := []struct {
+		name        string
+		transaction string
+		wantResult  dto.DemoImportResult
+		wantRows    int64
+	}{
+		{
+			name:        "chunk 模式只回滚存在失败行的范围",
+			transaction: "chunk",
+			wantResult:  dto.DemoImportResult{Total: 5, Valid: 4, Failed: 1, Created: 3, RolledBack: 1},
+			wantRows:    3,
+		},
+		{
+			name:        "file 模式整体回滚",
+			transaction: importTxFile,
+			wantResult:  dto.DemoImportResult{Total: 5, Valid: 4, Failed: 1, RolledBack: 4},
+			wantRows:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newImportService(t, 2, 10)
+			var progress []int
+			opts := dto.DemoImportQuery{Format: "csv", OnConflict: "fail", Transaction: tt.transaction}
+			got, err := svc.runImport(context.Background(), strings.NewReader(input), int64(len(input)), opts, func(p dto.DemoImportProgress) {
+				progress = append(progress, p.Rows)
+			})
+			if err != nil {
+				t.Fatalf("runImport() error = %v", err)
+			}
+			if len(got.Errors) != 1 || got.Errors[0].Line != 5 {
+				t.Errorf("Errors = %+v, want 第 5 行", got.Errors)
+			}
+			got.Errors = nil
+			if !reflect.DeepEqual(*got, tt.wantResult) {
+				t.Errorf("runImport() = %+v, want %+v", *got, tt.wantResult)
+			}
+			if last := progress[len(progress)-1]; last != 5 {
+				t.Errorf("最后一次进度 Rows = %d, want 5", last)
+			}
+
+			_, total, err := svc.demoRepo.ListDemoPage(context.Background(), 1, 10, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != tt.wantRows {
+				t.Errorf("写入的行数 = %d, want %d", total, tt.wantRows)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gin-template/internal/demo/dto"
+	"gin-template/internal/utils"
+	"slices"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// PatchDemo 局部更新demo数据
+//   - 补丁作用于由当前数据生成的 dto.DemoPatchDocument，结果按其字段规则校验
+//   - 只有 dto.DemoPatchFields 中的字段可以修改，field2 为 null 或被移除时置为 NULL；唯一键 field1 不能为 null 或被移除
+//   - 没有实际变化的字段不更新，补丁未产生任何变化时版本号不变
+func (svc *DemoServiceImpl) PatchDemo(ctx context.Context, id int, contentType string, patch []byte, versions []int) (*dto.DemoUpdateResponse, error) {
+	resp := &dto.DemoUpdateResponse{}
+	err := svc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// 加锁读取当前数据并校验版本号，保证补丁作用于最新的数据
+		demo, err := svc.demoRepo.GetDemoForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if demo == nil {
+			return utils.NewBusinessError(utils.ErrCodeResourceNotFound, "error.not_found_or_deleted")
+		}
+		if len(versions) > 0 && !slices.Contains(versions, demo.Version) {
+			return utils.NewBusinessError(utils.ErrCodeVersionConflict, "error.version_conflict")
+		}
+
+		// 生成当前文档并应用补丁
+		original, err := json.Marshal(dto.DemoPatchDocument{Field1: &demo.Field1, Field2: &demo.Field2})
+		if err != nil {
+			return utils.NewSystemError(fmt.Errorf("生成待修改文档失败: %w", err))
+		}
+		patched, err := utils.ApplyPatch(contentType, original, patch)
+		if err != nil {
+			return err
+		}
+		updateFields, err := demoPatchUpdates(original, patched)
+		if err != nil {
+			return err
+		}
+		if len(updateFields) == 0 {
+			resp.Version = demo.Version
+			return nil
+		}
+
+		// 调用数据访问层方法更新数据并读取新版本号
+		if err := svc.demoRepo.UpdateDemo(ctx, id, versions, updateFields); err != nil {
+			return err
+		}
+		updated, err := svc.demoRepo.GetDemoByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		resp.Version = updated.Version
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// demoPatchUpdates 校验补丁结果并与原文档比较，返回发生变化的字段（数据库列 -> 新值）
+func demoPatchUpdates(original, patched []byte) (map[string]interface{}, error) {
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return nil, utils.WrapBusinessError(utils.ErrCodeDataFormatError, "patch.invalid", err)
+	}
+	for field := range after {
+		if _, ok := dto.DemoPatchFields[field]; !ok {
+			return nil, utils.NewBusinessErrorWithParams(utils.ErrCodeParamInvalid, "patch.field_not_allowed", map[string]any{"field": field})
+		}
+	}
+
+	// 按文档结构解码并校验
+	var doc dto.DemoPatchDocument
+	if err := json.Unmarshal(patched, &doc); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, utils.NewBusinessErrorWithParams(utils.ErrCodeParamTypeError, "patch.invalid_value", map[string]any{"field": typeErr.Field})
+		}
+		return nil, utils.WrapBusinessError(utils.ErrCodeDataFormatError, "patch.invalid", err)
+	}
+	// 唯一键 field1 为非空列，不能通过补丁置为 NULL
+	if doc.Field1 == nil {
+		return nil, utils.NewBusinessErrorWithParams(utils.ErrCodeParamInvalid, "patch.field_required", map[string]any{"field": "field1"})
+	}
+	if err := binding.Validator.ValidateStruct(&doc); err != nil {
+		return nil, utils.NewSystemError(fmt.Errorf("补丁结果校验失败: %w", err))
+	}
+
+	if err := json.Unmarshal(original, &before); err != nil {
+		return nil, utils.NewSystemError(fmt.Errorf("解析原文档失败: %w", err))
+	}
+	values := map[string]interface{}{
+		"field1": doc.Field1,
+		"field2": doc.Field2,
+	}
+	updateFields := make(map[string]interface{})
+	for field, column := range dto.DemoPatchFields {
+		if !bytes.Equal(before[field], after[field]) {
+			updateFields[column] = values[field]
+		}
+	}
+	return updateFields, nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"gin-template/internal/utils"
+	"reflect"
+	"testing"
+)
+
+func TestDemoPatchUpdates(t *testing.T) {
+	original := []byte(`{"field1":1,"field2":"a"}`)
+	field1, field2 := 2, "b"
+	tests := []struct {
+		name    string
+		patched string
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{"只更新变化的字段", `{"field1":2,"field2":"a"}`, map[string]interface{}{"field1": &field1}, ""},
+		{"没有变化", `{"field1":1,"field2":"a"}`, map[string]interface{}{}, ""},
+		{"field2 置为 NULL", `{"field1":1,"field2":null}`, map[string]interface{}{"field2": (*string)(nil)}, ""},
+		{"field2 被移除", `{"field1":1}`, map[string]interface{}{"field2": (*string)(nil)}, ""},
+		{"同时修改两个字段", `{"field1":2,"field2":"b"}`, map[string]interface{}{"field1": &field1, "field2": &field2}, ""},
+		{"field1 置为 null", `{"field1":null,"field2":"a"}`, nil, "patch.field_required"},
+		{"field1 被移除", `{"field2":"a"}`, nil, "patch.field_required"},
+		{"不允许修改的字段", `{"field1":1,"field2":"a","version":9}`, nil, "patch.field_not_allowed"},
+		{"取值类型错误", `{"field1":"x","field2":"a"}`, nil, "patch.invalid_value"},
+		{"补丁结果不是对象", `[1]`, nil, "patch.invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := demoPatchUpdates(original, []byte(tt.patched))
+			if tt.wantErr != "" {
+				bizErr, ok := utils.GetBusinessError(err)
+				if !ok || bizErr.Message != tt.wantErr {
+					t.Fatalf("demoPatchUpdates() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("demoPatchUpdates() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("demoPatchUpdates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,46 +2,101 @@ package service
 
 import (
 	"context"
+	"gin-template/internal/app/cache"
+	"gin-template/internal/app/config"
+	"gin-template/internal/app/database"
+	"gin-template/internal/app/jobs"
+	"gin-template/internal/app/query"
+	baserepo "gin-template/internal/app/repository"
+	"gin-template/internal/app/search"
 	"gin-template/internal/demo/dto"
 	"gin-template/internal/demo/model"
 	"gin-template/internal/demo/repository"
 	"gin-template/internal/utils"
+	"io"
+	"slices"
 )
 
 // DemoService 服务接口，定义服务应该提供的功能
 type DemoService interface {
-	// ListDemo 获取demo数据
-	ListDemo(ctx context.Context, req *dto.DemoListRequest) ([]*dto.DemoListResponse, error)
-	// ListDemoPage 分页查询demo数据
-	ListDemoPage(ctx context.Context, page, pageSize int) ([]*dto.DemoPageListResponse, int64, error)
+	// ListDemo 获取demo数据，spec 为附加的过滤和排序规格
+	ListDemo(ctx context.Context, req *dto.DemoListRequest, spec *query.Spec) ([]*dto.DemoListResponse, error)
+	// ListDemoPage 分页查询demo数据，spec 为附加的过滤和排序规格
+	ListDemoPage(ctx context.Context, page, pageSize int, spec *query.Spec) ([]*dto.DemoPageListResponse, int64, error)
+	// ListDemoCursor 游标分页查询demo数据，spec 为附加的过滤条件
+	ListDemoCursor(ctx context.Context, q baserepo.CursorQuery, spec *query.Spec) (*baserepo.CursorPage[dto.DemoPageListResponse], error)
+	// SearchDemo 按关键词全文检索demo数据，按相关度降序分页返回
+	SearchDemo(ctx context.Context, q search.Query, page, pageSize int) ([]*dto.DemoSearchResponse, int64, error)
+	// StatsDemo 按统计规格分组聚合demo数据，spec 为过滤条件；结果按租户和条件短时缓存
+	StatsDemo(ctx context.Context, agg *query.Aggregation, spec *query.Spec) (*dto.DemoStatsResponse, error)
+	// ExportDemo 按列表查询条件逐行导出demo数据，每行交给 fn 处理，fn 返回错误或 ctx 取消时停止
+	ExportDemo(ctx context.Context, req *dto.DemoListRequest, spec *query.Spec, fn func(*dto.DemoExportRow) error) error
 	// GetDemoByID 根据ID获取demo数据
 	GetDemoByID(ctx context.Context, id int) (*dto.DemoDetailResponse, error)
 	// CreateDemo 创建demo数据
 	CreateDemo(ctx context.Context, demo *dto.DemoCreateRequest) (*dto.DemoCreateResponse, error)
-	// BatchCreateDemo 批量创建demo数据
-	BatchCreateDemo(ctx context.Context, demos []*dto.DemoCreateRequest) error
-	// UpdateDemo 更新demo数据
-	UpdateDemo(ctx context.Context, id int, req dto.DemoUpdateRequest) error
-	// SoftDeleteDemo 软删除demo数据
-	SoftDeleteDemo(ctx context.Context, id int) error
-	// DeleteDemo 删除demo数据
-	DeleteDemo(ctx context.Context, id int) error
+	// BatchCreateDemo 分批创建demo数据，返回新建的ID和失败的记录
+	BatchCreateDemo(ctx context.Context, demos []*dto.DemoCreateRequest, opts dto.DemoBatchCreateQuery) (*dto.DemoBatchCreateResponse, error)
+	// UpsertDemo 按 field1 创建或更新demo数据
+	UpsertDemo(ctx context.Context, field1 int, req dto.DemoUpsertRequest) (*dto.DemoUpsertResponse, error)
+	// UpdateDemo 更新demo数据，versions 为期望的当前版本号（为空不校验），返回更新后的版本号
+	UpdateDemo(ctx context.Context, id int, req dto.DemoUpdateRequest, versions []int) (*dto.DemoUpdateResponse, error)
+	// PatchDemo 以 JSON Merge Patch / JSON Patch 局部更新demo数据，versions 为期望的当前版本号（为空不校验），返回更新后的版本号
+	PatchDemo(ctx context.Context, id int, contentType string, patch []byte, versions []int) (*dto.DemoUpdateResponse, error)
+	// SoftDeleteDemo 软删除demo数据，versions 为期望的当前版本号（为空不校验）
+	SoftDeleteDemo(ctx context.Context, id int, versions []int) error
+	// RestoreDemo 恢复已软删除的demo数据
+	RestoreDemo(ctx context.Context, id int) error
+	// DeleteDemo 删除demo数据，versions 为期望的当前版本号（为空不校验）
+	DeleteDemo(ctx context.Context, id int, versions []int) error
+	// BatchUpdateDemo 批量更新demo数据，返回各记录的处理结果
+	BatchUpdateDemo(ctx context.Context, req dto.DemoBatchUpdateRequest) (*dto.DemoBatchResponse, error)
+	// BatchDeleteDemo 批量删除demo数据，返回各记录的处理结果
+	BatchDeleteDemo(ctx context.Context, req dto.DemoBatchDeleteRequest) (*dto.DemoBatchResponse, error)
+	// ImportDemo 导入 CSV / NDJSON 文件中的demo数据，dry run 时只检查不写入；文件较大或要求异步时转为异步任务
+	ImportDemo(ctx context.Context, src io.Reader, size int64, opts dto.DemoImportQuery) (*dto.DemoImportResponse, error)
+	// GetDemoImportJob 查询异步导入任务的状态、进度和结果
+	GetDemoImportJob(ctx context.Context, id string) (*dto.DemoImportJobResponse, error)
+	// ListDemoVersions 分页查询demo的历史版本，按版本号倒序
+	ListDemoVersions(ctx context.Context, id, page, pageSize int) ([]*dto.DemoVersionResponse, int64, error)
+	// GetDemoVersion 获取demo的指定版本
+	GetDemoVersion(ctx context.Context, id, version int) (*dto.DemoVersionResponse, error)
+	// DiffDemoVersions 比较demo两个版本的字段差异
+	DiffDemoVersions(ctx context.Context, id int, req dto.DemoVersionDiffRequest) (*dto.DemoVersionDiffResponse, error)
+	// RestoreDemoVersion 将demo数据回滚到指定版本，versions 为期望的当前版本号（为空不校验），返回回滚后的版本号
+	RestoreDemoVersion(ctx context.Context, id, version int, versions []int) (*dto.DemoUpdateResponse, error)
 }
 
-// DemoServiceImpl 实现接口的具体结构体，持有数据访问层接口 Repository 的实例
+// DemoServiceImpl 实现接口的具体结构体，持有数据访问层接口 Repository、事务管理器的实例、批量操作和导入配置
 type DemoServiceImpl struct {
-	demoRepo repository.DemoRepository
+	demoRepo     repository.DemoRepository
+	versionRepo  repository.DemoVersionRepository
+	txManager    database.TxManager
+	batchConfig  config.BatchConfig
+	importConfig config.ImportConfig
+	importJobs   jobs.Manager // 异步导入任务
+	statsConfig  config.StatsConfig
+	statsCache   cache.Cache // 统计结果缓存
 }
 
 // NewDemoService 创建服务实例
-func NewDemoService(demoRepo repository.DemoRepository) DemoService {
-	return &DemoServiceImpl{demoRepo: demoRepo}
+func NewDemoService(demoRepo repository.DemoRepository, versionRepo repository.DemoVersionRepository, txManager database.TxManager, batchConfig config.BatchConfig, importConfig config.ImportConfig, importJobs jobs.Manager, statsConfig config.StatsConfig) DemoService {
+	return &DemoServiceImpl{
+		demoRepo:     demoRepo,
+		versionRepo:  versionRepo,
+		txManager:    txManager,
+		batchConfig:  batchConfig,
+		importConfig: importConfig,
+		importJobs:   importJobs,
+		statsConfig:  statsConfig,
+		statsCache:   cache.NewMemory(statsConfig.CacheTTL, statsConfig.CacheSize),
+	}
 }
 
 // ListDemo 获取demo数据
-func (svc *DemoServiceImpl) ListDemo(ctx context.Context, req *dto.DemoListRequest) ([]*dto.DemoListResponse, error) {
+func (svc *DemoServiceImpl) ListDemo(ctx context.Context, req *dto.DemoListRequest, spec *query.Spec) ([]*dto.DemoListResponse, error) {
 	// 调用数据访问层方法获取数据
-	demo, err := svc.demoRepo.ListDemo(ctx, req)
+	demo, err := svc.demoRepo.ListDemo(ctx, req, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -59,9 +114,9 @@ func (svc *DemoServiceImpl) ListDemo(ctx context.Context, req *dto.DemoListReque
 }
 
 // ListDemoPage 分页查询demo数据
-func (svc *DemoServiceImpl) ListDemoPage(ctx context.Context, page, pageSize int) ([]*dto.DemoPageListResponse, int64, error) {
+func (svc *DemoServiceImpl) ListDemoPage(ctx context.Context, page, pageSize int, spec *query.Spec) ([]*dto.DemoPageListResponse, int64, error) {
 	// 调用数据访问层方法获取数据
-	demo, total, err := svc.demoRepo.ListDemoPage(ctx, page, pageSize)
+	demo, total, err := svc.demoRepo.ListDemoPage(ctx, page, pageSize, spec)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -78,6 +133,75 @@ func (svc *DemoServiceImpl) ListDemoPage(ctx context.Context, page, pageSize int
 	return demoResp, total, nil
 }
 
+// SearchDemo 按关键词全文检索demo数据，关键词中没有可检索的词（如只有标点）时返回空结果
+func (svc *DemoServiceImpl) SearchDemo(ctx context.Context, q search.Query, page, pageSize int) ([]*dto.DemoSearchResponse, int64, error) {
+	demoResp := make([]*dto.DemoSearchResponse, 0)
+	if len(q.Terms()) == 0 {
+		return demoResp, 0, nil
+	}
+	searcher, err := svc.demoRepo.DemoSearcher(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	hits, total, err := svc.demoRepo.SearchDemo(ctx, searcher, q, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	// 转换为dto，高亮检索列
+	for _, hit := range hits {
+		demoResp = append(demoResp, &dto.DemoSearchResponse{
+			ID:        hit.Entity.ID,
+			Field1:    hit.Entity.Field1,
+			Field2:    hit.Entity.Field2,
+			Score:     hit.Score,
+			Highlight: map[string]string{"field2": searcher.Highlight(hit.Entity.Field2, q)},
+		})
+	}
+	return demoResp, total, nil
+}
+
+// ListDemoCursor 游标分页查询demo数据
+func (svc *DemoServiceImpl) ListDemoCursor(ctx context.Context, q baserepo.CursorQuery, spec *query.Spec) (*baserepo.CursorPage[dto.DemoPageListResponse], error) {
+	// 调用数据访问层方法获取数据
+	page, err := svc.demoRepo.ListDemoCursor(ctx, q, spec)
+	if err != nil {
+		return nil, err
+	}
+	// 转换为dto（领域模型 -> 数据传输对象），游标和总条数原样返回
+	demoResp := make([]*dto.DemoPageListResponse, 0, len(page.List))
+	for _, v := range page.List {
+		demoResp = append(demoResp, &dto.DemoPageListResponse{
+			ID:     v.ID,
+			Field1: v.Field1,
+			Field2: v.Field2,
+		})
+	}
+	return &baserepo.CursorPage[dto.DemoPageListResponse]{
+		List:      demoResp,
+		Next:      page.Next,
+		Prev:      page.Prev,
+		Total:     page.Total,
+		Estimated: page.Estimated,
+	}, nil
+}
+
+// ExportDemo 按列表查询条件逐行导出demo数据
+func (svc *DemoServiceImpl) ExportDemo(ctx context.Context, req *dto.DemoListRequest, spec *query.Spec, fn func(*dto.DemoExportRow) error) error {
+	// 调用数据访问层方法逐行读取，转换为dto（领域模型 -> 数据传输对象）后交给调用方写出
+	return svc.demoRepo.ExportDemo(ctx, req, spec, func(demo *model.Demo) error {
+		return fn(&dto.DemoExportRow{
+			ID:         demo.ID,
+			Field1:     demo.Field1,
+			Field2:     demo.Field2,
+			Version:    demo.Version,
+			CreateTime: demo.CreateTime,
+			UpdateTime: demo.UpdateTime,
+			CreatedBy:  demo.CreatedBy,
+			UpdatedBy:  demo.UpdatedBy,
+		})
+	})
+}
+
 // GetDemoByID 根据ID获取demo数据
 func (svc *DemoServiceImpl) GetDemoByID(ctx context.Context, id int) (*dto.DemoDetailResponse, error) {
 	// 调用数据访问层方法获取数据
@@ -87,9 +211,14 @@ func (svc *DemoServiceImpl) GetDemoByID(ctx context.Context, id int) (*dto.DemoD
 	}
 	// 转换为dto（领域模型 -> 数据传输对象）
 	demoResp := &dto.DemoDetailResponse{
-		ID:     demo.ID,
-		Field1: demo.Field1,
-		Field2: demo.Field2,
+		ID:         demo.ID,
+		Field1:     demo.Field1,
+		Field2:     demo.Field2,
+		Version:    demo.Version,
+		CreateTime: demo.CreateTime,
+		UpdateTime: demo.UpdateTime,
+		CreatedBy:  demo.CreatedBy,
+		UpdatedBy:  demo.UpdatedBy,
 	}
 	// 返回数据传输对象
 	return demoResp, nil
@@ -116,29 +245,34 @@ func (svc *DemoServiceImpl) CreateDemo(ctx context.Context, demo *dto.DemoCreate
 	return resp, nil
 }
 
-// BatchCreateDemo 批量创建demo数据
-func (svc *DemoServiceImpl) BatchCreateDemo(ctx context.Context, demos []*dto.DemoCreateRequest) error {
-	// 转换为数据模型（数据传输对象 -> 数据模型）
-	demoModels := make([]*model.Demo, 0, len(demos))
-	for _, demo := range demos {
-		// 1.业务逻辑
-
-		// 2.转换为数据模型
-		demoModels = append(demoModels, &model.Demo{
-			Field1: demo.Field1,
-			Field2: demo.Field2,
-		})
-	}
-	// 调用数据访问层方法批量创建数据
-	err := svc.demoRepo.BatchCreateDemo(ctx, demoModels)
+// UpsertDemo 按 field1 创建或更新demo数据
+func (svc *DemoServiceImpl) UpsertDemo(ctx context.Context, field1 int, req dto.DemoUpsertRequest) (*dto.DemoUpsertResponse, error) {
+	// 在事务中创建或更新并读取最新版本号
+	resp := &dto.DemoUpsertResponse{}
+	err := svc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		demo := &model.Demo{
+			Field1: field1,
+			Field2: req.Field2,
+		}
+		created, err := svc.demoRepo.UpsertDemo(ctx, demo)
+		if err != nil {
+			return err
+		}
+		current, err := svc.demoRepo.GetDemoByID(ctx, demo.ID)
+		if err != nil {
+			return err
+		}
+		resp.ID, resp.Created, resp.Version = current.ID, created, current.Version
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return resp, nil
 }
 
 // UpdateDemo 更新demo数据
-func (svc *DemoServiceImpl) UpdateDemo(ctx context.Context, id int, req dto.DemoUpdateRequest) error {
+func (svc *DemoServiceImpl) UpdateDemo(ctx context.Context, id int, req dto.DemoUpdateRequest, versions []int) (*dto.DemoUpdateResponse, error) {
 	// 构建更新字段
 	updateFields := make(map[string]interface{})
 	if req.Field1 != nil {
@@ -147,57 +281,75 @@ func (svc *DemoServiceImpl) UpdateDemo(ctx context.Context, id int, req dto.Demo
 	if req.Field2 != nil {
 		updateFields["field2"] = *req.Field2
 	}
-	// 调用数据访问层方法更新数据
-	if len(updateFields) > 0 {
-		err := svc.demoRepo.UpdateDemo(ctx, id, updateFields)
+	if len(updateFields) == 0 {
+		return nil, utils.NewBusinessError(utils.ErrCodeResourceNotFound, "error.no_update_fields")
+	}
+
+	// 在事务中按版本号条件更新并读取新版本号，保证返回的版本号就是本次更新产生的版本
+	resp := &dto.DemoUpdateResponse{}
+	err := svc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// 调用数据访问层方法更新数据，版本号不一致时返回版本冲突
+		if err := svc.demoRepo.UpdateDemo(ctx, id, versions, updateFields); err != nil {
+			return err
+		}
+		demo, err := svc.demoRepo.GetDemoByID(ctx, id)
 		if err != nil {
 			return err
 		}
-	} else {
-		return utils.NewBusinessError(utils.ErrCodeResourceNotFound, "无更新数据")
+		resp.Version = demo.Version
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return resp, nil
 }
 
 // SoftDeleteDemo 软删除demo数据
-func (svc *DemoServiceImpl) SoftDeleteDemo(ctx context.Context, id int) error {
-	// 检查数据是否存在
-	demo, err := svc.demoRepo.GetDemoByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	if demo == nil {
-		return utils.NewBusinessError(utils.ErrCodeResourceNotFound, "数据不存在或已被删除，请刷新后重试")
-	}
+func (svc *DemoServiceImpl) SoftDeleteDemo(ctx context.Context, id int, versions []int) error {
+	// 在事务中先加锁读取再删除，避免检查与删除之间数据被并发修改
+	return svc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// 检查数据是否存在及版本号是否一致
+		if err := svc.checkVersionForUpdate(ctx, id, versions); err != nil {
+			return err
+		}
 
-	// 构建更新数据，复用UpdateDemo方法
-	updateFields := map[string]interface{}{
-		"is_deleted": "Y",
-	}
+		// 调用数据访问层方法软删除数据
+		return svc.demoRepo.SoftDeleteDemo(ctx, id)
+	})
+}
 
-	// 调用数据访问层方法软删除数据
-	err = svc.demoRepo.UpdateDemo(ctx, id, updateFields)
-	if err != nil {
-		return err
-	}
-	return nil
+// RestoreDemo 恢复已软删除的demo数据
+func (svc *DemoServiceImpl) RestoreDemo(ctx context.Context, id int) error {
+	// 调用数据访问层方法恢复数据
+	return svc.demoRepo.RestoreDemo(ctx, id)
 }
 
 // DeleteDemo 删除demo数据
-func (svc *DemoServiceImpl) DeleteDemo(ctx context.Context, id int) error {
-	// 检查数据是否存在
-	demo, err := svc.demoRepo.GetDemoByID(ctx, id)
+func (svc *DemoServiceImpl) DeleteDemo(ctx context.Context, id int, versions []int) error {
+	// 在事务中先加锁读取再删除，避免检查与删除之间数据被并发修改
+	return svc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// 检查数据是否存在及版本号是否一致
+		if err := svc.checkVersionForUpdate(ctx, id, versions); err != nil {
+			return err
+		}
+
+		// 调用数据访问层方法删除数据
+		return svc.demoRepo.DeleteDemo(ctx, id)
+	})
+}
+
+// checkVersionForUpdate 加锁读取数据并校验版本号，需在事务中调用
+func (svc *DemoServiceImpl) checkVersionForUpdate(ctx context.Context, id int, versions []int) error {
+	demo, err := svc.demoRepo.GetDemoForUpdate(ctx, id)
 	if err != nil {
 		return err
 	}
 	if demo == nil {
-		return utils.NewBusinessError(utils.ErrCodeResourceNotFound, "数据不存在或已被删除，请刷新后重试")
+		return utils.NewBusinessError(utils.ErrCodeResourceNotFound, "error.not_found_or_deleted")
 	}
-
-	// 调用数据访问层方法删除数据
-	err = svc.demoRepo.DeleteDemo(ctx, id)
-	if err != nil {
-		return err
+	if len(versions) > 0 && !slices.Contains(versions, demo.Version) {
+		return utils.NewBusinessError(utils.ErrCodeVersionConflict, "error.version_conflict")
 	}
 	return nil
 }
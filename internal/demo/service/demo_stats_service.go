@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"gin-template/internal/app/query"
+	"gin-template/internal/app/tenant"
+	"gin-template/internal/demo/dto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatsDemo 按统计规格分组聚合demo数据
+// 相同租户、相同统计规格和过滤条件的结果在 stats.cache_ttl 内直接返回缓存，数据变更后在缓存有效期内可能返回旧结果
+func (svc *DemoServiceImpl) StatsDemo(ctx context.Context, agg *query.Aggregation, spec *query.Spec) (*dto.DemoStatsResponse, error) {
+	key := statsCacheKey(ctx, agg, spec)
+	if cached, ok := svc.statsCache.Get(key); ok {
+		return cached.(*dto.DemoStatsResponse), nil
+	}
+
+	rows, truncated, err := svc.demoRepo.StatsDemo(ctx, agg, spec, svc.statsConfig.MaxGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为dto，缓存的结果只读，不再修改
+	resp := &dto.DemoStatsResponse{
+		GroupBy:     make([]string, 0, len(agg.Groups)),
+		Metrics:     make([]string, 0, len(agg.Metrics)),
+		Rows:        make([]dto.DemoStatsRow, 0, len(rows)),
+		Truncated:   truncated,
+		GeneratedAt: time.Now(),
+	}
+	for _, group := range agg.Groups {
+		name := group.Field
+		if group.Interval != "" {
+			name += ":" + string(group.Interval)
+		}
+		resp.GroupBy = append(resp.GroupBy, name)
+	}
+	for _, metric := range agg.Metrics {
+		resp.Metrics = append(resp.Metrics, metric.Name())
+	}
+	for _, row := range rows {
+		resp.Rows = append(resp.Rows, dto.DemoStatsRow{Keys: row.Keys, Values: row.Values})
+	}
+
+	svc.statsCache.Set(key, resp)
+	return resp, nil
+}
+
+// statsCacheKey 统计结果的缓存键：租户（及是否跨租户）、统计规格和过滤条件
+func statsCacheKey(ctx context.Context, agg *query.Aggregation, spec *query.Spec) string {
+	id, _ := tenant.FromContext(ctx)
+	return strings.Join([]string{"demo", id, strconv.FormatBool(tenant.IsElevated(ctx)), agg.Key(), spec.FilterKey()}, "|")
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"gin-template/internal/demo/dto"
+	"gin-template/internal/demo/model"
+	"reflect"
+)
+
+// ListDemoVersions 分页查询demo的历史版本
+// 版本表不含租户字段，先按租户作用域查询demo数据，其他租户的数据返回 demo.not_found
+func (svc *DemoServiceImpl) ListDemoVersions(ctx context.Context, id, page, pageSize int) ([]*dto.DemoVersionResponse, int64, error) {
+	if _, err := svc.demoRepo.GetDemoByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	versions, total, err := svc.versionRepo.ListDemoVersions(ctx, id, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp := make([]*dto.DemoVersionResponse, 0, len(versions))
+	for _, v := range versions {
+		resp = append(resp, toDemoVersionResponse(v))
+	}
+	return resp, total, nil
+}
+
+// GetDemoVersion 获取demo的指定版本
+func (svc *DemoServiceImpl) GetDemoVersion(ctx context.Context, id, version int) (*dto.DemoVersionResponse, error) {
+	if _, err := svc.demoRepo.GetDemoByID(ctx, id); err != nil {
+		return nil, err
+	}
+	v, err := svc.versionRepo.GetDemoVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	return toDemoVersionResponse(v), nil
+}
+
+// DiffDemoVersions 比较demo两个版本的字段差异，只返回取值不同的字段
+func (svc *DemoServiceImpl) DiffDemoVersions(ctx context.Context, id int, req dto.DemoVersionDiffRequest) (*dto.DemoVersionDiffResponse, error) {
+	if _, err := svc.demoRepo.GetDemoByID(ctx, id); err != nil {
+		return nil, err
+	}
+	from, err := svc.versionRepo.GetDemoVersion(ctx, id, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := svc.versionRepo.GetDemoVersion(ctx, id, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	fromFields, toFields := demoVersionFields(from), demoVersionFields(to)
+	changes := make(map[string]dto.DemoFieldChange)
+	for field, value := range toFields {
+		if !reflect.DeepEqual(fromFields[field], value) {
+			changes[field] = dto.DemoFieldChange{From: fromFields[field], To: value}
+		}
+	}
+	return &dto.DemoVersionDiffResponse{From: req.From, To: req.To, Changes: changes}, nil
+}
+
+// RestoreDemoVersion 将demo数据回滚到指定版本
+// 在事务中加锁读取当前数据并校验版本号，按版本内容更新业务字段，回滚本身产生一个新版本；已软删除的数据需先恢复
+func (svc *DemoServiceImpl) RestoreDemoVersion(ctx context.Context, id, version int, versions []int) (*dto.DemoUpdateResponse, error) {
+	resp := &dto.DemoUpdateResponse{}
+	err := svc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// 检查数据是否存在及版本号是否一致
+		if err := svc.checkVersionForUpdate(ctx, id, versions); err != nil {
+			return err
+		}
+		target, err := svc.versionRepo.GetDemoVersion(ctx, id, version)
+		if err != nil {
+			return err
+		}
+
+		// 按版本内容更新，版本号自增
+		updateFields := map[string]interface{}{
+			"field1": target.Field1,
+			"field2": target.Field2,
+		}
+		if err := svc.demoRepo.UpdateDemo(ctx, id, nil, updateFields); err != nil {
+			return err
+		}
+		demo, err := svc.demoRepo.GetDemoByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		resp.Version = demo.Version
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// toDemoVersionResponse 转换为dto（数据模型 -> 数据传输对象）
+func toDemoVersionResponse(v *model.DemoVersion) *dto.DemoVersionResponse {
+	return &dto.DemoVersionResponse{
+		Version:    v.Version,
+		Field1:     v.Field1,
+		Field2:     v.Field2,
+		Deleted:    v.Deleted == "Y",
+		Operator:   v.Operator,
+		CreateTime: v.CreateTime,
+	}
+}
+
+// demoVersionFields 参与版本比较的字段
+func demoVersionFields(v *model.DemoVersion) map[string]any {
+	return map[string]any{
+		"field1":  v.Field1,
+		"field2":  v.Field2,
+		"deleted": v.Deleted == "Y",
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"gin-template/internal/app/config"
+	"gin-template/internal/app/tenant"
+	"gin-template/internal/demo/dto"
+	"gin-template/internal/demo/model"
+	"gin-template/internal/demo/repository"
+	"gin-template/internal/utils"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB 打开测试用的 SQLite 数据库（临时文件）并迁移demo相关的表
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	// SQLite 不支持 FULLTEXT 索引，迁移时改为普通索引
+	err = db.Callback().Raw().Before("gorm:raw").Register("test:fulltext_index", func(db *gorm.DB) {
+		if sql, ok := strings.CutPrefix(db.Statement.SQL.String(), "CREATE FULLTEXT INDEX"); ok {
+			db.Statement.SQL.Reset()
+			db.Statement.SQL.WriteString("CREATE INDEX" + sql)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&model.Demo{}, &model.DemoVersion{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestDemoVersionsTenantScope(t *testing.T) {
+	db := openTestDB(t)
+	if err := db.Use(tenant.NewPlugin(tenant.ModeColumn, "")); err != nil {
+		t.Fatal(err)
+	}
+
+	demoRepo := repository.NewDemoRepository(db, nil)
+	versionRepo := repository.NewDemoVersionRepository(db, config.VersionConfig{})
+	svc := &DemoServiceImpl{demoRepo: demoRepo, versionRepo: versionRepo}
+
+	// t1 的数据及两个版本
+	t1 := tenant.WithTenant(context.Background(), "t1")
+	demo := &model.Demo{Field1: 1, Field2: "a"}
+	if _, err := demoRepo.CreateDemo(t1, demo); err != nil {
+		t.Fatal(err)
+	}
+	second := *demo
+	second.Version, second.Field2 = 2, "b"
+	if err := versionRepo.SaveDemoVersions(t1, []*model.Demo{demo, &second}); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, total, err := svc.ListDemoVersions(t1, demo.ID, 1, 10)
+	if err != nil || total != 2 || len(versions) != 2 {
+		t.Fatalf("本租户 ListDemoVersions() = %d, %d, %v, want 2 条", len(versions), total, err)
+	}
+	if _, err := svc.GetDemoVersion(t1, demo.ID, 1); err != nil {
+		t.Fatalf("本租户 GetDemoVersion() error = %v", err)
+	}
+	diff, err := svc.DiffDemoVersions(t1, demo.ID, dto.DemoVersionDiffRequest{From: 1, To: 2})
+	if err != nil || len(diff.Changes) != 1 {
+		t.Fatalf("本租户 DiffDemoVersions() = %v, %v, want field2 变化", diff, err)
+	}
+
+	// 其他租户按 ID 访问返回数据不存在
+	t2 := tenant.WithTenant(context.Background(), "t2")
+	operations := map[string]func() error{
+		"ListDemoVersions": func() error {
+			_, _, err := svc.ListDemoVersions(t2, demo.ID, 1, 10)
+			return err
+		},
+		"GetDemoVersion": func() error {
+			_, err := svc.GetDemoVersion(t2, demo.ID, 1)
+			return err
+		},
+		"DiffDemoVersions": func() error {
+			_, err := svc.DiffDemoVersions(t2, demo.ID, dto.DemoVersionDiffRequest{From: 1, To: 2})
+			return err
+		},
+	}
+	for name, fn := range operations {
+		bizErr, ok := utils.GetBusinessError(fn())
+		if !ok || bizErr.Message != "demo.not_found" {
+			t.Errorf("其他租户 %s() error = %v, want demo.not_found", name, bizErr)
+		}
+	}
+}
@@ -16,20 +16,48 @@ import (
 //- err: 错误对象
 //- statusCode: HTTP状态码
 //- code: 业务错误码
-//- message: 用户友好的错误消息
+//- message: 用户友好的错误消息（消息键，按请求语言翻译；err 为业务异常时使用其消息模板参数）
 
 func RespondWithError(ctx *gin.Context, err error, statusCode int, code int, message string) {
-	// 记录错误日志，包含原始错误、HTTP状态码、业务错误码和提示信息
-	logrus.WithError(err).
-		WithField("status", statusCode).
-		WithField("errorCode", code).
-		Error(message)
+	// 业务异常的消息模板参数
+	var params map[string]any
+	if bizErr, ok := GetBusinessError(err); ok && bizErr.Message == message {
+		params = bizErr.Params
+	}
+
+	// 记录错误日志，包含完整的错误原因链、HTTP状态码、业务错误码和提示信息
+	// 原因链、调用栈和结构化字段只写入日志，不返回给客户端
+	entry := logrus.WithFields(logrus.Fields{
+		"error":     strings.Join(errorChain(err), " <- "),
+		"status":    statusCode,
+		"errorCode": code,
+	})
+	if stack := errorStackOf(err); len(stack) > 0 {
+		entry = entry.WithField("stack", stack)
+	}
+	for key, value := range errorFieldsOf(err) {
+		// 不覆盖已有的日志字段
+		if _, exists := entry.Data[key]; !exists {
+			entry = entry.WithField(key, value)
+		}
+	}
+	if ctx != nil {
+		entry = entry.WithField("requestId", ctx.GetString("requestId"))
+	}
+	entry.Error(translateDefault(ctx, message, params))
 
 	if ctx != nil {
+		// 客户端通过 Accept 请求头要求 RFC 7807 格式时，返回 problem+json
+		if wantsProblemJSON(ctx) {
+			respondWithProblem(ctx, err, statusCode, code, translate(ctx, message, params))
+			ctx.Abort() // 终止后续处理
+			return
+		}
+
 		// 向客户端返回JSON格式的错误响应
 		ctx.JSON(statusCode, Response{
 			Code:      code,
-			Message:   message,
+			Message:   translate(ctx, message, params),
 			Data:      nil,
 			RequestId: getRequestId(ctx),
 		})
@@ -40,21 +68,32 @@ func RespondWithError(ctx *gin.Context, err error, statusCode int, code int, mes
 // HandlerFunc 封装错误处理逻辑
 // 注意：调用后需手动添加 return 终止当前函数，避免后续代码执行
 func HandlerFunc(ctx *gin.Context, err error) {
-	// 处理业务错误
+	// 处理业务错误，HTTP 状态码由错误码决定（默认 400）
 	if bizErr, ok := GetBusinessError(err); ok {
-		RespondWithError(ctx, err, http.StatusBadRequest, bizErr.Code, bizErr.Message)
+		RespondWithError(ctx, err, HTTPStatusOf(bizErr.Code), bizErr.Code, bizErr.Message)
+		return
+	}
+
+	// 处理参数校验错误（控制器绑定参数失败时会包装为系统错误）
+	// problem+json 格式返回400及校验明细；默认的统一响应结构体保持原有行为，按系统错误返回500
+	if isValidationError(err) {
+		if ctx != nil && wantsProblemJSON(ctx) {
+			RespondWithError(ctx, err, http.StatusBadRequest, ErrCodeParamInvalid, "error.param_invalid")
+			return
+		}
+		RespondWithError(ctx, err, http.StatusInternalServerError, ErrCodeServerInternalError, "error.param_invalid")
 		return
 	}
 
-	// 处理系统错误
+	// 处理系统错误，传入完整错误链以便日志记录原因和调用栈
 	var sysErr *SystemError
 	if errors.As(err, &sysErr) {
-		RespondWithError(ctx, sysErr.Err, http.StatusInternalServerError, ErrCodeServerInternalError, err.Error())
+		RespondWithError(ctx, err, http.StatusInternalServerError, ErrCodeServerInternalError, "error.internal")
 		return
 	}
 
 	// 处理未知错误
-	RespondWithError(ctx, err, http.StatusInternalServerError, ErrCodeServerInternalError, "未知服务器错误")
+	RespondWithError(ctx, err, http.StatusInternalServerError, ErrCodeServerInternalError, "error.unknown")
 }
 
 // IsUniqueConstraintError 判断是否为唯一索引冲突错误
@@ -74,7 +113,20 @@ func IsUniqueConstraintError(err error) (bool, string, string) {
 		}
 	}
 
-	// 可以在这里添加其他数据库类型的判断，如PostgreSQL、SQLite等
+	// 适配PostgreSQL（SQLSTATE 23505）和SQLite，按错误信息识别，无需引入对应驱动
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) && stateErr.SQLState() == "23505" {
+		return true, "unknown", ""
+	}
+	if msg := err.Error(); strings.Contains(msg, "UNIQUE constraint failed: ") {
+		// 错误信息格式示例："UNIQUE constraint failed: users.username"
+		column := msg[strings.Index(msg, "UNIQUE constraint failed: ")+len("UNIQUE constraint failed: "):]
+		column, _, _ = strings.Cut(column, ",")
+		if i := strings.LastIndex(column, "."); i >= 0 {
+			column = column[i+1:]
+		}
+		return true, strings.TrimSpace(column), ""
+	}
 
 	return false, "", ""
 }
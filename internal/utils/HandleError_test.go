@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// validationError 返回参数校验失败的错误
+func validationError(t *testing.T) error {
+	t.Helper()
+	var req struct {
+		Name string `binding:"required"`
+	}
+	err := binding.Validator.ValidateStruct(&req)
+	if err == nil {
+		t.Fatal("ValidateStruct() error = nil")
+	}
+	return err
+}
+
+func TestHandlerFuncStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        func(t *testing.T) error
+		accept     string
+		wantStatus int
+		wantCode   int
+	}{
+		{"业务错误默认返回 400", func(*testing.T) error { return NewBusinessError(ErrCodeResourceNotFound, "x") }, "", http.StatusBadRequest, ErrCodeResourceNotFound},
+		{"业务错误按错误码映射状态码", func(*testing.T) error { return NewBusinessError(ErrCodeVersionConflict, "x") }, "", http.StatusConflict, ErrCodeVersionConflict},
+		{"参数校验失败默认按系统错误返回 500", func(t *testing.T) error { return NewSystemError(validationError(t)) }, "", http.StatusInternalServerError, ErrCodeServerInternalError},
+		{"参数校验失败 problem+json 返回 400", func(t *testing.T) error { return NewSystemError(validationError(t)) }, MIMEProblemJSON, http.StatusBadRequest, ErrCodeParamInvalid},
+		{"系统错误返回 500", func(*testing.T) error { return NewSystemError(errors.New("db down")) }, "", http.StatusInternalServerError, ErrCodeServerInternalError},
+		{"未知错误返回 500", func(*testing.T) error { return errors.New("boom") }, MIMEProblemJSON, http.StatusInternalServerError, ErrCodeServerInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/demo", nil)
+			ctx.Request.Header.Set("Accept", tt.accept)
+			ctx.Set("requestId", "r1")
+
+			HandlerFunc(ctx, tt.err(t))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var body struct {
+				Code int `json:"code"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("响应不是 JSON: %s", w.Body.String())
+			}
+			if body.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d", body.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OperatorKey 当前操作人在上下文中的键，由认证中间件通过 ctx.Set(utils.OperatorKey, ...) 写入
+const OperatorKey = "operator"
+
+// 请求标识和客户端 IP 在上下文中的键，由 RequestIdInject 中间件写入
+const (
+	RequestIdKey = "requestId"
+	ClientIPKey  = "clientIp"
+)
+
+// operatorContextKey 通过 WithOperator 写入的操作人在上下文中的键
+type operatorContextKey struct{}
+
+// requestIdContextKey、clientIPContextKey 通过 WithRequest 写入的请求标识和客户端 IP 在上下文中的键
+type (
+	requestIdContextKey struct{}
+	clientIPContextKey  struct{}
+)
+
+// WithOperator 返回携带操作人标识的上下文，用于异步任务等非 HTTP 请求场景
+func WithOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorContextKey{}, operator)
+}
+
+// GetOperator 获取当前操作人标识，未认证或非 HTTP 请求上下文中返回空字符串
+// 控制器直接传入 *gin.Context 作为 context.Context，其 Value 方法可以读取 ctx.Set 写入的值
+func GetOperator(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if operator, ok := ctx.Value(operatorContextKey{}).(string); ok {
+		return operator
+	}
+	operator, _ := ctx.Value(OperatorKey).(string)
+	return operator
+}
+
+// WithRequest 返回携带请求标识和客户端 IP 的上下文，用于异步任务沿用发起请求的关联信息（如审计日志）
+func WithRequest(ctx context.Context, requestId, clientIP string) context.Context {
+	ctx = context.WithValue(ctx, requestIdContextKey{}, requestId)
+	return context.WithValue(ctx, clientIPContextKey{}, clientIP)
+}
+
+// GetRequestId 获取当前请求标识，非 HTTP 请求上下文中返回空字符串
+func GetRequestId(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if requestId, ok := ctx.Value(requestIdContextKey{}).(string); ok {
+		return requestId
+	}
+	requestId, _ := ctx.Value(RequestIdKey).(string)
+	return requestId
+}
+
+// GetClientIP 获取当前请求的客户端 IP，非 HTTP 请求上下文中返回空字符串
+func GetClientIP(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if ip, ok := ctx.Value(clientIPContextKey{}).(string); ok {
+		return ip
+	}
+	ip, _ := ctx.Value(ClientIPKey).(string)
+	return ip
+}
+
+// RequestContext 返回随请求取消的上下文：取消信号和截止时间来自请求的上下文（客户端断开连接时取消），
+// 取值先从 gin.Context 读取（中间件通过 ctx.Set 写入的操作人、租户等），再从请求的上下文读取
+// 路由引擎未开启 ContextWithFallback，直接传入 *gin.Context 时查询不会随请求取消，需要时（如流式导出）使用该上下文
+func RequestContext(ctx *gin.Context) context.Context {
+	return requestContext{Context: ctx.Request.Context(), gin: ctx}
+}
+
+// requestContext 见 RequestContext
+type requestContext struct {
+	context.Context
+	gin *gin.Context
+}
+
+// Value 先读取 gin.Context 中的值，未找到时读取请求的上下文
+func (c requestContext) Value(key any) any {
+	if value := c.gin.Value(key); value != nil {
+		return value
+	}
+	return c.Context.Value(key)
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestContext(t *testing.T) {
+	type typedKey struct{}
+	base, cancel := context.WithCancel(context.WithValue(context.Background(), typedKey{}, "request"))
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("GET", "/", nil).WithContext(base)
+	ctx.Set(OperatorKey, "bob")
+
+	requestCtx := RequestContext(ctx)
+	if got := GetOperator(requestCtx); got != "bob" {
+		t.Errorf("GetOperator() = %q, want bob", got)
+	}
+	if got := requestCtx.Value(typedKey{}); got != "request" {
+		t.Errorf("Value(typedKey) = %v, want request", got)
+	}
+	if requestCtx.Err() != nil {
+		t.Fatalf("Err() = %v before cancel", requestCtx.Err())
+	}
+
+	// 请求的上下文取消（客户端断开连接）时随之取消
+	cancel()
+	select {
+	case <-requestCtx.Done():
+	default:
+		t.Fatal("Done() not closed after request context canceled")
+	}
+	if requestCtx.Err() != context.Canceled {
+		t.Errorf("Err() = %v, want context.Canceled", requestCtx.Err())
+	}
+}
@@ -1,22 +1,49 @@
 package utils
 
+import "net/http"
+
 // 错误码定义（按业务模块分类）
 const (
 	// 通用错误
-	ErrCodeSuccess         = 0     // 成功
-	ErrCodeParamInvalid    = 10001 // 参数验证失败
-	ErrCodeParamBind       = 10002 // 参数绑定失败
-	ErrCodeParamTypeError  = 10003 // 参数类型错误
-	ErrCodeParamOutOfRange = 10004 // 参数值超出合法范围
-	ErrCodeDataFormatError = 10005 // 数据格式错误（如 JSON/XML 格式解析失败）
+	ErrCodeSuccess              = 0     // 成功
+	ErrCodeParamInvalid         = 10001 // 参数验证失败
+	ErrCodeParamBind            = 10002 // 参数绑定失败
+	ErrCodeParamTypeError       = 10003 // 参数类型错误
+	ErrCodeParamOutOfRange      = 10004 // 参数值超出合法范围
+	ErrCodeDataFormatError      = 10005 // 数据格式错误（如 JSON/XML 格式解析失败）
+	ErrCodeUnsupportedMediaType = 10006 // 不支持的请求体类型（Content-Type）
+	ErrCodePayloadTooLarge      = 10007 // 请求体或上传文件超过大小上限
+	ErrCodeTooManyRequests      = 10008 // 请求过多（如异步任务队列已满），稍后重试
 
 	// 用户/权限相关
 	ErrCodePermissionDenied = 20001 // 权限不足（无访问该资源的权限）
+	ErrCodeTenantRequired   = 20002 // 缺少租户标识
+	ErrCodeTenantInvalid    = 20003 // 租户标识无效或未开通
 
 	// 资源相关
-	ErrCodeResourceNotFound = 30001 // 资源不存在（如查询的用户 ID / 订单 ID 不存在）
-	ErrCodeDuplicateKey     = 30002 // 重复键错误（如创建重复的用户名、订单号等）
+	ErrCodeResourceNotFound   = 30001 // 资源不存在（如查询的用户 ID / 订单 ID 不存在）
+	ErrCodeDuplicateKey       = 30002 // 重复键错误（如创建重复的用户名、订单号等）
+	ErrCodeVersionConflict    = 30003 // 版本冲突（数据已被他人修改，乐观锁校验失败）
+	ErrCodePreconditionFailed = 30004 // 前置条件不满足（If-Match 与当前版本不一致）
 
 	// 服务器/系统相关
 	ErrCodeServerInternalError = 50001 // 服务器内部错误（如代码异常、未捕获的异常）
 )
+
+// errCodeHTTPStatus 需要特定 HTTP 状态码的业务错误码，未登记的业务错误码返回 400
+var errCodeHTTPStatus = map[int]int{
+	ErrCodePermissionDenied:     http.StatusForbidden,
+	ErrCodeVersionConflict:      http.StatusConflict,
+	ErrCodePreconditionFailed:   http.StatusPreconditionFailed,
+	ErrCodeUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	ErrCodePayloadTooLarge:      http.StatusRequestEntityTooLarge,
+	ErrCodeTooManyRequests:      http.StatusTooManyRequests,
+}
+
+// HTTPStatusOf 返回业务错误码对应的 HTTP 状态码
+func HTTPStatusOf(code int) int {
+	if status, ok := errCodeHTTPStatus[code]; ok {
+		return status
+	}
+	return http.StatusBadRequest
+}
@@ -1,24 +1,74 @@
 // Package utils 用于自定义错误类型
 package utils
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// ErrorFields 错误附加的结构化字段（如 entity、id、operation），仅写入日志，不返回给客户端
+type ErrorFields map[string]any
 
 // BusinessError 业务异常（向用户展示,携带错误码和消息）
 // 程序中的逻辑错误，如用户输入错误、资源未找到等。向用户展示具体的错误信息。
 type BusinessError struct {
-	Code    int    // 自定义错误码（对应utils中的错误码）
-	Message string // 错误消息
+	Code    int            // 自定义错误码（对应utils中的错误码）
+	Message string         // 错误消息（消息键，响应时按请求语言翻译）
+	Params  map[string]any // 消息模板参数（可选）
+	Err     error          // 原始错误（可选，仅用于日志）
+	Fields  ErrorFields    // 结构化字段（可选，仅用于日志）
+	stack   []uintptr      // 创建时的调用栈
+	origin  error          // WithErrorFields 返回的副本对应的原错误
 }
 
 func (e *BusinessError) Error() string {
 	return e.Message
 }
 
+// Unwrap 返回原始错误，使 errors.Is / errors.As 可以穿透业务异常
+func (e *BusinessError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 WithErrorFields 返回的副本与原错误匹配，共享的错误值附加字段后仍可用 errors.Is 判断
+func (e *BusinessError) Is(target error) bool {
+	return e.origin != nil && e.origin == target
+}
+
+// StackTrace 返回创建业务异常时的调用栈（仅包含项目代码）
+func (e *BusinessError) StackTrace() []string {
+	return formatStack(e.stack)
+}
+
 // NewBusinessError 创建业务异常
 func NewBusinessError(code int, message string) error {
 	return &BusinessError{
 		Code:    code,
 		Message: message,
+		stack:   callers(),
+	}
+}
+
+// NewBusinessErrorWithParams 创建带消息模板参数的业务异常
+// 用法：utils.NewBusinessErrorWithParams(utils.ErrCodeDuplicateKey, "demo.field1_duplicate", map[string]any{"value": value})
+func NewBusinessErrorWithParams(code int, message string, params map[string]any) error {
+	return &BusinessError{
+		Code:    code,
+		Message: message,
+		Params:  params,
+		stack:   callers(),
+	}
+}
+
+// WrapBusinessError 创建携带原始错误的业务异常，原始错误只记录日志，不返回给客户端
+func WrapBusinessError(code int, message string, err error) error {
+	return &BusinessError{
+		Code:    code,
+		Message: message,
+		Err:     err,
+		stack:   callers(),
 	}
 }
 
@@ -33,7 +83,10 @@ func GetBusinessError(err error) (*BusinessError, bool) {
 // 程序中的系统错误，如数据库连接失败、服务器异常等。用于记录日志，向用户展示通用的错误信息。
 // 这些错误通常是由于系统故障或配置问题引起的，需要管理员干预解决。
 type SystemError struct {
-	Err error // 原始错误（用于日志）
+	Err    error       // 原始错误（用于日志）
+	Fields ErrorFields // 结构化字段（可选，仅用于日志）
+	stack  []uintptr   // 创建时的调用栈
+	origin error       // WithErrorFields 返回的副本对应的原错误
 }
 
 // 返回统一的错误消息
@@ -41,7 +94,154 @@ func (e *SystemError) Error() string {
 	return "服务器内部错误"
 }
 
+// Unwrap 返回原始错误，使 errors.Is(err, gorm.ErrRecordNotFound) 等判断可以穿透系统异常
+func (e *SystemError) Unwrap() error {
+	return e.Err
+}
+
+// Is 使 WithErrorFields 返回的副本与原错误匹配
+func (e *SystemError) Is(target error) bool {
+	return e.origin != nil && e.origin == target
+}
+
+// StackTrace 返回创建系统异常时的调用栈（仅包含项目代码）
+func (e *SystemError) StackTrace() []string {
+	return formatStack(e.stack)
+}
+
 // NewSystemError 创建系统异常
 func NewSystemError(err error) error {
-	return &SystemError{Err: err}
+	return &SystemError{
+		Err:   err,
+		stack: callers(),
+	}
+}
+
+// WithErrorFields 返回附加了结构化字段的错误，不修改 err 本身：共享的错误值（如包级变量）不会累积其他请求附加的字段
+// err 为 BusinessError / SystemError 时返回附加字段后的副本（errors.Is 与原错误匹配）；其他错误（包括经 fmt.Errorf 包装的自定义异常）
+// 包装为 SystemError 后再附加，日志中外层字段优先
+// 用法：return utils.WithErrorFields(err, utils.ErrorFields{"entity": "demo", "id": id, "operation": "update"})
+func WithErrorFields(err error, fields ErrorFields) error {
+	if err == nil {
+		return nil
+	}
+
+	switch typed := err.(type) {
+	case *BusinessError:
+		clone := *typed
+		clone.Fields = mergeErrorFields(typed.Fields, fields)
+		if clone.origin == nil {
+			clone.origin = typed
+		}
+		return &clone
+	case *SystemError:
+		clone := *typed
+		clone.Fields = mergeErrorFields(typed.Fields, fields)
+		if clone.origin == nil {
+			clone.origin = typed
+		}
+		return &clone
+	}
+
+	return &SystemError{
+		Err:    err,
+		Fields: mergeErrorFields(nil, fields),
+		stack:  callers(),
+	}
+}
+
+// mergeErrorFields 合并结构化字段到新的 map，src 覆盖 dst 中的同名字段，不修改 dst
+func mergeErrorFields(dst, src ErrorFields) ErrorFields {
+	merged := make(ErrorFields, len(dst)+len(src))
+	for k, v := range dst {
+		merged[k] = v
+	}
+	for k, v := range src {
+		merged[k] = v
+	}
+	return merged
+}
+
+// errorFieldsOf 收集错误链上所有自定义异常的结构化字段，外层字段优先
+func errorFieldsOf(err error) ErrorFields {
+	fields := ErrorFields{}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		var layer ErrorFields
+		switch typed := e.(type) {
+		case *BusinessError:
+			layer = typed.Fields
+		case *SystemError:
+			layer = typed.Fields
+		}
+		for k, v := range layer {
+			if _, exists := fields[k]; !exists {
+				fields[k] = v
+			}
+		}
+	}
+	return fields
+}
+
+// errorStackOf 返回错误链中最内层自定义异常的调用栈，即错误最初产生的位置
+func errorStackOf(err error) []string {
+	var stack []string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if tracer, ok := e.(interface{ StackTrace() []string }); ok {
+			if frames := tracer.StackTrace(); len(frames) > 0 {
+				stack = frames
+			}
+		}
+	}
+	return stack
+}
+
+// errorChain 按从外到内的顺序展开错误链，用于日志记录完整的错误原因
+// SystemError 的 Error() 为统一提示信息，不包含有效内容，因此跳过
+func errorChain(err error) []string {
+	var chain []string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch typed := e.(type) {
+		case *SystemError:
+			continue
+		case *BusinessError:
+			chain = append(chain, fmt.Sprintf("[%d] %s", typed.Code, typed.Message))
+		default:
+			chain = append(chain, e.Error())
+		}
+	}
+	return chain
+}
+
+// 调用栈最大深度
+const maxStackDepth = 32
+
+// 项目代码的包路径前缀，用于过滤调用栈中的框架和标准库代码
+const projectPackagePrefix = "gin-template/"
+
+// callers 获取调用方的调用栈
+// 跳过 runtime.Callers、callers 本身以及异常构造函数，从构造函数的调用方开始记录
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
+// formatStack 将调用栈格式化为 "函数名 文件:行号"，只保留项目代码所在的帧
+func formatStack(pcs []uintptr) []string {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	var lines []string
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if strings.HasPrefix(frame.Function, projectPackagePrefix) {
+			lines = append(lines, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	return lines
 }
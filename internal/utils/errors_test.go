@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestErrorUnwrap(t *testing.T) {
+	sysErr := NewSystemError(fmt.Errorf("查询失败: %w", gorm.ErrRecordNotFound))
+	bizErr := WrapBusinessError(ErrCodeResourceNotFound, "demo.not_found", sysErr)
+	wrapped := fmt.Errorf("service: %w", bizErr)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"系统异常", sysErr},
+		{"业务异常包装系统异常", bizErr},
+		{"fmt.Errorf 包装业务异常", wrapped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, gorm.ErrRecordNotFound) {
+				t.Error("errors.Is(err, gorm.ErrRecordNotFound) = false")
+			}
+			if errors.Is(tt.err, io.EOF) {
+				t.Error("errors.Is(err, io.EOF) = true")
+			}
+			var target *SystemError
+			if !errors.As(tt.err, &target) || target != sysErr {
+				t.Errorf("errors.As(*SystemError) = %v", target)
+			}
+		})
+	}
+
+	if got, ok := GetBusinessError(wrapped); !ok || got.Code != ErrCodeResourceNotFound {
+		t.Errorf("GetBusinessError() = %v, %v", got, ok)
+	}
+	if _, ok := GetBusinessError(sysErr); ok {
+		t.Error("系统异常 GetBusinessError() ok = true")
+	}
+	if (&BusinessError{Message: "x"}).Unwrap() != nil {
+		t.Error("无原始错误的业务异常 Unwrap() != nil")
+	}
+}
+
+func TestErrorChainAndStack(t *testing.T) {
+	err := WrapBusinessError(ErrCodeDuplicateKey, "demo.field1_duplicate", NewSystemError(errors.New("Duplicate entry")))
+
+	want := []string{fmt.Sprintf("[%d] demo.field1_duplicate", ErrCodeDuplicateKey), "Duplicate entry"}
+	if got := errorChain(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("errorChain() = %q, want %q", got, want)
+	}
+
+	stack := errorStackOf(err)
+	if len(stack) == 0 || !strings.Contains(stack[0], "TestErrorChainAndStack") {
+		t.Errorf("errorStackOf() = %q, want 首帧为测试函数", stack)
+	}
+	if stack := errorStackOf(errors.New("plain")); stack != nil {
+		t.Errorf("普通错误 errorStackOf() = %q", stack)
+	}
+}
+
+func TestWithErrorFields(t *testing.T) {
+	sentinel := NewBusinessError(ErrCodeResourceNotFound, "demo.not_found")
+	sysErr := NewSystemError(errors.New("db"))
+	plain := errors.New("plain")
+
+	tests := []struct {
+		name   string
+		err    error
+		fields ErrorFields
+		want   ErrorFields
+	}{
+		{"业务异常", sentinel, ErrorFields{"id": 1}, ErrorFields{"id": 1}},
+		{"系统异常", sysErr, ErrorFields{"entity": "demo"}, ErrorFields{"entity": "demo"}},
+		{"普通错误包装为系统异常", plain, ErrorFields{"id": 2}, ErrorFields{"id": 2}},
+		{"fmt.Errorf 包装的自定义异常", fmt.Errorf("x: %w", WithErrorFields(sentinel, ErrorFields{"id": 3, "op": "get"})), ErrorFields{"id": 4}, ErrorFields{"id": 4, "op": "get"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WithErrorFields(tt.err, tt.fields)
+			if !reflect.DeepEqual(errorFieldsOf(got), tt.want) {
+				t.Errorf("errorFieldsOf() = %v, want %v", errorFieldsOf(got), tt.want)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Error("errors.Is(got, err) = false")
+			}
+		})
+	}
+
+	// 同一个错误值多次附加字段互不影响，原错误不被修改
+	first := WithErrorFields(sentinel, ErrorFields{"id": 1})
+	second := WithErrorFields(sentinel, ErrorFields{"tenant": "acme"})
+	if fields := errorFieldsOf(sentinel); len(fields) != 0 {
+		t.Errorf("原错误被修改: %v", fields)
+	}
+	if fields := errorFieldsOf(second); !reflect.DeepEqual(fields, ErrorFields{"tenant": "acme"}) {
+		t.Errorf("第二次附加的字段 = %v", fields)
+	}
+	if bizErr, ok := GetBusinessError(first); !ok || bizErr.Code != ErrCodeResourceNotFound || bizErr.Message != "demo.not_found" {
+		t.Errorf("副本丢失错误码或消息: %v", bizErr)
+	}
+	// 在副本上继续附加，不影响副本本身
+	third := WithErrorFields(first, ErrorFields{"id": 9})
+	if fields := errorFieldsOf(first); !reflect.DeepEqual(fields, ErrorFields{"id": 1}) {
+		t.Errorf("副本被修改: %v", fields)
+	}
+	if fields := errorFieldsOf(third); !reflect.DeepEqual(fields, ErrorFields{"id": 9}) {
+		t.Errorf("覆盖同名字段 = %v", fields)
+	}
+
+	if !errors.Is(third, sentinel) || !errors.Is(fmt.Errorf("x: %w", third), sentinel) {
+		t.Error("副本 errors.Is(sentinel) = false")
+	}
+	if errors.Is(sentinel, first) {
+		t.Error("errors.Is(sentinel, 副本) = true")
+	}
+
+	if WithErrorFields(nil, ErrorFields{"id": 1}) != nil {
+		t.Error("WithErrorFields(nil) != nil")
+	}
+}
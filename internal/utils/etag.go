@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetVersionETag 将数据版本号作为强 ETag 写入响应头，如 ETag: "3"
+func SetVersionETag(ctx *gin.Context, version int) {
+	ctx.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// IfMatchVersions 解析 If-Match 请求头中的版本号
+//   - present: 是否携带了 If-Match
+//   - anyVersion: 是否为 "*"（匹配任意现存版本）
+//   - versions: 解析出的版本号；弱 ETag（W/"..."）和无法解析的值按 RFC 7232 强比较规则不匹配任何版本，被忽略
+//
+// 携带了 If-Match 但 versions 为空且 anyVersion 为 false 时，前置条件必然不满足
+func IfMatchVersions(ctx *gin.Context) (versions []int, anyVersion bool, present bool) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" {
+		return nil, false, false
+	}
+	if header == "*" {
+		return nil, true, true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		unquoted, err := strconv.Unquote(tag)
+		if err != nil {
+			continue
+		}
+		if version, err := strconv.Atoi(unquoted); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions, false, true
+}
@@ -0,0 +1,310 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// 局部更新请求体类型
+const (
+	MIMEMergePatch = "application/merge-patch+json" // JSON Merge Patch（RFC 7396）
+	MIMEJSONPatch  = "application/json-patch+json"  // JSON Patch（RFC 6902）
+)
+
+// jsonPatchOperation JSON Patch 的单个操作
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"` // 未提供时为 nil，显式的 null 为 "null"
+}
+
+// ApplyPatch 按请求体类型将补丁应用到 JSON 文档，返回修改后的文档
+// 不支持的请求体类型返回 415 对应的业务异常，补丁格式错误或无法应用时返回参数错误的业务异常
+func ApplyPatch(contentType string, doc, patch []byte) ([]byte, error) {
+	switch contentType {
+	case MIMEMergePatch:
+		return ApplyMergePatch(doc, patch)
+	case MIMEJSONPatch:
+		return ApplyJSONPatch(doc, patch)
+	default:
+		return nil, NewBusinessErrorWithParams(ErrCodeUnsupportedMediaType, "patch.unsupported_media_type", map[string]any{"type": contentType})
+	}
+}
+
+// ApplyMergePatch 应用 JSON Merge Patch：对象逐字段合并，值为 null 的字段被删除，其他类型的值整体替换
+func ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decodePatchJSON(doc)
+	if err != nil {
+		return nil, NewSystemError(fmt.Errorf("待修改的文档不是合法的 JSON: %w", err))
+	}
+	patchValue, err := decodePatchJSON(patch)
+	if err != nil {
+		return nil, WrapBusinessError(ErrCodeDataFormatError, "patch.invalid", err)
+	}
+	return marshalPatched(mergePatch(target, patchValue))
+}
+
+// mergePatch 按 RFC 7396 合并补丁
+func mergePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any, len(patchObject))
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
+
+// ApplyJSONPatch 应用 JSON Patch：按顺序执行 add / remove / replace / move / copy / test 操作，任一操作失败时整体失败
+func ApplyJSONPatch(doc, patch []byte) ([]byte, error) {
+	target, err := decodePatchJSON(doc)
+	if err != nil {
+		return nil, NewSystemError(fmt.Errorf("待修改的文档不是合法的 JSON: %w", err))
+	}
+	var operations []jsonPatchOperation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, WrapBusinessError(ErrCodeDataFormatError, "patch.invalid", err)
+	}
+
+	for _, operation := range operations {
+		if target, err = applyPatchOperation(target, operation); err != nil {
+			return nil, err
+		}
+	}
+	return marshalPatched(target)
+}
+
+// applyPatchOperation 执行单个 JSON Patch 操作
+func applyPatchOperation(doc any, operation jsonPatchOperation) (any, error) {
+	if operation.Path == nil {
+		return nil, NewBusinessError(ErrCodeDataFormatError, "patch.invalid")
+	}
+	path, err := parsePointer(*operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add", "replace", "test":
+		if operation.Value == nil {
+			return nil, NewBusinessError(ErrCodeDataFormatError, "patch.invalid")
+		}
+		value, err := decodePatchJSON(operation.Value)
+		if err != nil {
+			return nil, WrapBusinessError(ErrCodeDataFormatError, "patch.invalid", err)
+		}
+		if operation.Op == "test" {
+			current, ok := pointerGet(doc, path)
+			if !ok {
+				return nil, pathNotFound(*operation.Path)
+			}
+			if !jsonEqual(current, value) {
+				return nil, NewBusinessErrorWithParams(ErrCodeParamInvalid, "patch.test_failed", map[string]any{"path": *operation.Path})
+			}
+			return doc, nil
+		}
+		return pointerUpdate(doc, path, *operation.Path, operation.Op, value)
+	case "remove":
+		return pointerUpdate(doc, path, *operation.Path, "remove", nil)
+	case "move", "copy":
+		if operation.From == nil {
+			return nil, NewBusinessError(ErrCodeDataFormatError, "patch.invalid")
+		}
+		from, err := parsePointer(*operation.From)
+		if err != nil {
+			return nil, err
+		}
+		value, ok := pointerGet(doc, from)
+		if !ok {
+			return nil, pathNotFound(*operation.From)
+		}
+		if operation.Op == "move" {
+			// 不能移动到自身的子路径下
+			if strings.HasPrefix(*operation.Path, *operation.From+"/") {
+				return nil, NewBusinessError(ErrCodeDataFormatError, "patch.invalid")
+			}
+			if doc, err = pointerUpdate(doc, from, *operation.From, "remove", nil); err != nil {
+				return nil, err
+			}
+		} else {
+			// 复制一份，避免后续操作同时修改两处
+			if value, err = copyJSON(value); err != nil {
+				return nil, err
+			}
+		}
+		return pointerUpdate(doc, path, *operation.Path, "add", value)
+	default:
+		return nil, NewBusinessErrorWithParams(ErrCodeDataFormatError, "patch.unsupported_operation", map[string]any{"op": operation.Op})
+	}
+}
+
+// parsePointer 解析 JSON Pointer（RFC 6901），空字符串表示整个文档
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, NewBusinessError(ErrCodeDataFormatError, "patch.invalid")
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// pointerGet 读取指针指向的值
+func pointerGet(doc any, tokens []string) (any, bool) {
+	current := doc
+	for _, token := range tokens {
+		switch container := current.(type) {
+		case map[string]any:
+			value, ok := container[token]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(container) || strconv.Itoa(index) != token {
+				return nil, false
+			}
+			current = container[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// pointerUpdate 在指针指向的位置执行 add / replace / remove，返回修改后的文档
+// 数组插入或删除元素会产生新的切片，因此逐层返回修改后的值由上层重新赋值
+func pointerUpdate(doc any, tokens []string, pointer, op string, value any) (any, error) {
+	if len(tokens) == 0 {
+		// 操作整个文档
+		if op == "remove" {
+			return nil, nil
+		}
+		return value, nil
+	}
+
+	token, last := tokens[0], len(tokens) == 1
+	switch container := doc.(type) {
+	case map[string]any:
+		child, exists := container[token]
+		if !last {
+			if !exists {
+				return nil, pathNotFound(pointer)
+			}
+			updated, err := pointerUpdate(child, tokens[1:], pointer, op, value)
+			if err != nil {
+				return nil, err
+			}
+			container[token] = updated
+			return container, nil
+		}
+		if op != "add" && !exists {
+			return nil, pathNotFound(pointer)
+		}
+		if op == "remove" {
+			delete(container, token)
+		} else {
+			container[token] = value
+		}
+		return container, nil
+	case []any:
+		// add 允许以 "-" 或数组长度表示追加到末尾
+		limit := len(container)
+		if last && op == "add" {
+			if token == "-" {
+				token = strconv.Itoa(len(container))
+			}
+			limit++
+		}
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index >= limit || strconv.Itoa(index) != token {
+			return nil, pathNotFound(pointer)
+		}
+		if !last {
+			updated, err := pointerUpdate(container[index], tokens[1:], pointer, op, value)
+			if err != nil {
+				return nil, err
+			}
+			container[index] = updated
+			return container, nil
+		}
+		switch op {
+		case "add":
+			container = append(container[:index], append([]any{value}, container[index:]...)...)
+		case "remove":
+			container = append(container[:index], container[index+1:]...)
+		default:
+			container[index] = value
+		}
+		return container, nil
+	default:
+		return nil, pathNotFound(pointer)
+	}
+}
+
+// pathNotFound 补丁路径不存在的业务异常
+func pathNotFound(pointer string) error {
+	return NewBusinessErrorWithParams(ErrCodeParamInvalid, "patch.path_not_found", map[string]any{"path": pointer})
+}
+
+// decodePatchJSON 解码 JSON，数字保留为 json.Number 避免精度丢失
+func decodePatchJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("JSON 之后存在多余的内容")
+	}
+	return value, nil
+}
+
+// marshalPatched 序列化修改后的文档
+func marshalPatched(value any) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, NewSystemError(fmt.Errorf("补丁结果序列化失败: %w", err))
+	}
+	return data, nil
+}
+
+// copyJSON 深拷贝 JSON 值
+func copyJSON(value any) (any, error) {
+	data, err := marshalPatched(value)
+	if err != nil {
+		return nil, err
+	}
+	return decodePatchJSON(data)
+}
+
+// jsonEqual 按 JSON 语义比较两个值，数字按数值比较（1 与 1.0 相等）
+func jsonEqual(a, b any) bool {
+	normalize := func(value any) any {
+		data, _ := json.Marshal(value)
+		var result any
+		_ = json.Unmarshal(data, &result)
+		return result
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// compactJSON 规范化 JSON 文本（对象的键按字母顺序排列），用于比较补丁结果
+func compactJSON(t *testing.T, data string) string {
+	t.Helper()
+	var value any
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		t.Fatalf("无效的 JSON %q: %v", data, err)
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, normalized); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// businessMessage 返回业务异常的消息键，非业务异常返回空字符串
+func businessMessage(err error) string {
+	if bizErr, ok := GetBusinessError(err); ok {
+		return bizErr.Message
+	}
+	return ""
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	// RFC 7396 附录 A 中的示例
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"替换字段", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"新增字段", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"null 删除字段", `{"a":"b"}`, `{"a":null}`, `{}`},
+		{"只删除指定字段", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"数组整体替换", `{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"值替换为数组", `{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{"嵌套对象合并", `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{"数组中的对象不合并", `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{"补丁为数组", `["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{"对象替换为数组", `{"a":"b"}`, `["c"]`, `["c"]`},
+		{"补丁为 null", `{"a":"foo"}`, `null`, `null`},
+		{"补丁为字符串", `{"a":"foo"}`, `"bar"`, `"bar"`},
+		{"文档中已有的 null 保留", `{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{"非对象替换为对象", `[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{"新建嵌套对象", `{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyMergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ApplyMergePatch() error = %v", err)
+			}
+			if compactJSON(t, string(got)) != compactJSON(t, tt.want) {
+				t.Errorf("ApplyMergePatch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyMergePatchKeepsNumberPrecision(t *testing.T) {
+	got, err := ApplyMergePatch([]byte(`{"a":1}`), []byte(`{"a":9007199254740993}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"a":9007199254740993}`; string(got) != want {
+		t.Errorf("ApplyMergePatch() = %s, want %s", got, want)
+	}
+}
+
+func TestApplyMergePatchInvalid(t *testing.T) {
+	_, err := ApplyMergePatch([]byte(`{"a":1}`), []byte(`{"a":`))
+	if got := businessMessage(err); got != "patch.invalid" {
+		t.Errorf("error = %v, want patch.invalid", err)
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	// 大部分示例来自 RFC 6902 附录 A
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"add 对象成员", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"add 数组元素", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"add 以 - 追加到数组末尾", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{"add 以数组长度追加", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/1","value":"baz"}]`, `{"foo":["bar","baz"]}`},
+		{"add 已存在的成员时替换", `{"foo":"bar"}`, `[{"op":"add","path":"/foo","value":1}]`, `{"foo":1}`},
+		{"add null 值", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":null}]`, `{"baz":null,"foo":"bar"}`},
+		{"add 嵌套成员", `{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{"add 替换整个文档", `{"foo":"bar"}`, `[{"op":"add","path":"","value":{"a":1}}]`, `{"a":1}`},
+		{"remove 对象成员", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"remove 数组元素", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"replace", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"replace 数组元素", `{"foo":[1,2]}`, `[{"op":"replace","path":"/foo/0","value":3}]`, `{"foo":[3,2]}`},
+		{"move 对象成员", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move 数组元素", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"copy 后修改互不影响", `{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, `{"a":{"b":1},"c":{"b":2}}`},
+		{"test 通过", `{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+		{"test 数字按数值比较", `{"a":1}`, `[{"op":"test","path":"/a","value":1.0}]`, `{"a":1}`},
+		{"~1 转义斜杠", `{"a/b":1}`, `[{"op":"replace","path":"/a~1b","value":2}]`, `{"a/b":2}`},
+		{"~0 转义波浪号", `{"m~n":1}`, `[{"op":"remove","path":"/m~0n"}]`, `{}`},
+		{"~01 先解析 ~1 再解析 ~0", `{"~1":1}`, `[{"op":"replace","path":"/~01","value":2}]`, `{"~1":2}`},
+		{"空字符串成员", `{"":1}`, `[{"op":"replace","path":"/","value":2}]`, `{"":2}`},
+		{"按顺序执行多个操作", `{"a":[]}`, `[{"op":"add","path":"/a/-","value":1},{"op":"add","path":"/a/-","value":2},{"op":"remove","path":"/a/0"}]`, `{"a":[2]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyJSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ApplyJSONPatch() error = %v", err)
+			}
+			if compactJSON(t, string(got)) != compactJSON(t, tt.want) {
+				t.Errorf("ApplyJSONPatch() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyJSONPatchErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"补丁不是数组", `{}`, `{"op":"add"}`, "patch.invalid"},
+		{"缺少 path", `{}`, `[{"op":"add","value":1}]`, "patch.invalid"},
+		{"path 不以 / 开头", `{}`, `[{"op":"add","path":"a","value":1}]`, "patch.invalid"},
+		{"add 缺少 value", `{}`, `[{"op":"add","path":"/a"}]`, "patch.invalid"},
+		{"move 缺少 from", `{"a":1}`, `[{"op":"move","path":"/b"}]`, "patch.invalid"},
+		{"move 到自身的子路径", `{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/b/c"}]`, "patch.invalid"},
+		{"不支持的操作", `{}`, `[{"op":"merge","path":"/a","value":1}]`, "patch.unsupported_operation"},
+		{"remove 不存在的成员", `{"a":1}`, `[{"op":"remove","path":"/b"}]`, "patch.path_not_found"},
+		{"replace 不存在的成员", `{"a":1}`, `[{"op":"replace","path":"/b","value":1}]`, "patch.path_not_found"},
+		{"add 的父节点不存在", `{"a":1}`, `[{"op":"add","path":"/b/c","value":1}]`, "patch.path_not_found"},
+		{"数组下标越界", `{"a":[1]}`, `[{"op":"add","path":"/a/2","value":1}]`, "patch.path_not_found"},
+		{"数组下标有前导零", `{"a":[1,2]}`, `[{"op":"replace","path":"/a/01","value":1}]`, "patch.path_not_found"},
+		{"只有 add 支持 -", `{"a":[1]}`, `[{"op":"remove","path":"/a/-"}]`, "patch.path_not_found"},
+		{"copy 来源不存在", `{"a":1}`, `[{"op":"copy","from":"/b","path":"/c"}]`, "patch.path_not_found"},
+		{"test 路径不存在", `{"a":1}`, `[{"op":"test","path":"/b","value":1}]`, "patch.path_not_found"},
+		{"test 不通过", `{"a":1}`, `[{"op":"test","path":"/a","value":"1"}]`, "patch.test_failed"},
+		{"test 失败时之前的操作不生效", `{"a":1}`, `[{"op":"replace","path":"/a","value":2},{"op":"test","path":"/a","value":1}]`, "patch.test_failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyJSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if err == nil {
+				t.Fatalf("ApplyJSONPatch() = %s, want error %s", got, tt.want)
+			}
+			if msg := businessMessage(err); msg != tt.want {
+				t.Errorf("error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPatchContentType(t *testing.T) {
+	got, err := ApplyPatch(MIMEMergePatch, []byte(`{"a":1}`), []byte(`{"b":2}`))
+	if err != nil || compactJSON(t, string(got)) != `{"a":1,"b":2}` {
+		t.Errorf("merge patch = %s, %v", got, err)
+	}
+	got, err = ApplyPatch(MIMEJSONPatch, []byte(`{"a":1}`), []byte(`[{"op":"remove","path":"/a"}]`))
+	if err != nil || string(got) != `{}` {
+		t.Errorf("json patch = %s, %v", got, err)
+	}
+
+	_, err = ApplyPatch("application/json", []byte(`{}`), []byte(`{}`))
+	bizErr, ok := GetBusinessError(err)
+	if !ok || bizErr.Code != ErrCodeUnsupportedMediaType {
+		t.Fatalf("error = %v, want unsupported media type", err)
+	}
+	if HTTPStatusOf(bizErr.Code) != 415 {
+		t.Errorf("HTTPStatusOf() = %d, want 415", HTTPStatusOf(bizErr.Code))
+	}
+}
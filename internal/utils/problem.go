@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// MIMEProblemJSON RFC 7807 定义的错误响应媒体类型
+const MIMEProblemJSON = "application/problem+json"
+
+// ProblemDetails RFC 7807 错误响应结构体
+// 标准字段为 type/title/status/detail/instance，code 和 requestId 为本项目的扩展字段
+type ProblemDetails struct {
+	Type          string         `json:"type"`                     // 问题类型URI，无特定类型时为 about:blank
+	Title         string         `json:"title"`                    // 问题类型的简短描述（HTTP状态文本）
+	Status        int            `json:"status"`                   // HTTP状态码
+	Detail        string         `json:"detail,omitempty"`         // 本次错误的具体说明
+	Instance      string         `json:"instance,omitempty"`       // 发生错误的请求路径
+	Code          int            `json:"code"`                     // 业务错误码（扩展字段）
+	RequestId     string         `json:"requestId"`                // 请求ID（扩展字段）
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"` // 参数校验失败明细（扩展字段）
+}
+
+// InvalidParam 参数校验失败明细
+type InvalidParam struct {
+	Name   string `json:"name"`   // 参数名
+	Reason string `json:"reason"` // 失败原因
+}
+
+// wantsProblemJSON 根据 Accept 请求头判断客户端是否要求 problem+json 格式
+// 未声明或声明为 */* 时返回 false，默认仍使用统一响应结构体 Response
+func wantsProblemJSON(ctx *gin.Context) bool {
+	return ctx.NegotiateFormat(binding.MIMEJSON, MIMEProblemJSON) == MIMEProblemJSON
+}
+
+// respondWithProblem 以 RFC 7807 格式返回错误响应
+func respondWithProblem(ctx *gin.Context, err error, statusCode int, code int, message string) {
+	problem := ProblemDetails{
+		Type:          "about:blank",
+		Title:         http.StatusText(statusCode),
+		Status:        statusCode,
+		Detail:        message,
+		Instance:      ctx.Request.URL.Path,
+		Code:          code,
+		RequestId:     getRequestId(ctx),
+		InvalidParams: invalidParams(ctx, err),
+	}
+
+	// 先设置 Content-Type，gin 的 JSON 渲染不会覆盖已存在的 Content-Type
+	ctx.Header("Content-Type", MIMEProblemJSON+"; charset=utf-8")
+	ctx.JSON(statusCode, problem)
+}
+
+// invalidParams 从错误链中提取参数校验失败明细，非校验错误返回nil
+// 消息目录中存在 "validation.<校验规则>" 时使用翻译后的原因，否则（包括未使用 Locale 中间件时）使用校验器的原始描述
+func invalidParams(ctx *gin.Context, err error) []InvalidParam {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	translator, hasTranslator := getTranslator(ctx)
+	locale := getLocale(ctx)
+	params := make([]InvalidParam, 0, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		reason := fieldErr.Error()
+		if key := "validation." + fieldErr.Tag(); hasTranslator && translator.Has(locale, key) {
+			reason = translator.Translate(locale, key, map[string]any{
+				"field": fieldErr.Field(),
+				"param": fieldErr.Param(),
+			})
+		}
+		params = append(params, InvalidParam{
+			Name:   fieldErr.Field(),
+			Reason: reason,
+		})
+	}
+	return params
+}
+
+// isValidationError 判断错误链中是否包含参数校验错误
+func isValidationError(err error) bool {
+	var validationErrs validator.ValidationErrors
+	return errors.As(err, &validationErrs)
+}
@@ -22,23 +22,110 @@ type PageResponse struct {
 	List     any   `json:"list"`     // 当前页数据
 }
 
+// 游标分页响应结构体（扩展成功响应，用于游标分页列表接口）
+type CursorPageResponse struct {
+	List      any    `json:"list"`                // 当前页数据
+	Next      string `json:"next"`                // 下一页游标，为空表示没有下一页
+	Prev      string `json:"prev"`                // 上一页游标，为空表示没有上一页
+	Total     *int64 `json:"total,omitempty"`     // 总条数，未要求统计时不返回
+	Estimated bool   `json:"estimated,omitempty"` // 总条数是否为估算值
+}
+
 // 获取 RequestId
 func getRequestId(ctx *gin.Context) string {
 	requestId, _ := ctx.Get("requestId")
 	return requestId.(string)
 }
 
-// Success 通用成功响应
-// 参数：ctx（Gin 上下文）、message（提示信息）、data（业务数据）
-func Success(ctx *gin.Context, message string, data any) {
-	// 若 message 为空，默认填充 "success"
+// TranslatorKey 翻译器在上下文中的键，由 Locale 中间件写入
+const TranslatorKey = "translator"
+
+// Translator 消息翻译器（如 i18n.Bundle），由 Locale 中间件写入上下文，utils 不依赖全局的消息目录
+type Translator interface {
+	// DefaultLocale 返回默认语言
+	DefaultLocale() string
+	// Has 判断指定语言或默认语言中是否存在消息键
+	Has(locale, key string) bool
+	// Translate 翻译消息键，不存在时原样返回消息键
+	Translate(locale, key string, params map[string]any) string
+}
+
+// 获取本次请求使用的语言（由 Locale 中间件写入，未设置时翻译会回退到默认语言）
+func getLocale(ctx *gin.Context) string {
+	return ctx.GetString("locale")
+}
+
+// getTranslator 获取 Locale 中间件写入的翻译器
+func getTranslator(ctx *gin.Context) (Translator, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	value, _ := ctx.Get(TranslatorKey)
+	translator, ok := value.(Translator)
+	return translator, ok
+}
+
+// translate 按本次请求的语言翻译消息键，未使用 Locale 中间件时原样返回
+func translate(ctx *gin.Context, key string, params map[string]any) string {
+	translator, ok := getTranslator(ctx)
+	if !ok {
+		return key
+	}
+	return translator.Translate(getLocale(ctx), key, params)
+}
+
+// translateDefault 按默认语言翻译消息键，用于日志；未使用 Locale 中间件时原样返回
+func translateDefault(ctx *gin.Context, key string, params map[string]any) string {
+	translator, ok := getTranslator(ctx)
+	if !ok {
+		return key
+	}
+	return translator.Translate(translator.DefaultLocale(), key, params)
+}
+
+// Translate 按本次请求的语言翻译消息键，用于响应体之外的文本（如导出文件的表头）
+func Translate(ctx *gin.Context, key string, params map[string]any) string {
+	return translate(ctx, key, params)
+}
+
+// successMessage 返回成功响应的提示信息：message 为空时使用默认消息键 key；
+// 未使用 Locale 中间件时没有翻译器，默认提示信息保持为 "success"
+func successMessage(ctx *gin.Context, message, key string) string {
 	if message == "" {
-		message = "success"
+		if _, ok := getTranslator(ctx); !ok {
+			return "success"
+		}
+		message = key
 	}
+	return translate(ctx, message, nil)
+}
 
+// Success 通用成功响应
+// 参数：ctx（Gin 上下文）、message（提示信息的消息键，如 "common.fetch_success"）、data（业务数据）
+func Success(ctx *gin.Context, message string, data any) {
 	ctx.JSON(http.StatusOK, Response{
 		Code:      200, // 成功业务码固定为 200
-		Message:   message,
+		Message:   successMessage(ctx, message, "common.success"),
+		Data:      data,
+		RequestId: getRequestId(ctx),
+	})
+}
+
+// SuccessCreated 资源创建成功响应，HTTP 状态码为 201
+func SuccessCreated(ctx *gin.Context, message string, data any) {
+	ctx.JSON(http.StatusCreated, Response{
+		Code:      200,
+		Message:   successMessage(ctx, message, "common.create_success"),
+		Data:      data,
+		RequestId: getRequestId(ctx),
+	})
+}
+
+// SuccessAccepted 请求已受理、将在后台处理的响应（如异步任务），HTTP 状态码为 202
+func SuccessAccepted(ctx *gin.Context, message string, data any) {
+	ctx.JSON(http.StatusAccepted, Response{
+		Code:      200,
+		Message:   successMessage(ctx, message, "common.success"),
 		Data:      data,
 		RequestId: getRequestId(ctx),
 	})
@@ -58,3 +145,9 @@ func SuccessPage(ctx *gin.Context, message string, total int64, page, pageSize i
 	// 复用通用成功响应，将分页数据作为 Data 传入
 	Success(ctx, message, pageData)
 }
+
+// SuccessCursorPage 游标分页成功响应
+// 参数：ctx、message（提示信息的消息键）、page（游标分页数据）
+func SuccessCursorPage(ctx *gin.Context, message string, page CursorPageResponse) {
+	Success(ctx, message, page)
+}
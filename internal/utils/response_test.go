@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testTranslator 测试用的翻译器，只登记 common.* 消息
+type testTranslator map[string]string
+
+func (t testTranslator) DefaultLocale() string { return "zh-CN" }
+
+func (t testTranslator) Has(locale, key string) bool {
+	_, ok := t[key]
+	return ok
+}
+
+func (t testTranslator) Translate(locale, key string, params map[string]any) string {
+	if msg, ok := t[key]; ok {
+		return locale + ":" + msg
+	}
+	return key
+}
+
+func TestSuccessMessage(t *testing.T) {
+	translator := testTranslator{"common.success": "成功", "common.create_success": "创建成功", "common.fetch_success": "查询成功"}
+	tests := []struct {
+		name       string
+		translator Translator
+		respond    func(ctx *gin.Context)
+		want       string
+	}{
+		{"未使用 Locale 中间件时默认为 success", nil, func(ctx *gin.Context) { Success(ctx, "", nil) }, "success"},
+		{"未使用 Locale 中间件时原样返回", nil, func(ctx *gin.Context) { Success(ctx, "common.fetch_success", nil) }, "common.fetch_success"},
+		{"未使用 Locale 中间件时创建成功默认为 success", nil, func(ctx *gin.Context) { SuccessCreated(ctx, "", nil) }, "success"},
+		{"默认消息键", translator, func(ctx *gin.Context) { Success(ctx, "", nil) }, "en-US:成功"},
+		{"创建成功的默认消息键", translator, func(ctx *gin.Context) { SuccessCreated(ctx, "", nil) }, "en-US:创建成功"},
+		{"指定消息键", translator, func(ctx *gin.Context) { SuccessPage(ctx, "common.fetch_success", 0, 1, 10, nil) }, "en-US:查询成功"},
+		{"未登记的文本原样返回", translator, func(ctx *gin.Context) { Success(ctx, "done", nil) }, "done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			ctx.Set("requestId", "r1")
+			if tt.translator != nil {
+				ctx.Set("locale", "en-US")
+				ctx.Set(TranslatorKey, tt.translator)
+			}
+
+			tt.respond(ctx)
+
+			var body Response
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Message != tt.want {
+				t.Errorf("message = %q, want %q", body.Message, tt.want)
+			}
+			if body.RequestId != "r1" || body.Code != 200 {
+				t.Errorf("requestId = %q, code = %d", body.RequestId, body.Code)
+			}
+		})
+	}
+}